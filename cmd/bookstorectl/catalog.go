@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+func runCatalog(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing catalog command, see -h")
+	}
+
+	switch command, args := args[0], args[1:]; command {
+	case "export":
+		flags := flag.NewFlagSet("catalog export", flag.ExitOnError)
+		path := flags.String("file", "", "write to this file instead of stdout")
+		_ = flags.Parse(args)
+
+		var w io.Writer = os.Stdout
+		if *path != "" {
+			file, err := os.Create(*path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			w = file
+		}
+		return a.BookUseCase.ExportCatalog(ctx, w)
+
+	case "import":
+		flags := flag.NewFlagSet("catalog import", flag.ExitOnError)
+		dryRun := flags.Bool("dry-run", false, "validate and count the changes without saving them")
+		_ = flags.Parse(args)
+		path, err := oneArg(flags.Args(), "FILE")
+		if err != nil {
+			return err
+		}
+
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		result, err := a.BookUseCase.ImportCatalog(ctx, r, *dryRun)
+		if err != nil {
+			return err
+		}
+
+		rows := [][]string{{
+			strconv.Itoa(result.Created), strconv.Itoa(result.Updated), strconv.Itoa(result.Unchanged),
+			strconv.Itoa(result.CategoriesCreated), strconv.FormatBool(result.DryRun),
+		}}
+		if err := a.Out.Print(result, []string{"CREATED", "UPDATED", "UNCHANGED", "CATEGORIES CREATED", "DRY RUN"}, rows); err != nil {
+			return err
+		}
+		if len(result.Errors) == 0 {
+			return nil
+		}
+		if a.Out.Format == "table" {
+			for _, rowError := range result.Errors {
+				fmt.Fprintf(os.Stderr, "line %d: %s\n", rowError.Line, rowError.Message)
+			}
+		}
+		return fmt.Errorf("%d invalid rows, nothing was imported", len(result.Errors))
+
+	default:
+		return fmt.Errorf("unknown catalog command %q", command)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fathirarya/online-bookstore-api/db/migrations"
+	"github.com/fathirarya/online-bookstore-api/internal/config"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const usage = `usage: bookstorectl [-o table|json] <command> [flags] [args]
+
+users:
+  users create -name NAME -email EMAIL [-password PW] [-admin]
+  users promote EMAIL             make the user an admin
+  users demote EMAIL              make the admin a customer again
+  users disable EMAIL             block login and revoke every refresh token
+  users enable EMAIL
+  users reset-password [-password PW] EMAIL
+                                  without -password a random password is generated and printed
+
+orders:
+  orders list [-status S] [-user-id N] [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-page N] [-size N]
+  orders cancel [-reason TEXT] [-admin EMAIL] ID...
+                                  cancel PENDING orders, recorded as changed by the admin
+  orders expire                   run the order-expiry job once
+
+catalog:
+  catalog export [-file PATH]     write every book as CSV (default stdout)
+  catalog import [-dry-run] FILE  create or update books from CSV, - reads stdin
+
+stats                             print book and order statistics
+`
+
+// app berisi use case yang sama dengan server, dirangkai tanpa HTTP dan job scheduler
+type app struct {
+	Config         *viper.Viper
+	Log            *logrus.Logger
+	Out            *printer
+	UserRepository *repository.UserRepository
+	UserUseCase    *usecase.UserUseCase
+	OrderUseCase   *usecase.OrderUseCase
+	BookUseCase    *usecase.BookUseCase
+	OrderCronJob   *usecase.OrderCronJob
+}
+
+// bookstorectl menjalankan tugas operasional langsung ke database lewat use case yang sama
+// dengan server, sebagai ganti SQL manual. Dijalankan dari cmd/bookstorectl supaya .env terbaca.
+//
+//	go run . users reset-password admin@example.com
+//	go run . -o json orders list -status PENDING
+func main() {
+	output := flag.String("o", "table", "output format: table or json")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		log.Fatalf("invalid -o %q: must be table or json", *output)
+	}
+
+	commands := map[string]func(ctx context.Context, a *app, args []string) error{
+		"users":   runUsers,
+		"orders":  runOrders,
+		"catalog": runCatalog,
+		"stats":   runStats,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := command(ctx, newApp(*output), args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func newApp(output string) *app {
+	viperConfig := config.NewViper()
+	logger := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, logger)
+	validate := config.NewValidator(viperConfig)
+
+	if config.IsInMemory(viperConfig) {
+		log.Fatalf("DB_NAME=:memory: has no data outside the server process")
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := migrator.Verify(); err != nil {
+		log.Fatalf("database schema is not up to date, run cmd/migrate first: %v", err)
+	}
+
+	viperConfig.SetDefault("CURRENCY", money.DefaultCurrency)
+	if err := money.SetCurrency(viperConfig.GetString("CURRENCY")); err != nil {
+		log.Fatalf("invalid CURRENCY: %v", err)
+	}
+
+	userRepository := repository.NewUserRepository(db, logger)
+	categoryRepository := repository.NewCategoryRepository(db, logger)
+	bookRepository := repository.NewBookRepository(db, logger)
+	orderRepository := repository.NewOrderRepository(db, logger)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(db, logger)
+	revokedTokenRepository := repository.NewRevokedTokenRepository(db, logger)
+	stockMovementRepository := repository.NewStockMovementRepository(db, logger)
+	orderStatusHistoryRepository := repository.NewOrderStatusHistoryRepository(db, logger)
+	paymentRepository := repository.NewPaymentRepository(db, logger)
+	couponRepository := repository.NewCouponRepository(db, logger)
+	orderNoteRepository := repository.NewOrderNoteRepository(db, logger)
+	outboxRepository := repository.NewOutboxRepository(db, logger)
+
+	orderRules := config.NewOrderRules(viperConfig)
+	paymentProvider := config.NewPaymentProvider(viperConfig, logger)
+	// Tanpa replica, perintah operasional harus melihat data terbaru
+	readReplicas := repository.NewReadReplicas(db, logger)
+
+	orderStateMachine := usecase.NewOrderStateMachine(logger, orderRepository, orderStatusHistoryRepository, bookRepository,
+		stockMovementRepository, paymentRepository, paymentProvider, couponRepository, outboxRepository)
+
+	return &app{
+		Config:         viperConfig,
+		Log:            logger,
+		Out:            &printer{Format: output, Writer: os.Stdout},
+		UserRepository: userRepository,
+		UserUseCase:    usecase.NewUserUseCase(db, logger, userRepository, refreshTokenRepository, revokedTokenRepository),
+		OrderUseCase: usecase.NewOrderUseCase(db, logger, validate, orderRepository, bookRepository, stockMovementRepository,
+			orderStatusHistoryRepository, orderStateMachine, couponRepository, orderNoteRepository, outboxRepository, orderRules,
+			config.NewLowStockThreshold(viperConfig)),
+		BookUseCase: usecase.NewBookUseCase(db, readReplicas, logger, validate, bookRepository, categoryRepository,
+			stockMovementRepository, outboxRepository, config.NewImageStore(viperConfig), config.NewImageOptions(viperConfig)),
+		OrderCronJob: usecase.NewOrderCronJob(db, logger, orderRepository, orderStateMachine, orderRules),
+	}
+}
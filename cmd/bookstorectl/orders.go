@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func runOrders(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing orders command, see -h")
+	}
+
+	switch command, args := args[0], args[1:]; command {
+	case "list":
+		flags := flag.NewFlagSet("orders list", flag.ExitOnError)
+		req := &model.AdminListOrdersRequest{}
+		flags.StringVar(&req.Status, "status", "", "only orders with this status")
+		flags.IntVar(&req.UserID, "user-id", 0, "only orders of this customer")
+		flags.StringVar(&req.DateFrom, "from", "", "created on or after YYYY-MM-DD")
+		flags.StringVar(&req.DateTo, "to", "", "created on or before YYYY-MM-DD")
+		flags.IntVar(&req.Page, "page", 1, "page number")
+		flags.IntVar(&req.Size, "size", 20, "orders per page, max 100")
+		_ = flags.Parse(args)
+
+		orders, page, size, totalItems, totalPages, err := a.OrderUseCase.ListAllOrders(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, 0, len(orders.Orders))
+		for _, order := range orders.Orders {
+			rows = append(rows, []string{
+				strconv.Itoa(order.ID), strconv.Itoa(order.UserID), order.Status,
+				order.TotalPrice.String(), order.Currency, formatTime(&order.CreatedAt),
+			})
+		}
+		if err := a.Out.Print(&model.WebResponse[[]model.OrderResponse]{
+			Page: page, Size: size, TotalItems: totalItems, TotalPages: totalPages, Data: orders.Orders,
+		}, []string{"ID", "USER", "STATUS", "TOTAL", "CURRENCY", "CREATED"}, rows); err != nil {
+			return err
+		}
+		if a.Out.Format == "table" {
+			fmt.Fprintf(a.Out.Writer, "page %d of %d, %d orders\n", page, totalPages, totalItems)
+		}
+		return nil
+
+	case "cancel":
+		flags := flag.NewFlagSet("orders cancel", flag.ExitOnError)
+		reason := flags.String("reason", "cancelled by operator", "reason stored in the status history")
+		adminEmail := flags.String("admin", a.Config.GetString("ADMIN_EMAIL"), "admin recorded as the one who cancelled, default ADMIN_EMAIL")
+		_ = flags.Parse(args)
+		if flags.NArg() == 0 {
+			return fmt.Errorf("expected at least one order ID")
+		}
+		ids := make([]int, 0, flags.NArg())
+		for _, arg := range flags.Args() {
+			id, err := strconv.Atoi(arg)
+			if err != nil || id < 1 {
+				return fmt.Errorf("invalid order ID %q", arg)
+			}
+			ids = append(ids, id)
+		}
+
+		// Riwayat status selalu mencatat admin yang mengubah order
+		if *adminEmail == "" {
+			return fmt.Errorf("-admin is required when ADMIN_EMAIL is not set")
+		}
+		admin, err := a.UserRepository.FindByEmail(ctx, *adminEmail)
+		if err != nil {
+			return fmt.Errorf("admin %s not found", *adminEmail)
+		}
+		if admin.Role != enum.RoleAdmin {
+			return fmt.Errorf("%s is not an admin", *adminEmail)
+		}
+
+		// Order diproses satu per satu, kegagalan satu order tidak membatalkan yang lain
+		cancelled := make([]model.OrderResponse, 0, len(ids))
+		var failed int
+		for _, id := range ids {
+			order, err := a.OrderUseCase.UpdateOrderStatus(ctx, id, &model.UpdateOrderStatusRequest{Status: enum.Cancelled, Reason: *reason}, admin.ID)
+			if err != nil {
+				a.Log.Errorf("order %d: %v", id, err)
+				failed++
+				continue
+			}
+			cancelled = append(cancelled, *order)
+		}
+
+		rows := make([][]string, 0, len(cancelled))
+		for _, order := range cancelled {
+			rows = append(rows, []string{strconv.Itoa(order.ID), strconv.Itoa(order.UserID), order.Status})
+		}
+		if err := a.Out.Print(cancelled, []string{"ID", "USER", "STATUS"}, rows); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d orders could not be cancelled", failed, len(ids))
+		}
+		return nil
+
+	case "expire":
+		if len(args) != 0 {
+			return fmt.Errorf("orders expire takes no arguments")
+		}
+		return a.OrderCronJob.CheckingOrderPaymentStatus(ctx)
+
+	default:
+		return fmt.Errorf("unknown orders command %q", command)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+type statsResponse struct {
+	Books  *model.BookStatsResponse      `json:"books"`
+	Prices *model.BookPriceStatsResponse `json:"prices"`
+	Orders *model.OrderStatsResponse     `json:"orders"`
+}
+
+func runStats(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("stats takes no arguments")
+	}
+
+	books, err := a.BookUseCase.GetTotalBooks(ctx)
+	if err != nil {
+		return err
+	}
+	prices, err := a.BookUseCase.GetBookPriceStats(ctx)
+	if err != nil {
+		return err
+	}
+	orders, err := a.OrderUseCase.GetOrderStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{
+		{"books", strconv.Itoa(books.TotalBooks)},
+		{"min price", prices.MinPrice.String() + " " + prices.Currency},
+		{"max price", prices.MaxPrice.String() + " " + prices.Currency},
+		{"avg price", prices.AvgPrice.String() + " " + prices.Currency},
+		{"orders", strconv.FormatInt(orders.TotalOrders, 10)},
+	}
+	for _, status := range orders.Statuses {
+		rows = append(rows, []string{
+			"  " + status.Status,
+			fmt.Sprintf("%d (%s %s)", status.Count, status.TotalPrice.String(), orders.Currency),
+		})
+	}
+	rows = append(rows, []string{"revenue", orders.Revenue.String() + " " + orders.Currency})
+
+	return a.Out.Print(&statsResponse{Books: books, Prices: prices, Orders: orders}, []string{"METRIC", "VALUE"}, rows)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func runUsers(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing users command, see -h")
+	}
+
+	switch command, args := args[0], args[1:]; command {
+	case "create":
+		flags := flag.NewFlagSet("users create", flag.ExitOnError)
+		name := flags.String("name", "", "full name")
+		email := flags.String("email", "", "email address, used to log in")
+		password := flags.String("password", "", "password, generated when empty")
+		admin := flags.Bool("admin", false, "create an admin instead of a customer")
+		_ = flags.Parse(args)
+		if *name == "" || *email == "" {
+			return fmt.Errorf("-name and -email are required")
+		}
+
+		generated := *password == ""
+		if generated {
+			*password = generatePassword()
+		}
+		role := enum.RoleCustomer
+		if *admin {
+			role = enum.RoleAdmin
+		}
+
+		user, err := a.UserUseCase.CreateUser(ctx, &model.RegisterUserRequest{Name: *name, Email: *email, Password: *password}, role)
+		if err != nil {
+			return err
+		}
+		if !generated {
+			return printUser(a, user)
+		}
+		return a.Out.Print(&createdUserResponse{UserResponse: user, Password: *password},
+			[]string{"ID", "NAME", "EMAIL", "ROLE", "PASSWORD"},
+			[][]string{{strconv.Itoa(user.ID), user.Name, user.Email, user.Role, *password}})
+
+	case "promote", "demote":
+		email, err := oneArg(args, "EMAIL")
+		if err != nil {
+			return err
+		}
+		role := enum.RoleAdmin
+		if command == "demote" {
+			role = enum.RoleCustomer
+		}
+		user, err := a.UserUseCase.ChangeRole(ctx, email, role)
+		if err != nil {
+			return err
+		}
+		return printUser(a, user)
+
+	case "disable", "enable":
+		email, err := oneArg(args, "EMAIL")
+		if err != nil {
+			return err
+		}
+		user, err := a.UserUseCase.SetDisabled(ctx, email, command == "disable")
+		if err != nil {
+			return err
+		}
+		return printUser(a, user)
+
+	case "reset-password":
+		flags := flag.NewFlagSet("users reset-password", flag.ExitOnError)
+		password := flags.String("password", "", "new password, generated when empty")
+		_ = flags.Parse(args)
+		email, err := oneArg(flags.Args(), "EMAIL")
+		if err != nil {
+			return err
+		}
+
+		generated := *password == ""
+		if generated {
+			*password = generatePassword()
+		}
+		if err := a.UserUseCase.ResetPassword(ctx, email, *password); err != nil {
+			return err
+		}
+
+		result := map[string]string{"email": email}
+		rows := [][]string{{email, "(as given)"}}
+		if generated {
+			result["password"] = *password
+			rows[0][1] = *password
+		}
+		return a.Out.Print(result, []string{"EMAIL", "PASSWORD"}, rows)
+
+	default:
+		return fmt.Errorf("unknown users command %q", command)
+	}
+}
+
+// createdUserResponse menyertakan password yang dibuat otomatis, hanya ditampilkan sekali
+type createdUserResponse struct {
+	*model.UserResponse
+	Password string `json:"password"`
+}
+
+func printUser(a *app, user *model.UserResponse) error {
+	status := "active"
+	if user.DisabledAt != nil {
+		status = "disabled since " + formatTime(user.DisabledAt)
+	}
+	return a.Out.Print(user, []string{"ID", "NAME", "EMAIL", "ROLE", "STATUS"}, [][]string{
+		{strconv.Itoa(user.ID), user.Name, user.Email, user.Role, status},
+	})
+}
+
+// generatePassword selalu memenuhi aturan utils.ValidatePassword
+func generatePassword() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf) + "#Aa1"
+}
+
+func oneArg(args []string, name string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected exactly one %s argument", name)
+	}
+	return args[0], nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fathirarya/online-bookstore-api/db/migrations"
+	"github.com/fathirarya/online-bookstore-api/internal/config"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+const usage = `usage: migrate [-dir path] <command> [arg]
+
+-dir is only used by create, it defaults to db/migrations of the module the command runs in
+
+commands:
+  up            apply all pending migrations
+  down [N]      roll back the last N migrations (default 1)
+  status        list migrations and whether they are applied
+  goto V        apply or roll back migrations until V is the current version (0 rolls back everything)
+  create NAME   create a new pair of up/down files for every dialect in -dir
+`
+
+// migrate mengelola skema database lewat file SQL bernomor di db/migrations.
+// Dijalankan dari cmd/migrate supaya .env di root terbaca, sama seperti server.
+//
+//	go run . up
+//	go run . down 1
+//	go run . create add_book_isbn
+func main() {
+	dir := flag.String("dir", "", "directory with a folder of migration files per dialect, default db/migrations in the module root")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// create tidak butuh koneksi database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatalf("create needs a name, e.g. create add_book_isbn")
+		}
+		if *dir == "" {
+			root, err := moduleRoot()
+			if err != nil {
+				log.Fatalf("failed to find the migrations directory, pass -dir: %v", err)
+			}
+			*dir = filepath.Join(root, "db", "migrations")
+		}
+		files, err := migrations.Create(*dir, args[1])
+		if err != nil {
+			log.Fatalf("failed to create migration: %v", err)
+		}
+		for _, file := range files {
+			fmt.Println("created", file)
+		}
+		return
+	}
+
+	viperConfig := config.NewViper()
+	db := config.NewDatabase(viperConfig, config.NewLogger(viperConfig))
+
+	// Database lama dari AutoMigrate diisi mata uang toko saat diadopsi
+	viperConfig.SetDefault("CURRENCY", money.DefaultCurrency)
+	if err := money.SetCurrency(viperConfig.GetString("CURRENCY")); err != nil {
+		log.Fatalf("invalid CURRENCY: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("migrate up failed after %d migrations: %v", count, err)
+		}
+		fmt.Printf("%d migrations applied\n", count)
+		// Cover base64 dari skema lama dipindahkan ke image store setelah skema terbaru terpasang
+		migrations.MigrateBookCovers(db, config.NewImageStore(viperConfig))
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				log.Fatalf("down needs a positive number of migrations")
+			}
+		}
+		count, err := migrator.Down(n)
+		if err != nil {
+			log.Fatalf("migrate down failed after %d migrations: %v", count, err)
+		}
+		fmt.Printf("%d migrations rolled back\n", count)
+	case "goto":
+		if len(args) != 2 {
+			log.Fatalf("goto needs a version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			log.Fatalf("invalid version: %s", args[1])
+		}
+		count, err := migrator.Goto(version)
+		if err != nil {
+			log.Fatalf("migrate goto %d failed after %d migrations: %v", version, count, err)
+		}
+		fmt.Printf("%d migrations applied or rolled back\n", count)
+	case "status":
+		list, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("failed to read migration status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range list {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Dirty {
+				state = "dirty"
+			}
+			if status.Up == "" {
+				state += " (unknown)"
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		w.Flush()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// moduleRoot walks up from the working directory to the folder with go.mod,
+// so create works from any folder of the repository
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("go.mod not found in the working directory or any parent")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/config"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+)
+
+// outbox-replay menandai event outbox dalam rentang waktu sebagai PENDING lagi,
+// dispatcher di server lalu mengirim ulang event tersebut sesuai urutan aslinya.
+//
+//	go run ./cmd/outbox-replay -from 2026-01-01 -to 2026-01-02 -type order.paid
+func main() {
+	from := flag.String("from", "", "start of the range, inclusive (YYYY-MM-DD or RFC3339)")
+	to := flag.String("to", "", "end of the range, exclusive (YYYY-MM-DD or RFC3339)")
+	eventType := flag.String("type", "", "only replay this event type, e.g. order.paid")
+	flag.Parse()
+
+	fromTime, err := parseTime(*from)
+	if err != nil {
+		log.Fatalf("invalid -from: %v", err)
+	}
+	toTime, err := parseTime(*to)
+	if err != nil {
+		log.Fatalf("invalid -to: %v", err)
+	}
+	if !toTime.After(fromTime) {
+		log.Fatalf("-to must be after -from")
+	}
+
+	viperConfig := config.NewViper()
+	logger := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, logger)
+
+	requeued, err := repository.NewOutboxRepository(db, logger).Requeue(db, fromTime, toTime, *eventType)
+	if err != nil {
+		log.Fatalf("failed to requeue events: %v", err)
+	}
+	fmt.Printf("%d events requeued\n", requeued)
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("required")
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/fathirarya/online-bookstore-api/db/migrations"
+	"github.com/fathirarya/online-bookstore-api/db/seeds"
+	"github.com/fathirarya/online-bookstore-api/internal/config"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+)
+
+// seed mengisi database yang sudah dimigrasi dengan data demo: admin, kategori, buku beserta
+// cover, customer dan riwayat order di semua status. Aman dijalankan berulang kali.
+//
+//	go run .
+//	go run . -seed 42 -books 5000 -customers 1000
+func main() {
+	defaults := seeds.DefaultOptions()
+	seed := flag.Int64("seed", defaults.Seed, "random seed, the same seed always generates the same data")
+	books := flag.Int("books", defaults.Books, "number of books")
+	customers := flag.Int("customers", defaults.Customers, "number of customers")
+	maxOrders := flag.Int("max-orders", defaults.MaxOrders, "maximum number of orders per customer")
+	covers := flag.Bool("covers", defaults.Covers, "generate cover images into the image store")
+	adminPassword := flag.String("admin-password", defaults.AdminPassword, "password of the admin user")
+	customerPassword := flag.String("customer-password", defaults.CustomerPassword, "password of every customer")
+	flag.Parse()
+
+	if *books < 0 || *customers < 0 || *maxOrders < 0 {
+		log.Fatalf("-books, -customers and -max-orders must not be negative")
+	}
+	for _, password := range []string{*adminPassword, *customerPassword} {
+		if err := utils.ValidatePassword(password); err != nil {
+			log.Fatalf("invalid password %q: %v", password, err)
+		}
+	}
+
+	viperConfig := config.NewViper()
+	logger := config.NewLogger(viperConfig)
+	db := config.NewDatabase(viperConfig, logger)
+
+	if config.IsInMemory(viperConfig) {
+		log.Fatalf("nothing to seed: DB_NAME=:memory: is discarded when this command exits")
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if err := migrator.Verify(); err != nil {
+		log.Fatalf("database schema is not up to date, run cmd/migrate first: %v", err)
+	}
+
+	viperConfig.SetDefault("CURRENCY", money.DefaultCurrency)
+	if err := money.SetCurrency(viperConfig.GetString("CURRENCY")); err != nil {
+		log.Fatalf("invalid CURRENCY: %v", err)
+	}
+
+	options := seeds.Options{
+		Seed:             *seed,
+		Books:            *books,
+		Customers:        *customers,
+		MaxOrders:        *maxOrders,
+		Covers:           *covers,
+		AdminEmail:       defaults.AdminEmail,
+		AdminPassword:    *adminPassword,
+		CustomerPassword: *customerPassword,
+		PaymentProvider:  config.NewPaymentProvider(viperConfig, logger).Name(),
+	}
+	// Admin yang sama dengan ADMIN_EMAIL server
+	if email := viperConfig.GetString("ADMIN_EMAIL"); email != "" {
+		options.AdminEmail = email
+	}
+
+	result, err := seeds.NewSeeder(db, logger, config.NewImageStore(viperConfig), options).Run(context.Background())
+	if err != nil {
+		log.Fatalf("seed failed: %v", err)
+	}
+
+	created := result.Created
+	fmt.Printf("created %d users, %d categories, %d books (%d covers), %d orders\n",
+		created.Users, created.Categories, created.Books, created.Covers, created.Orders)
+	fmt.Printf("admin: %s / %s, customers: <name><n>@example.com / %s\n", options.AdminEmail, options.AdminPassword, options.CustomerPassword)
+}
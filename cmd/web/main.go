@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/fathirarya/online-bookstore-api/internal/config"
 )
@@ -13,16 +15,22 @@ import (
 func main() {
 	viperConfig := config.NewViper()
 	log := config.NewLogger(viperConfig)
-	db := config.NewDatabase(viperConfig)
+	db := config.NewDatabase(viperConfig, log)
+	readReplicas := config.NewReadReplicas(viperConfig, log, db)
 	validate := config.NewValidator(viperConfig)
 	app := config.NewFiber(viperConfig)
-
-	config.Bootstrap(&config.BootstrapConfig{
-		DB:       db,
-		App:      app,
-		Log:      log,
-		Validate: validate,
-		Config:   viperConfig,
+	imageStore := config.NewImageStore(viperConfig)
+	paymentProvider := config.NewPaymentProvider(viperConfig, log)
+
+	scheduler := config.Bootstrap(&config.BootstrapConfig{
+		DB:              db,
+		ReadReplicas:    readReplicas,
+		App:             app,
+		Log:             log,
+		Validate:        validate,
+		Config:          viperConfig,
+		ImageStore:      imageStore,
+		PaymentProvider: paymentProvider,
 	})
 
 	webPort := viperConfig.GetInt("WEB_PORT")
@@ -40,4 +48,12 @@ func main() {
 
 	slog.Info("Shutting down gracefully...")
 	_ = app.Shutdown()
+
+	// Job yang sedang berjalan diberi waktu JOB_SHUTDOWN_TIMEOUT detik sebelum dibatalkan
+	viperConfig.SetDefault("JOB_SHUTDOWN_TIMEOUT", 30)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(viperConfig.GetInt("JOB_SHUTDOWN_TIMEOUT"))*time.Second)
+	defer cancel()
+	if err := scheduler.Stop(ctx); err != nil {
+		slog.Warn("Background jobs did not finish before shutdown", "error", err.Error())
+	}
 }
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"log"
+	"net/http"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/storage"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type legacyBookCover struct {
+	ID          int
+	ImageBase64 string
+}
+
+// MigrateBookCovers moves base64 covers from the legacy books.image_base64 column
+// into the image store and drops the column once every row has been moved.
+func MigrateBookCovers(db *gorm.DB, store storage.ImageStore) {
+	if !db.Migrator().HasColumn(&entity.Book{}, "image_base64") {
+		return
+	}
+
+	ctx := context.Background()
+	moved, failed := 0, 0
+
+	var batch []legacyBookCover
+	result := db.Table("books").
+		Select("id, image_base64").
+		Where("image_base64 IS NOT NULL AND image_base64 <> ''").
+		FindInBatches(&batch, 50, func(tx *gorm.DB, _ int) error {
+			for _, cover := range batch {
+				data, err := base64.StdEncoding.DecodeString(cover.ImageBase64)
+				if err != nil {
+					log.Printf("⚠️ book %d has invalid base64 cover: %v", cover.ID, err)
+					failed++
+					continue
+				}
+
+				contentType := http.DetectContentType(data)
+				key := "covers/" + uuid.NewString() + "/original" + utils.ImageExtension(contentType)
+				if err := store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+					log.Printf("⚠️ failed to store cover of book %d: %v", cover.ID, err)
+					failed++
+					continue
+				}
+
+				if err := db.Table("books").Where("id = ?", cover.ID).Updates(map[string]any{
+					"image_key":    key,
+					"image_base64": "",
+				}).Error; err != nil {
+					log.Printf("⚠️ failed to update book %d: %v", cover.ID, err)
+					failed++
+					continue
+				}
+				moved++
+			}
+			return nil
+		})
+	if result.Error != nil {
+		log.Fatalf("failed to migrate book covers: %v", result.Error)
+	}
+
+	// Kolom lama hanya di-drop jika semua cover berhasil dipindahkan
+	if failed > 0 {
+		log.Printf("⚠️ %d book covers could not be migrated, keeping image_base64 column", failed)
+		return
+	}
+	if err := db.Migrator().DropColumn(&entity.Book{}, "image_base64"); err != nil {
+		log.Fatalf("failed to drop image_base64 column: %v", err)
+	}
+
+	log.Printf("✅ %d book covers moved to image store", moved)
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"gorm.io/gorm"
+)
+
+// legacyColumn adalah kolom yang ditambahkan AutoMigrate ke tabel yang sudah ada sebelumnya
+type legacyColumn struct {
+	Table      string
+	Name       string
+	Definition string
+}
+
+// legacyColumns are the columns AutoMigrate added to existing tables before versioned migrations.
+// A database last migrated by an older build lacks some of them, and 000001_init skips its tables
+// because they already exist. Only MySQL was supported while AutoMigrate was in use.
+var legacyColumns = []legacyColumn{
+	{"users", "role", "varchar(20) NOT NULL DEFAULT 'CUSTOMER'"},
+	{"books", "stock", "bigint NOT NULL DEFAULT 0"},
+	{"books", "image_key", "varchar(255)"},
+	{"orders", "subtotal", "decimal(10,2) NOT NULL DEFAULT '0'"},
+	{"orders", "discount_amount", "decimal(10,2) NOT NULL DEFAULT '0'"},
+	{"orders", "coupon_id", "bigint"},
+	{"orders", "coupon_code", "varchar(50)"},
+	{"orders", "currency", "varchar(3) NOT NULL DEFAULT ''"},
+	{"book_orders", "unit_price", "decimal(10,2) NOT NULL DEFAULT '0'"},
+	{"book_orders", "title", "varchar(255) NOT NULL DEFAULT ''"},
+	{"book_orders", "author", "varchar(100) NOT NULL DEFAULT ''"},
+	{"payments", "currency", "varchar(3) NOT NULL DEFAULT ''"},
+}
+
+// isLegacy reports whether 000001_init is about to run on a database created by AutoMigrate
+func isLegacy(db *gorm.DB, migration *Migration) bool {
+	return migration.Version == 1 && db.Dialector.Name() == "mysql" && db.Migrator().HasTable("books")
+}
+
+// adoptLegacySchema brings a database created by AutoMigrate to the 000001_init schema and fills
+// the columns older builds left empty. It runs right after 000001_init created the missing tables.
+// The currency backfill uses money.Currency, so CURRENCY must be set before migrating.
+func adoptLegacySchema(db *gorm.DB) error {
+	for _, column := range legacyColumns {
+		if db.Migrator().HasColumn(column.Table, column.Name) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s", column.Table, column.Name, column.Definition)).Error; err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", column.Table, column.Name, err)
+		}
+		log.Printf("✅ added legacy column %s.%s", column.Table, column.Name)
+	}
+
+	// Status order versi awal berupa ENUM tiga nilai, status baru seperti SHIPPED tidak muat
+	columnTypes, err := db.Migrator().ColumnTypes("orders")
+	if err != nil {
+		return err
+	}
+	for _, columnType := range columnTypes {
+		if columnType.Name() == "status" && strings.EqualFold(columnType.DatabaseTypeName(), "enum") {
+			if err := db.Exec("ALTER TABLE `orders` MODIFY `status` varchar(20) NOT NULL DEFAULT 'PENDING'").Error; err != nil {
+				return fmt.Errorf("failed to convert orders.status: %w", err)
+			}
+		}
+	}
+
+	// Order lama belum punya subtotal, tanpa diskon subtotal sama dengan total
+	if err := db.Exec("UPDATE orders SET subtotal = total_price WHERE subtotal = 0 AND discount_amount = 0").Error; err != nil {
+		return fmt.Errorf("failed to backfill order subtotal: %w", err)
+	}
+
+	// Baris order lama belum punya snapshot, harga terbaik yang tersedia adalah harga buku saat ini
+	if err := db.Exec(`UPDATE book_orders SET
+		unit_price = COALESCE((SELECT books.price FROM books WHERE books.id = book_orders.book_id), 0),
+		title = COALESCE((SELECT books.title FROM books WHERE books.id = book_orders.book_id), ''),
+		author = COALESCE((SELECT books.author FROM books WHERE books.id = book_orders.book_id), '')
+		WHERE unit_price = 0 AND title = ''`).Error; err != nil {
+		return fmt.Errorf("failed to backfill order line snapshots: %w", err)
+	}
+
+	// Order dan payment lama dibuat sebelum ada kolom currency
+	for _, table := range []string{"orders", "payments"} {
+		if err := db.Exec("UPDATE "+table+" SET currency = ? WHERE currency = ''", money.Currency()).Error; err != nil {
+			return fmt.Errorf("failed to backfill %s currency: %w", table, err)
+		}
+	}
+
+	log.Println("✅ adopted database created by AutoMigrate")
+	return nil
+}
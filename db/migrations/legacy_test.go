@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// baselineSchema adalah bentuk tabel hasil AutoMigrate versi pertama, sebelum kolom stok, cover,
+// kupon, currency dan snapshot baris order ditambahkan
+const baselineSchema = `
+CREATE TABLE users (id integer PRIMARY KEY AUTOINCREMENT, name varchar(100) NOT NULL, email varchar(100) NOT NULL UNIQUE, password varchar(255) NOT NULL, created_at datetime);
+CREATE TABLE categories (id integer PRIMARY KEY AUTOINCREMENT, name varchar(100) NOT NULL, created_at datetime, updated_at datetime);
+CREATE TABLE books (id integer PRIMARY KEY AUTOINCREMENT, title varchar(255) NOT NULL, author varchar(100) NOT NULL, price decimal(10,2) NOT NULL, year integer, category_id integer NOT NULL, image_base64 text, created_at datetime, updated_at datetime);
+CREATE TABLE orders (id integer PRIMARY KEY AUTOINCREMENT, user_id integer NOT NULL, total_price decimal(10,2) NOT NULL, status varchar(20) DEFAULT 'PENDING', created_at datetime, updated_at datetime);
+CREATE TABLE book_orders (book_id integer, order_id integer, quantity integer NOT NULL, created_at datetime, PRIMARY KEY (book_id, order_id));
+CREATE TABLE payments (id integer PRIMARY KEY AUTOINCREMENT, order_id integer NOT NULL, provider varchar(30) NOT NULL, provider_ref varchar(100) NOT NULL, amount decimal(10,2) NOT NULL, status varchar(20) NOT NULL DEFAULT 'PENDING', created_at datetime, updated_at datetime);
+INSERT INTO users (name, email, password) VALUES ('Old Customer', 'old@example.com', 'x');
+INSERT INTO categories (name) VALUES ('Fiction');
+INSERT INTO books (title, author, price, year, category_id) VALUES ('Old Book', 'Old Author', 12.50, 2001, 1);
+INSERT INTO orders (user_id, total_price, status) VALUES (1, 25.00, 'PAID');
+INSERT INTO book_orders (book_id, order_id, quantity) VALUES (1, 1, 2);
+INSERT INTO payments (order_id, provider, provider_ref, amount, status) VALUES (1, 'fake', 'ref-1', 25.00, 'SUCCEEDED');
+`
+
+func TestAdoptLegacySchemaAddsColumnsAndBackfills(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:adopt_legacy?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	migrator := &Migrator{DB: db}
+	if err := migrator.exec(db, baselineSchema); err != nil {
+		t.Fatalf("failed to create baseline schema: %v", err)
+	}
+
+	if err := adoptLegacySchema(db); err != nil {
+		t.Fatalf("adoptLegacySchema() error = %v", err)
+	}
+	for _, column := range legacyColumns {
+		if !db.Migrator().HasColumn(column.Table, column.Name) {
+			t.Errorf("column %s.%s was not added", column.Table, column.Name)
+		}
+	}
+
+	var order struct {
+		Subtotal   money.Amount
+		TotalPrice money.Amount
+		Currency   string
+	}
+	if err := db.Table("orders").Select("subtotal, total_price, currency").Take(&order).Error; err != nil {
+		t.Fatalf("failed to load order: %v", err)
+	}
+	if order.Subtotal != money.FromMinor(2500) || order.Currency != money.Currency() {
+		t.Errorf("order subtotal = %s %q, want 25.00 %q", order.Subtotal, order.Currency, money.Currency())
+	}
+
+	var line struct {
+		UnitPrice money.Amount
+		Title     string
+		Author    string
+	}
+	if err := db.Table("book_orders").Select("unit_price, title, author").Take(&line).Error; err != nil {
+		t.Fatalf("failed to load order line: %v", err)
+	}
+	if line.UnitPrice != money.FromMinor(1250) || line.Title != "Old Book" || line.Author != "Old Author" {
+		t.Errorf("order line = %s %q %q, want the book snapshot 12.50 \"Old Book\" \"Old Author\"", line.UnitPrice, line.Title, line.Author)
+	}
+
+	var paymentCurrency, role string
+	if err := db.Table("payments").Select("currency").Scan(&paymentCurrency).Error; err != nil || paymentCurrency != money.Currency() {
+		t.Errorf("payment currency = %q, %v, want %q", paymentCurrency, err, money.Currency())
+	}
+	if err := db.Table("users").Select("role").Scan(&role).Error; err != nil || role != "CUSTOMER" {
+		t.Errorf("user role = %q, %v, want CUSTOMER", role, err)
+	}
+
+	// Dijalankan ulang tidak mengubah apa pun
+	if err := adoptLegacySchema(db); err != nil {
+		t.Fatalf("second adoptLegacySchema() error = %v", err)
+	}
+}
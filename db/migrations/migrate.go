@@ -1,23 +1,461 @@
 package migrations
 
 import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) {
-	err := db.AutoMigrate(
-		&entity.User{},
-		&entity.Category{},
-		&entity.Book{},
-		&entity.Order{},
-		&entity.BookOrder{},
-	)
+// File migrasi per dialect: <dialect>/<versi>_<nama>.up.sql dan <dialect>/<versi>_<nama>.down.sql.
+// Setiap versi harus ada di semua dialect supaya nomor versinya sama.
+//
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dialects are the directories migrations are kept in, named after the gorm dialector
+var Dialects = []string{"mysql", "postgres", "sqlite"}
+
+// advisoryLockKey identifies the migration lock on PostgreSQL
+const advisoryLockKey = 72707369
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var (
+	ErrLocked  = errors.New("migrations: another migration is running")
+	ErrNoTable = errors.New("migrations: schema_migrations table not found, run `migrate up` first")
+)
+
+// Migration is one numbered pair of SQL files
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// schemaMigration is one row of schema_migrations. Dirty is set while the migration runs and
+// stays set when it fails, the schema then has to be fixed by hand.
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	Dirty     bool      `gorm:"not null;default:false"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status describes a migration and whether it is applied
+type Status struct {
+	Migration
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies and rolls back migrations, every command holds a database lock
+type Migrator struct {
+	DB          *gorm.DB
+	Migrations  []Migration // urut berdasarkan versi
+	LockTimeout time.Duration
+}
+
+// NewMigrator loads the migrations embedded for the dialect of db
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	list, err := Load(files, db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: list, LockTimeout: 30 * time.Second}, nil
+}
+
+// Load reads the migrations in dir of fsys and checks every version has an up and a down file
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: no migrations for %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrations: invalid file name %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migrations: %06d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		list = append(list, *migration)
+	}
+	slices.SortFunc(list, func(a, b Migration) int { return a.Version - b.Version })
+	return list, nil
+}
+
+// Latest returns the highest known version, 0 when there are no migrations
+func (m *Migrator) Latest() int {
+	if len(m.Migrations) == 0 {
+		return 0
+	}
+	return m.Migrations[len(m.Migrations)-1].Version
+}
+
+// Up applies every pending migration and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	return m.Goto(m.Latest())
+}
+
+// Down rolls back the last n applied migrations
+func (m *Migrator) Down(n int) (int, error) {
+	count := 0
+	err := m.locked(func(db *gorm.DB) error {
+		applied, err := m.clean(db)
+		if err != nil {
+			return err
+		}
+		versions := m.appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && count < n; i-- {
+			if err := m.rollback(db, versions[i]); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Goto applies or rolls back migrations until version is the highest applied one.
+// Version 0 rolls back everything.
+func (m *Migrator) Goto(version int) (int, error) {
+	if version != 0 && m.find(version) == nil {
+		return 0, fmt.Errorf("migrations: unknown version %d", version)
+	}
+
+	count := 0
+	err := m.locked(func(db *gorm.DB) error {
+		applied, err := m.clean(db)
+		if err != nil {
+			return err
+		}
+
+		// Turun dulu dari versi tertinggi, lalu naik berurutan termasuk versi yang terlewat
+		versions := m.appliedVersions(applied)
+		for i := len(versions) - 1; i >= 0 && versions[i] > version; i-- {
+			if err := m.rollback(db, versions[i]); err != nil {
+				return err
+			}
+			count++
+		}
+		for i := range m.Migrations {
+			migration := &m.Migrations[i]
+			if migration.Version > version {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(db, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status lists the known migrations and applied versions this build does not know about
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied(m.DB)
+	if err != nil && !errors.Is(err, ErrNoTable) {
+		return nil, err
+	}
+
+	list := make([]Status, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.Dirty = row.Dirty
+			status.AppliedAt = &row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		list = append(list, status)
+	}
+	for _, row := range applied {
+		list = append(list, Status{
+			Migration: Migration{Version: row.Version, Name: row.Name},
+			Applied:   true,
+			Dirty:     row.Dirty,
+			AppliedAt: &row.AppliedAt,
+		})
+	}
+	slices.SortFunc(list, func(a, b Status) int { return a.Version - b.Version })
+	return list, nil
+}
+
+// Verify checks the database has every migration of this build applied and none is dirty.
+// It only reads schema_migrations, the server never changes the schema itself.
+func (m *Migrator) Verify() error {
+	applied, err := m.clean(m.DB)
 	if err != nil {
-		log.Fatalf("failed to migrate database: %v", err)
+		return err
+	}
+
+	var pending []string
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%06d_%s", migration.Version, migration.Name))
+		}
+		delete(applied, migration.Version)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("migrations: %d pending migrations (%s), run `migrate up`", len(pending), strings.Join(pending, ", "))
 	}
 
-	log.Println("✅ Database migrated successfully")
+	// Versi yang lebih baru bisa terjadi saat rolling deploy, selama migrasinya kompatibel
+	for version := range applied {
+		log.Printf("⚠️ database has migration %d which this build does not know", version)
+	}
+	return nil
+}
+
+func (m *Migrator) apply(db *gorm.DB, migration *Migration) error {
+	// Dicek sebelum 000001_init membuat tabel yang belum ada
+	legacy := isLegacy(db, migration)
+
+	row := &schemaMigration{Version: migration.Version, Name: migration.Name, Dirty: true, AppliedAt: time.Now()}
+	if err := db.Create(row).Error; err != nil {
+		return err
+	}
+	if err := m.exec(db, migration.Up); err != nil {
+		return fmt.Errorf("migrations: %06d_%s up failed, version left dirty: %w", migration.Version, migration.Name, err)
+	}
+	if legacy {
+		if err := adoptLegacySchema(db); err != nil {
+			return fmt.Errorf("migrations: adopting the AutoMigrate schema failed, version 1 left dirty: %w", err)
+		}
+	}
+	if err := db.Model(row).Update("dirty", false).Error; err != nil {
+		return err
+	}
+	log.Printf("✅ applied %06d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+func (m *Migrator) rollback(db *gorm.DB, version int) error {
+	migration := m.find(version)
+	if migration == nil {
+		return fmt.Errorf("migrations: version %d is applied but unknown to this build, roll it back with the build that added it", version)
+	}
+
+	row := &schemaMigration{Version: migration.Version}
+	if err := db.Model(row).Update("dirty", true).Error; err != nil {
+		return err
+	}
+	if err := m.exec(db, migration.Down); err != nil {
+		return fmt.Errorf("migrations: %06d_%s down failed, version left dirty: %w", migration.Version, migration.Name, err)
+	}
+	if err := db.Delete(row).Error; err != nil {
+		return err
+	}
+	log.Printf("↩️ rolled back %06d_%s", migration.Version, migration.Name)
+	return nil
+}
+
+// exec runs a file statement by statement, a statement ends with ";" at the end of a line
+func (m *Migrator) exec(db *gorm.DB, sql string) error {
+	var statement strings.Builder
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if err := db.Exec(statement.String()).Error; err != nil {
+				return err
+			}
+			statement.Reset()
+		}
+	}
+	if strings.TrimSpace(statement.String()) != "" {
+		return db.Exec(statement.String()).Error
+	}
+	return nil
+}
+
+// applied returns the rows of schema_migrations by version
+func (m *Migrator) applied(db *gorm.DB) (map[int]schemaMigration, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return nil, ErrNoTable
+	}
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// clean returns the applied rows, failing when one is dirty
+func (m *Migrator) clean(db *gorm.DB) (map[int]schemaMigration, error) {
+	applied, err := m.applied(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range applied {
+		if row.Dirty {
+			return nil, fmt.Errorf("migrations: version %d is dirty, fix the schema by hand and update schema_migrations", row.Version)
+		}
+	}
+	return applied, nil
+}
+
+// appliedVersions returns the applied versions in ascending order
+func (m *Migrator) appliedVersions(applied map[int]schemaMigration) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.Migrations {
+		if m.Migrations[i].Version == version {
+			return &m.Migrations[i]
+		}
+	}
+	return nil
+}
+
+// locked runs fn on a single connection holding the migration lock
+func (m *Migrator) locked(fn func(db *gorm.DB) error) error {
+	return m.DB.Connection(func(db *gorm.DB) error {
+		// Session baru supaya setiap query tidak mewarisi kondisi query sebelumnya
+		db = db.Session(&gorm.Session{})
+		if !db.Migrator().HasTable(&schemaMigration{}) {
+			if err := db.Migrator().CreateTable(&schemaMigration{}); err != nil {
+				return err
+			}
+		}
+
+		unlock, err := m.lock(db)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		return fn(db)
+	})
+}
+
+func (m *Migrator) lock(db *gorm.DB) (func(), error) {
+	switch dialect := db.Dialector.Name(); dialect {
+	case "mysql":
+		// GET_LOCK milik koneksi, otomatis lepas jika proses mati
+		var acquired *int
+		if err := db.Raw("SELECT GET_LOCK('schema_migrations', ?)", int(m.LockTimeout.Seconds())).Scan(&acquired).Error; err != nil {
+			return nil, err
+		}
+		if acquired == nil || *acquired != 1 {
+			return nil, ErrLocked
+		}
+		return func() { db.Exec("SELECT RELEASE_LOCK('schema_migrations')") }, nil
+	case "postgres":
+		// Advisory lock juga milik koneksi, dicoba ulang sampai LockTimeout
+		deadline := time.Now().Add(m.LockTimeout)
+		for {
+			var acquired bool
+			if err := db.Raw("SELECT pg_try_advisory_lock(?)", advisoryLockKey).Scan(&acquired).Error; err != nil {
+				return nil, err
+			}
+			if acquired {
+				return func() { db.Exec("SELECT pg_advisory_unlock(?)", advisoryLockKey) }, nil
+			}
+			if time.Now().After(deadline) {
+				return nil, ErrLocked
+			}
+			time.Sleep(time.Second)
+		}
+	case "sqlite":
+		// SQLite hanya punya satu writer
+		return func() {}, nil
+	default:
+		return nil, fmt.Errorf("migrations: locking is not supported for %s", dialect)
+	}
+}
+
+// Create writes an empty up and down file for every dialect in dir, numbered after the highest
+// version found in any of them
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migrations: name is required")
+	}
+
+	version := 1
+	for _, dialect := range Dialects {
+		list, err := Load(os.DirFS(filepath.Join(dir, dialect)), ".")
+		if err != nil {
+			return nil, err
+		}
+		if len(list) > 0 && list[len(list)-1].Version >= version {
+			version = list[len(list)-1].Version + 1
+		}
+	}
+
+	var created []string
+	for _, dialect := range Dialects {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, dialect, fmt.Sprintf("%06d_%s.%s.sql", version, name, direction))
+			if err := os.WriteFile(file, []byte("-- "+direction+" migration for "+name+"\n"), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, file)
+		}
+	}
+	return created, nil
 }
@@ -0,0 +1,64 @@
+package migrations_test
+
+import (
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/db/migrations"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+)
+
+func TestMigrationsRollBackAndReapply(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if err := migrator.Verify(); err != nil {
+		t.Fatalf("Verify() after Up = %v", err)
+	}
+
+	// Setiap file down harus membalik file up-nya, sehingga up berikutnya berjalan bersih
+	if _, err := migrator.Goto(0); err != nil {
+		t.Fatalf("Goto(0) error = %v", err)
+	}
+	if db.Migrator().HasTable("books") {
+		t.Fatalf("books still exists after rolling back every migration")
+	}
+	if err := migrator.Verify(); err == nil {
+		t.Fatalf("Verify() with pending migrations = nil, want error")
+	}
+
+	count, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if count != len(migrator.Migrations) {
+		t.Fatalf("Up() applied %d migrations, want %d", count, len(migrator.Migrations))
+	}
+	if err := migrator.Verify(); err != nil {
+		t.Fatalf("Verify() after reapplying = %v", err)
+	}
+}
+
+func TestEveryDialectHasTheSameVersions(t *testing.T) {
+	var reference []migrations.Migration
+	for _, dialect := range migrations.Dialects {
+		list, err := migrations.Load(migrations.Files, dialect)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", dialect, err)
+		}
+		if reference == nil {
+			reference = list
+			continue
+		}
+		if len(list) != len(reference) {
+			t.Fatalf("%s has %d migrations, %s has %d", dialect, len(list), migrations.Dialects[0], len(reference))
+		}
+		for i := range list {
+			if list[i].Version != reference[i].Version || list[i].Name != reference[i].Name {
+				t.Fatalf("%s migration %06d_%s does not match %06d_%s", dialect,
+					list[i].Version, list[i].Name, reference[i].Version, reference[i].Name)
+			}
+		}
+	}
+}
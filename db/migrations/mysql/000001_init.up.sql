@@ -0,0 +1,318 @@
+-- Skema awal, sama dengan hasil AutoMigrate sebelum migrasi berversi.
+-- IF NOT EXISTS supaya database lama yang sudah dibuat AutoMigrate bisa diadopsi, kolom yang
+-- belum ada di tabel lama ditambahkan dan diisi oleh adoptLegacySchema (legacy.go).
+
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` bigint AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `email` varchar(100) NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `role` varchar(20) NOT NULL DEFAULT 'CUSTOMER',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `categories` (
+  `id` bigint AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `books` (
+  `id` bigint AUTO_INCREMENT,
+  `title` varchar(255) NOT NULL,
+  `author` varchar(100) NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `year` bigint,
+  `category_id` bigint NOT NULL,
+  `stock` bigint NOT NULL DEFAULT 0,
+  `image_key` varchar(255),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_categories_books` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `orders` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `subtotal` decimal(10,2) NOT NULL DEFAULT '0',
+  `discount_amount` decimal(10,2) NOT NULL DEFAULT '0',
+  `coupon_id` bigint,
+  `coupon_code` varchar(50),
+  `total_price` decimal(10,2) NOT NULL,
+  `currency` varchar(3) NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL DEFAULT 'PENDING',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_users_orders` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `book_orders` (
+  `book_id` bigint,
+  `order_id` bigint,
+  `quantity` bigint NOT NULL,
+  `unit_price` decimal(10,2) NOT NULL DEFAULT '0',
+  `title` varchar(255) NOT NULL DEFAULT '',
+  `author` varchar(100) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`book_id`,`order_id`),
+  CONSTRAINT `fk_books_book_orders` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`),
+  CONSTRAINT `fk_orders_book_orders` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `refresh_tokens` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `family_id` varchar(36) NOT NULL,
+  `token_hash` varchar(64) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `revoked_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_refresh_tokens_user_id` (`user_id`),
+  INDEX `idx_refresh_tokens_family_id` (`family_id`),
+  UNIQUE INDEX `idx_refresh_tokens_token_hash` (`token_hash`),
+  CONSTRAINT `fk_refresh_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `revoked_tokens` (
+  `jti` varchar(36),
+  `user_id` bigint NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`jti`),
+  INDEX `idx_revoked_tokens_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `stock_movements` (
+  `id` bigint AUTO_INCREMENT,
+  `book_id` bigint NOT NULL,
+  `quantity_change` bigint NOT NULL,
+  `balance_after` bigint NOT NULL,
+  `reason` varchar(255) NOT NULL,
+  `order_id` bigint,
+  `created_by` bigint,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_stock_movements_book_id` (`book_id`),
+  INDEX `idx_stock_movements_order_id` (`order_id`),
+  CONSTRAINT `fk_stock_movements_book` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `order_status_histories` (
+  `id` bigint AUTO_INCREMENT,
+  `order_id` bigint NOT NULL,
+  `from_status` varchar(20) NOT NULL,
+  `to_status` varchar(20) NOT NULL,
+  `changed_by` bigint,
+  `reason` varchar(255),
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_order_status_histories_order_id` (`order_id`),
+  CONSTRAINT `fk_order_status_histories_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `payments` (
+  `id` bigint AUTO_INCREMENT,
+  `order_id` bigint NOT NULL,
+  `provider` varchar(30) NOT NULL,
+  `provider_ref` varchar(100) NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `currency` varchar(3) NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL DEFAULT 'PENDING',
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_payments_order_id` (`order_id`),
+  UNIQUE INDEX `idx_payments_provider_ref` (`provider_ref`),
+  CONSTRAINT `fk_payments_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `payment_events` (
+  `event_id` varchar(100),
+  `payment_id` bigint NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`event_id`),
+  INDEX `idx_payment_events_payment_id` (`payment_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `idempotency_keys` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `idempotency_key` varchar(255) NOT NULL,
+  `request_hash` varchar(64) NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `response_code` bigint,
+  `response_body` longblob,
+  `expires_at` datetime(3) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_idempotency_user_key` (`user_id`,`idempotency_key`),
+  INDEX `idx_idempotency_keys_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `carts` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_carts_user_id` (`user_id`),
+  CONSTRAINT `fk_carts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `cart_items` (
+  `cart_id` bigint,
+  `book_id` bigint,
+  `quantity` bigint NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`cart_id`,`book_id`),
+  CONSTRAINT `fk_cart_items_book` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_carts_items` FOREIGN KEY (`cart_id`) REFERENCES `carts`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `coupons` (
+  `id` bigint AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `value` decimal(10,2) NOT NULL,
+  `category_id` bigint,
+  `book_id` bigint,
+  `min_order_value` decimal(10,2) NOT NULL DEFAULT '0',
+  `max_discount` decimal(10,2),
+  `starts_at` datetime(3) NULL,
+  `ends_at` datetime(3) NULL,
+  `usage_limit` bigint,
+  `per_user_limit` bigint,
+  `used_count` bigint NOT NULL DEFAULT 0,
+  `active` boolean NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_coupons_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `coupon_redemptions` (
+  `id` bigint AUTO_INCREMENT,
+  `coupon_id` bigint NOT NULL,
+  `user_id` bigint NOT NULL,
+  `order_id` bigint NOT NULL,
+  `discount_amount` decimal(10,2) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_coupon_redemption_user` (`coupon_id`,`user_id`),
+  UNIQUE INDEX `idx_coupon_redemptions_order_id` (`order_id`),
+  CONSTRAINT `fk_coupon_redemptions_coupon` FOREIGN KEY (`coupon_id`) REFERENCES `coupons`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `order_notes` (
+  `id` bigint AUTO_INCREMENT,
+  `order_id` bigint NOT NULL,
+  `author_id` bigint NOT NULL,
+  `note` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_order_notes_order_id` (`order_id`),
+  CONSTRAINT `fk_order_notes_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `fk_order_notes_author` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `job_runs` (
+  `id` bigint AUTO_INCREMENT,
+  `job_name` varchar(100) NOT NULL,
+  `trigger_type` varchar(20) NOT NULL,
+  `triggered_by` bigint,
+  `owner` varchar(255) NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `error` text,
+  `started_at` datetime(3) NOT NULL,
+  `finished_at` datetime(3) NULL,
+  `duration_ms` bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  INDEX `idx_job_runs_job_name` (`job_name`),
+  INDEX `idx_job_runs_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `job_leases` (
+  `name` varchar(100),
+  `owner` varchar(255) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `outbox_events` (
+  `id` bigint AUTO_INCREMENT,
+  `event_id` varchar(36) NOT NULL,
+  `aggregate_type` varchar(50) NOT NULL,
+  `aggregate_id` bigint NOT NULL,
+  `event_type` varchar(100) NOT NULL,
+  `payload` text NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `last_error` text,
+  `next_attempt_at` datetime(3) NOT NULL,
+  `published_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_outbox_events_event_id` (`event_id`),
+  INDEX `idx_outbox_aggregate` (`aggregate_type`,`aggregate_id`),
+  INDEX `idx_outbox_events_event_type` (`event_type`),
+  INDEX `idx_outbox_events_status` (`status`),
+  INDEX `idx_outbox_events_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+  `id` bigint AUTO_INCREMENT,
+  `url` varchar(2048) NOT NULL,
+  `secret` varchar(100) NOT NULL,
+  `event_types` varchar(500) NOT NULL,
+  `description` varchar(255),
+  `active` boolean NOT NULL DEFAULT true,
+  `consecutive_failures` bigint NOT NULL DEFAULT 0,
+  `disabled_at` datetime(3) NULL,
+  `disabled_reason` varchar(255),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` bigint AUTO_INCREMENT,
+  `subscription_id` bigint NOT NULL,
+  `event_id` varchar(36) NOT NULL,
+  `event_type` varchar(100) NOT NULL,
+  `payload` text NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `response_code` bigint,
+  `last_error` text,
+  `next_attempt_at` datetime(3) NOT NULL,
+  `delivered_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_webhook_delivery_event` (`subscription_id`,`event_id`),
+  INDEX `idx_webhook_deliveries_status` (`status`),
+  INDEX `idx_webhook_deliveries_next_attempt_at` (`next_attempt_at`),
+  CONSTRAINT `fk_webhook_deliveries_subscription` FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions`(`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS `webhook_delivery_attempts` (
+  `id` bigint AUTO_INCREMENT,
+  `delivery_id` bigint NOT NULL,
+  `response_code` bigint,
+  `response_body` text,
+  `error` text,
+  `duration_ms` bigint NOT NULL,
+  `manual` boolean NOT NULL DEFAULT false,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhook_delivery_attempts_delivery_id` (`delivery_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+ALTER TABLE `webhook_deliveries` DROP CHECK `chk_webhook_deliveries_status`;
+ALTER TABLE `outbox_events` DROP CHECK `chk_outbox_events_status`;
+ALTER TABLE `job_runs` DROP CHECK `chk_job_runs_status`;
+ALTER TABLE `job_runs` DROP CHECK `chk_job_runs_trigger_type`;
+ALTER TABLE `coupons` DROP CHECK `chk_coupons_type`;
+ALTER TABLE `idempotency_keys` DROP CHECK `chk_idempotency_keys_status`;
+ALTER TABLE `payments` DROP CHECK `chk_payments_status`;
+ALTER TABLE `order_status_histories` DROP CHECK `chk_order_status_histories_to_status`;
+ALTER TABLE `order_status_histories` DROP CHECK `chk_order_status_histories_from_status`;
+ALTER TABLE `orders` DROP CHECK `chk_orders_status`;
+ALTER TABLE `users` DROP CHECK `chk_users_role`;
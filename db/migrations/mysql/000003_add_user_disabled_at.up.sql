@@ -0,0 +1,2 @@
+-- User yang dinonaktifkan tidak bisa login atau refresh token
+ALTER TABLE `users` ADD COLUMN `disabled_at` datetime(3) NULL;
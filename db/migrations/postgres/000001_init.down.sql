@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS "webhook_delivery_attempts";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_subscriptions";
+DROP TABLE IF EXISTS "outbox_events";
+DROP TABLE IF EXISTS "job_leases";
+DROP TABLE IF EXISTS "job_runs";
+DROP TABLE IF EXISTS "order_notes";
+DROP TABLE IF EXISTS "coupon_redemptions";
+DROP TABLE IF EXISTS "coupons";
+DROP TABLE IF EXISTS "cart_items";
+DROP TABLE IF EXISTS "carts";
+DROP TABLE IF EXISTS "idempotency_keys";
+DROP TABLE IF EXISTS "payment_events";
+DROP TABLE IF EXISTS "payments";
+DROP TABLE IF EXISTS "order_status_histories";
+DROP TABLE IF EXISTS "stock_movements";
+DROP TABLE IF EXISTS "revoked_tokens";
+DROP TABLE IF EXISTS "refresh_tokens";
+DROP TABLE IF EXISTS "book_orders";
+DROP TABLE IF EXISTS "orders";
+DROP TABLE IF EXISTS "books";
+DROP TABLE IF EXISTS "categories";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,316 @@
+-- Skema awal
+
+CREATE TABLE "users" (
+  "id" bigserial,
+  "name" varchar(100) NOT NULL,
+  "email" varchar(100) NOT NULL,
+  "password" varchar(255) NOT NULL,
+  "role" varchar(20) NOT NULL DEFAULT 'CUSTOMER',
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+
+CREATE TABLE "categories" (
+  "id" bigserial,
+  "name" varchar(100) NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "books" (
+  "id" bigserial,
+  "title" varchar(255) NOT NULL,
+  "author" varchar(100) NOT NULL,
+  "price" decimal(10,2) NOT NULL,
+  "year" bigint,
+  "category_id" bigint NOT NULL,
+  "stock" bigint NOT NULL DEFAULT 0,
+  "image_key" varchar(255),
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_categories_books" FOREIGN KEY ("category_id") REFERENCES "categories"("id")
+);
+
+CREATE TABLE "orders" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "subtotal" decimal(10,2) NOT NULL DEFAULT '0',
+  "discount_amount" decimal(10,2) NOT NULL DEFAULT '0',
+  "coupon_id" bigint,
+  "coupon_code" varchar(50),
+  "total_price" decimal(10,2) NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT '',
+  "status" varchar(20) NOT NULL DEFAULT 'PENDING',
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_orders" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+
+CREATE TABLE "book_orders" (
+  "book_id" bigint,
+  "order_id" bigint,
+  "quantity" bigint NOT NULL,
+  "unit_price" decimal(10,2) NOT NULL DEFAULT '0',
+  "title" varchar(255) NOT NULL DEFAULT '',
+  "author" varchar(100) NOT NULL DEFAULT '',
+  "created_at" timestamptz,
+  PRIMARY KEY ("book_id","order_id"),
+  CONSTRAINT "fk_books_book_orders" FOREIGN KEY ("book_id") REFERENCES "books"("id"),
+  CONSTRAINT "fk_orders_book_orders" FOREIGN KEY ("order_id") REFERENCES "orders"("id")
+);
+
+CREATE TABLE "refresh_tokens" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "family_id" varchar(36) NOT NULL,
+  "token_hash" varchar(64) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_refresh_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
+CREATE INDEX "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+
+CREATE TABLE "revoked_tokens" (
+  "jti" varchar(36),
+  "user_id" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz,
+  PRIMARY KEY ("jti")
+);
+CREATE INDEX "idx_revoked_tokens_expires_at" ON "revoked_tokens" ("expires_at");
+
+CREATE TABLE "stock_movements" (
+  "id" bigserial,
+  "book_id" bigint NOT NULL,
+  "quantity_change" bigint NOT NULL,
+  "balance_after" bigint NOT NULL,
+  "reason" varchar(255) NOT NULL,
+  "order_id" bigint,
+  "created_by" bigint,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_stock_movements_book" FOREIGN KEY ("book_id") REFERENCES "books"("id")
+);
+CREATE INDEX "idx_stock_movements_order_id" ON "stock_movements" ("order_id");
+CREATE INDEX "idx_stock_movements_book_id" ON "stock_movements" ("book_id");
+
+CREATE TABLE "order_status_histories" (
+  "id" bigserial,
+  "order_id" bigint NOT NULL,
+  "from_status" varchar(20) NOT NULL,
+  "to_status" varchar(20) NOT NULL,
+  "changed_by" bigint,
+  "reason" varchar(255),
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_order_status_histories_order" FOREIGN KEY ("order_id") REFERENCES "orders"("id")
+);
+CREATE INDEX "idx_order_status_histories_order_id" ON "order_status_histories" ("order_id");
+
+CREATE TABLE "payments" (
+  "id" bigserial,
+  "order_id" bigint NOT NULL,
+  "provider" varchar(30) NOT NULL,
+  "provider_ref" varchar(100) NOT NULL,
+  "amount" decimal(10,2) NOT NULL,
+  "currency" varchar(3) NOT NULL DEFAULT '',
+  "status" varchar(20) NOT NULL DEFAULT 'PENDING',
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_payments_order" FOREIGN KEY ("order_id") REFERENCES "orders"("id")
+);
+CREATE UNIQUE INDEX "idx_payments_provider_ref" ON "payments" ("provider_ref");
+CREATE INDEX "idx_payments_order_id" ON "payments" ("order_id");
+
+CREATE TABLE "payment_events" (
+  "event_id" varchar(100),
+  "payment_id" bigint NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "created_at" timestamptz,
+  PRIMARY KEY ("event_id")
+);
+CREATE INDEX "idx_payment_events_payment_id" ON "payment_events" ("payment_id");
+
+CREATE TABLE "idempotency_keys" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "idempotency_key" varchar(255) NOT NULL,
+  "request_hash" varchar(64) NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "response_code" bigint,
+  "response_body" bytea,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_idempotency_keys_expires_at" ON "idempotency_keys" ("expires_at");
+CREATE UNIQUE INDEX "idx_idempotency_user_key" ON "idempotency_keys" ("user_id","idempotency_key");
+
+CREATE TABLE "carts" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_carts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX "idx_carts_user_id" ON "carts" ("user_id");
+
+CREATE TABLE "cart_items" (
+  "cart_id" bigint,
+  "book_id" bigint,
+  "quantity" bigint NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("cart_id","book_id"),
+  CONSTRAINT "fk_cart_items_book" FOREIGN KEY ("book_id") REFERENCES "books"("id") ON DELETE CASCADE,
+  CONSTRAINT "fk_carts_items" FOREIGN KEY ("cart_id") REFERENCES "carts"("id")
+);
+
+CREATE TABLE "coupons" (
+  "id" bigserial,
+  "code" varchar(50) NOT NULL,
+  "type" varchar(20) NOT NULL,
+  "value" decimal(10,2) NOT NULL,
+  "category_id" bigint,
+  "book_id" bigint,
+  "min_order_value" decimal(10,2) NOT NULL DEFAULT '0',
+  "max_discount" decimal(10,2),
+  "starts_at" timestamptz,
+  "ends_at" timestamptz,
+  "usage_limit" bigint,
+  "per_user_limit" bigint,
+  "used_count" bigint NOT NULL DEFAULT 0,
+  "active" boolean NOT NULL,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX "idx_coupons_code" ON "coupons" ("code");
+
+CREATE TABLE "coupon_redemptions" (
+  "id" bigserial,
+  "coupon_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "order_id" bigint NOT NULL,
+  "discount_amount" decimal(10,2) NOT NULL,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_coupon_redemptions_coupon" FOREIGN KEY ("coupon_id") REFERENCES "coupons"("id")
+);
+CREATE UNIQUE INDEX "idx_coupon_redemptions_order_id" ON "coupon_redemptions" ("order_id");
+CREATE INDEX "idx_coupon_redemption_user" ON "coupon_redemptions" ("coupon_id","user_id");
+
+CREATE TABLE "order_notes" (
+  "id" bigserial,
+  "order_id" bigint NOT NULL,
+  "author_id" bigint NOT NULL,
+  "note" text NOT NULL,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_order_notes_order" FOREIGN KEY ("order_id") REFERENCES "orders"("id"),
+  CONSTRAINT "fk_order_notes_author" FOREIGN KEY ("author_id") REFERENCES "users"("id")
+);
+CREATE INDEX "idx_order_notes_order_id" ON "order_notes" ("order_id");
+
+CREATE TABLE "job_runs" (
+  "id" bigserial,
+  "job_name" varchar(100) NOT NULL,
+  "trigger_type" varchar(20) NOT NULL,
+  "triggered_by" bigint,
+  "owner" varchar(255) NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "error" text,
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz,
+  "duration_ms" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_job_runs_status" ON "job_runs" ("status");
+CREATE INDEX "idx_job_runs_job_name" ON "job_runs" ("job_name");
+
+CREATE TABLE "job_leases" (
+  "name" varchar(100),
+  "owner" varchar(255) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("name")
+);
+
+CREATE TABLE "outbox_events" (
+  "id" bigserial,
+  "event_id" varchar(36) NOT NULL,
+  "aggregate_type" varchar(50) NOT NULL,
+  "aggregate_id" bigint NOT NULL,
+  "event_type" varchar(100) NOT NULL,
+  "payload" text NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "last_error" text,
+  "next_attempt_at" timestamptz NOT NULL,
+  "published_at" timestamptz,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_outbox_events_created_at" ON "outbox_events" ("created_at");
+CREATE INDEX "idx_outbox_events_status" ON "outbox_events" ("status");
+CREATE INDEX "idx_outbox_events_event_type" ON "outbox_events" ("event_type");
+CREATE INDEX "idx_outbox_aggregate" ON "outbox_events" ("aggregate_type","aggregate_id");
+CREATE UNIQUE INDEX "idx_outbox_events_event_id" ON "outbox_events" ("event_id");
+
+CREATE TABLE "webhook_subscriptions" (
+  "id" bigserial,
+  "url" varchar(2048) NOT NULL,
+  "secret" varchar(100) NOT NULL,
+  "event_types" varchar(500) NOT NULL,
+  "description" varchar(255),
+  "active" boolean NOT NULL DEFAULT true,
+  "consecutive_failures" bigint NOT NULL DEFAULT 0,
+  "disabled_at" timestamptz,
+  "disabled_reason" varchar(255),
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial,
+  "subscription_id" bigint NOT NULL,
+  "event_id" varchar(36) NOT NULL,
+  "event_type" varchar(100) NOT NULL,
+  "payload" text NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "response_code" bigint,
+  "last_error" text,
+  "next_attempt_at" timestamptz NOT NULL,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_webhook_deliveries_subscription" FOREIGN KEY ("subscription_id") REFERENCES "webhook_subscriptions"("id")
+);
+CREATE INDEX "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE UNIQUE INDEX "idx_webhook_delivery_event" ON "webhook_deliveries" ("subscription_id","event_id");
+
+CREATE TABLE "webhook_delivery_attempts" (
+  "id" bigserial,
+  "delivery_id" bigint NOT NULL,
+  "response_code" bigint,
+  "response_body" text,
+  "error" text,
+  "duration_ms" bigint NOT NULL,
+  "manual" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX "idx_webhook_delivery_attempts_delivery_id" ON "webhook_delivery_attempts" ("delivery_id");
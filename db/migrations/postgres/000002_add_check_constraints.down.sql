@@ -0,0 +1,11 @@
+ALTER TABLE "webhook_deliveries" DROP CONSTRAINT "chk_webhook_deliveries_status";
+ALTER TABLE "outbox_events" DROP CONSTRAINT "chk_outbox_events_status";
+ALTER TABLE "job_runs" DROP CONSTRAINT "chk_job_runs_status";
+ALTER TABLE "job_runs" DROP CONSTRAINT "chk_job_runs_trigger_type";
+ALTER TABLE "coupons" DROP CONSTRAINT "chk_coupons_type";
+ALTER TABLE "idempotency_keys" DROP CONSTRAINT "chk_idempotency_keys_status";
+ALTER TABLE "payments" DROP CONSTRAINT "chk_payments_status";
+ALTER TABLE "order_status_histories" DROP CONSTRAINT "chk_order_status_histories_to_status";
+ALTER TABLE "order_status_histories" DROP CONSTRAINT "chk_order_status_histories_from_status";
+ALTER TABLE "orders" DROP CONSTRAINT "chk_orders_status";
+ALTER TABLE "users" DROP CONSTRAINT "chk_users_role";
@@ -0,0 +1,12 @@
+-- Nilai kolom status dibatasi dengan CHECK, pengganti ENUM yang hanya ada di MySQL
+ALTER TABLE "users" ADD CONSTRAINT "chk_users_role" CHECK ("role" IN ('CUSTOMER', 'ADMIN'));
+ALTER TABLE "orders" ADD CONSTRAINT "chk_orders_status" CHECK ("status" IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED'));
+ALTER TABLE "order_status_histories" ADD CONSTRAINT "chk_order_status_histories_from_status" CHECK ("from_status" IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED'));
+ALTER TABLE "order_status_histories" ADD CONSTRAINT "chk_order_status_histories_to_status" CHECK ("to_status" IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED'));
+ALTER TABLE "payments" ADD CONSTRAINT "chk_payments_status" CHECK ("status" IN ('PENDING', 'SUCCEEDED', 'FAILED', 'REFUNDED'));
+ALTER TABLE "idempotency_keys" ADD CONSTRAINT "chk_idempotency_keys_status" CHECK ("status" IN ('IN_PROGRESS', 'COMPLETED'));
+ALTER TABLE "coupons" ADD CONSTRAINT "chk_coupons_type" CHECK ("type" IN ('PERCENTAGE', 'FIXED'));
+ALTER TABLE "job_runs" ADD CONSTRAINT "chk_job_runs_trigger_type" CHECK ("trigger_type" IN ('SCHEDULE', 'MANUAL'));
+ALTER TABLE "job_runs" ADD CONSTRAINT "chk_job_runs_status" CHECK ("status" IN ('RUNNING', 'SUCCEEDED', 'FAILED'));
+ALTER TABLE "outbox_events" ADD CONSTRAINT "chk_outbox_events_status" CHECK ("status" IN ('PENDING', 'PUBLISHED', 'DEAD'));
+ALTER TABLE "webhook_deliveries" ADD CONSTRAINT "chk_webhook_deliveries_status" CHECK ("status" IN ('PENDING', 'SUCCEEDED', 'FAILED'));
@@ -0,0 +1 @@
+DELETE FROM "stock_movements" WHERE "reason" = 'opening balance' AND "order_id" IS NULL AND "created_by" IS NULL;
@@ -0,0 +1,9 @@
+-- Buku yang sudah ada sebelum ledger stok tidak punya movement awal. Selisih antara stok dan
+-- jumlah movement dicatat sebagai saldo pembuka, sehingga SUM(quantity_change) = books.stock.
+INSERT INTO "stock_movements" ("book_id", "quantity_change", "balance_after", "reason", "created_at")
+SELECT "books"."id", "books"."stock" - COALESCE("ledger"."total", 0), "books"."stock" - COALESCE("ledger"."total", 0),
+  'opening balance', COALESCE("books"."created_at", CURRENT_TIMESTAMP)
+FROM "books"
+LEFT JOIN (SELECT "book_id", SUM("quantity_change") AS "total" FROM "stock_movements" GROUP BY "book_id") AS "ledger"
+  ON "ledger"."book_id" = "books"."id"
+WHERE "books"."stock" <> COALESCE("ledger"."total", 0);
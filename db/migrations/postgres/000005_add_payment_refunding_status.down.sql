@@ -0,0 +1,4 @@
+UPDATE "payments" SET "status" = 'SUCCEEDED' WHERE "status" = 'REFUNDING';
+UPDATE "payments" SET "status" = 'FAILED' WHERE "status" = 'MISMATCHED';
+ALTER TABLE "payments" DROP CONSTRAINT "chk_payments_status";
+ALTER TABLE "payments" ADD CONSTRAINT "chk_payments_status" CHECK ("status" IN ('PENDING', 'SUCCEEDED', 'FAILED', 'REFUNDED'));
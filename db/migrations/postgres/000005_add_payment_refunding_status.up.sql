@@ -0,0 +1,3 @@
+-- REFUNDING: refund sudah dicatat sebelum provider dipanggil. MISMATCHED: jumlah dari webhook berbeda.
+ALTER TABLE "payments" DROP CONSTRAINT "chk_payments_status";
+ALTER TABLE "payments" ADD CONSTRAINT "chk_payments_status" CHECK ("status" IN ('PENDING', 'SUCCEEDED', 'FAILED', 'REFUNDING', 'REFUNDED', 'MISMATCHED'));
@@ -0,0 +1,309 @@
+-- Skema awal
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL,
+  `email` text NOT NULL,
+  `password` text NOT NULL,
+  `role` text NOT NULL DEFAULT 'CUSTOMER',
+  `created_at` datetime,
+  CONSTRAINT `uni_users_email` UNIQUE (`email`),
+  CONSTRAINT `chk_users_role` CHECK (`role` IN ('CUSTOMER', 'ADMIN'))
+);
+
+CREATE TABLE `categories` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE TABLE `books` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `title` text NOT NULL,
+  `author` text NOT NULL,
+  `price` decimal(10,2) NOT NULL,
+  `year` integer,
+  `category_id` integer NOT NULL,
+  `stock` integer NOT NULL DEFAULT 0,
+  `image_key` text,
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_categories_books` FOREIGN KEY (`category_id`) REFERENCES `categories`(`id`)
+);
+
+CREATE TABLE `orders` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `subtotal` decimal(10,2) NOT NULL DEFAULT '0',
+  `discount_amount` decimal(10,2) NOT NULL DEFAULT '0',
+  `coupon_id` integer,
+  `coupon_code` text,
+  `total_price` decimal(10,2) NOT NULL,
+  `currency` text NOT NULL DEFAULT '',
+  `status` varchar(20) NOT NULL DEFAULT 'PENDING',
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_users_orders` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `chk_orders_status` CHECK (`status` IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED'))
+);
+
+CREATE TABLE `book_orders` (
+  `book_id` integer,
+  `order_id` integer,
+  `quantity` integer NOT NULL,
+  `unit_price` decimal(10,2) NOT NULL DEFAULT '0',
+  `title` text NOT NULL DEFAULT '',
+  `author` text NOT NULL DEFAULT '',
+  `created_at` datetime,
+  PRIMARY KEY (`book_id`,`order_id`),
+  CONSTRAINT `fk_books_book_orders` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`),
+  CONSTRAINT `fk_orders_book_orders` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`)
+);
+
+CREATE TABLE `refresh_tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `family_id` text NOT NULL,
+  `token_hash` text NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `revoked_at` datetime,
+  `created_at` datetime,
+  CONSTRAINT `fk_refresh_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE UNIQUE INDEX `idx_refresh_tokens_token_hash` ON `refresh_tokens`(`token_hash`);
+CREATE INDEX `idx_refresh_tokens_family_id` ON `refresh_tokens`(`family_id`);
+CREATE INDEX `idx_refresh_tokens_user_id` ON `refresh_tokens`(`user_id`);
+
+CREATE TABLE `revoked_tokens` (
+  `jti` text,
+  `user_id` integer NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `created_at` datetime,
+  PRIMARY KEY (`jti`)
+);
+CREATE INDEX `idx_revoked_tokens_expires_at` ON `revoked_tokens`(`expires_at`);
+
+CREATE TABLE `stock_movements` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `book_id` integer NOT NULL,
+  `quantity_change` integer NOT NULL,
+  `balance_after` integer NOT NULL,
+  `reason` text NOT NULL,
+  `order_id` integer,
+  `created_by` integer,
+  `created_at` datetime,
+  CONSTRAINT `fk_stock_movements_book` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`)
+);
+CREATE INDEX `idx_stock_movements_order_id` ON `stock_movements`(`order_id`);
+CREATE INDEX `idx_stock_movements_book_id` ON `stock_movements`(`book_id`);
+
+CREATE TABLE `order_status_histories` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `order_id` integer NOT NULL,
+  `from_status` text NOT NULL,
+  `to_status` text NOT NULL,
+  `changed_by` integer,
+  `reason` text,
+  `created_at` datetime,
+  CONSTRAINT `fk_order_status_histories_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `chk_order_status_histories_from_status` CHECK (`from_status` IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED')),
+  CONSTRAINT `chk_order_status_histories_to_status` CHECK (`to_status` IN ('PENDING', 'PAID', 'PROCESSING', 'SHIPPED', 'DELIVERED', 'CANCELLED', 'REFUND_REQUESTED', 'REFUNDED'))
+);
+CREATE INDEX `idx_order_status_histories_order_id` ON `order_status_histories`(`order_id`);
+
+CREATE TABLE `payments` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `order_id` integer NOT NULL,
+  `provider` text NOT NULL,
+  `provider_ref` text NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `currency` text NOT NULL DEFAULT '',
+  `status` text NOT NULL DEFAULT 'PENDING',
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_payments_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `chk_payments_status` CHECK (`status` IN ('PENDING', 'SUCCEEDED', 'FAILED', 'REFUNDED'))
+);
+CREATE UNIQUE INDEX `idx_payments_provider_ref` ON `payments`(`provider_ref`);
+CREATE INDEX `idx_payments_order_id` ON `payments`(`order_id`);
+
+CREATE TABLE `payment_events` (
+  `event_id` text,
+  `payment_id` integer NOT NULL,
+  `type` text NOT NULL,
+  `created_at` datetime,
+  PRIMARY KEY (`event_id`)
+);
+CREATE INDEX `idx_payment_events_payment_id` ON `payment_events`(`payment_id`);
+
+CREATE TABLE `idempotency_keys` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `idempotency_key` text NOT NULL,
+  `request_hash` text NOT NULL,
+  `status` text NOT NULL,
+  `response_code` integer,
+  `response_body` blob,
+  `expires_at` datetime NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `chk_idempotency_keys_status` CHECK (`status` IN ('IN_PROGRESS', 'COMPLETED'))
+);
+CREATE INDEX `idx_idempotency_keys_expires_at` ON `idempotency_keys`(`expires_at`);
+CREATE UNIQUE INDEX `idx_idempotency_user_key` ON `idempotency_keys`(`user_id`,`idempotency_key`);
+
+CREATE TABLE `carts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_carts_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE UNIQUE INDEX `idx_carts_user_id` ON `carts`(`user_id`);
+
+CREATE TABLE `cart_items` (
+  `cart_id` integer,
+  `book_id` integer,
+  `quantity` integer NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime,
+  PRIMARY KEY (`cart_id`,`book_id`),
+  CONSTRAINT `fk_carts_items` FOREIGN KEY (`cart_id`) REFERENCES `carts`(`id`),
+  CONSTRAINT `fk_cart_items_book` FOREIGN KEY (`book_id`) REFERENCES `books`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `coupons` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `code` text NOT NULL,
+  `type` text NOT NULL,
+  `value` decimal(10,2) NOT NULL,
+  `category_id` integer,
+  `book_id` integer,
+  `min_order_value` decimal(10,2) NOT NULL DEFAULT '0',
+  `max_discount` decimal(10,2),
+  `starts_at` datetime,
+  `ends_at` datetime,
+  `usage_limit` integer,
+  `per_user_limit` integer,
+  `used_count` integer NOT NULL DEFAULT 0,
+  `active` numeric NOT NULL,
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `chk_coupons_type` CHECK (`type` IN ('PERCENTAGE', 'FIXED'))
+);
+CREATE UNIQUE INDEX `idx_coupons_code` ON `coupons`(`code`);
+
+CREATE TABLE `coupon_redemptions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `coupon_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `order_id` integer NOT NULL,
+  `discount_amount` decimal(10,2) NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `fk_coupon_redemptions_coupon` FOREIGN KEY (`coupon_id`) REFERENCES `coupons`(`id`)
+);
+CREATE UNIQUE INDEX `idx_coupon_redemptions_order_id` ON `coupon_redemptions`(`order_id`);
+CREATE INDEX `idx_coupon_redemption_user` ON `coupon_redemptions`(`coupon_id`,`user_id`);
+
+CREATE TABLE `order_notes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `order_id` integer NOT NULL,
+  `author_id` integer NOT NULL,
+  `note` text NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `fk_order_notes_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `fk_order_notes_author` FOREIGN KEY (`author_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_order_notes_order_id` ON `order_notes`(`order_id`);
+
+CREATE TABLE `job_runs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `job_name` text NOT NULL,
+  `trigger_type` text NOT NULL,
+  `triggered_by` integer,
+  `owner` text NOT NULL,
+  `status` text NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `error` text,
+  `started_at` datetime NOT NULL,
+  `finished_at` datetime,
+  `duration_ms` integer NOT NULL DEFAULT 0,
+  CONSTRAINT `chk_job_runs_trigger_type` CHECK (`trigger_type` IN ('SCHEDULE', 'MANUAL')),
+  CONSTRAINT `chk_job_runs_status` CHECK (`status` IN ('RUNNING', 'SUCCEEDED', 'FAILED'))
+);
+CREATE INDEX `idx_job_runs_status` ON `job_runs`(`status`);
+CREATE INDEX `idx_job_runs_job_name` ON `job_runs`(`job_name`);
+
+CREATE TABLE `job_leases` (
+  `name` text,
+  `owner` text NOT NULL,
+  `expires_at` datetime NOT NULL,
+  PRIMARY KEY (`name`)
+);
+
+CREATE TABLE `outbox_events` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `event_id` text NOT NULL,
+  `aggregate_type` text NOT NULL,
+  `aggregate_id` integer NOT NULL,
+  `event_type` text NOT NULL,
+  `payload` text NOT NULL,
+  `status` text NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `last_error` text,
+  `next_attempt_at` datetime NOT NULL,
+  `published_at` datetime,
+  `created_at` datetime,
+  CONSTRAINT `chk_outbox_events_status` CHECK (`status` IN ('PENDING', 'PUBLISHED', 'DEAD'))
+);
+CREATE INDEX `idx_outbox_events_created_at` ON `outbox_events`(`created_at`);
+CREATE INDEX `idx_outbox_events_status` ON `outbox_events`(`status`);
+CREATE INDEX `idx_outbox_events_event_type` ON `outbox_events`(`event_type`);
+CREATE INDEX `idx_outbox_aggregate` ON `outbox_events`(`aggregate_type`,`aggregate_id`);
+CREATE UNIQUE INDEX `idx_outbox_events_event_id` ON `outbox_events`(`event_id`);
+
+CREATE TABLE `webhook_subscriptions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `url` text NOT NULL,
+  `secret` text NOT NULL,
+  `event_types` text NOT NULL,
+  `description` text,
+  `active` numeric NOT NULL DEFAULT true,
+  `consecutive_failures` integer NOT NULL DEFAULT 0,
+  `disabled_at` datetime,
+  `disabled_reason` text,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+
+CREATE TABLE `webhook_deliveries` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `subscription_id` integer NOT NULL,
+  `event_id` text NOT NULL,
+  `event_type` text NOT NULL,
+  `payload` text NOT NULL,
+  `status` text NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `response_code` integer,
+  `last_error` text,
+  `next_attempt_at` datetime NOT NULL,
+  `delivered_at` datetime,
+  `created_at` datetime,
+  CONSTRAINT `fk_webhook_deliveries_subscription` FOREIGN KEY (`subscription_id`) REFERENCES `webhook_subscriptions`(`id`),
+  CONSTRAINT `chk_webhook_deliveries_status` CHECK (`status` IN ('PENDING', 'SUCCEEDED', 'FAILED'))
+);
+CREATE INDEX `idx_webhook_deliveries_next_attempt_at` ON `webhook_deliveries`(`next_attempt_at`);
+CREATE INDEX `idx_webhook_deliveries_status` ON `webhook_deliveries`(`status`);
+CREATE UNIQUE INDEX `idx_webhook_delivery_event` ON `webhook_deliveries`(`subscription_id`,`event_id`);
+
+CREATE TABLE `webhook_delivery_attempts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `delivery_id` integer NOT NULL,
+  `response_code` integer,
+  `response_body` text,
+  `error` text,
+  `duration_ms` integer NOT NULL,
+  `manual` numeric NOT NULL DEFAULT false,
+  `created_at` datetime
+);
+CREATE INDEX `idx_webhook_delivery_attempts_delivery_id` ON `webhook_delivery_attempts`(`delivery_id`);
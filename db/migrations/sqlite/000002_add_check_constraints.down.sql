@@ -0,0 +1 @@
+-- Lihat 000002_add_check_constraints.up.sql
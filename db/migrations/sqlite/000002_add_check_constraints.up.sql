@@ -0,0 +1,2 @@
+-- SQLite tidak bisa menambah constraint ke tabel yang sudah ada,
+-- CHECK untuk kolom status sudah dibuat di 000001_init.
@@ -0,0 +1,20 @@
+UPDATE `payments` SET `status` = 'SUCCEEDED' WHERE `status` = 'REFUNDING';
+UPDATE `payments` SET `status` = 'FAILED' WHERE `status` = 'MISMATCHED';
+CREATE TABLE `payments_new` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `order_id` integer NOT NULL,
+  `provider` text NOT NULL,
+  `provider_ref` text NOT NULL,
+  `amount` decimal(10,2) NOT NULL,
+  `currency` text NOT NULL DEFAULT '',
+  `status` text NOT NULL DEFAULT 'PENDING',
+  `created_at` datetime,
+  `updated_at` datetime,
+  CONSTRAINT `fk_payments_order` FOREIGN KEY (`order_id`) REFERENCES `orders`(`id`),
+  CONSTRAINT `chk_payments_status` CHECK (`status` IN ('PENDING', 'SUCCEEDED', 'FAILED', 'REFUNDED'))
+);
+INSERT INTO `payments_new` SELECT * FROM `payments`;
+DROP TABLE `payments`;
+ALTER TABLE `payments_new` RENAME TO `payments`;
+CREATE UNIQUE INDEX `idx_payments_provider_ref` ON `payments`(`provider_ref`);
+CREATE INDEX `idx_payments_order_id` ON `payments`(`order_id`);
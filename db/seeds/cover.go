@@ -0,0 +1,84 @@
+package seeds
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	coverWidth  = 300
+	coverHeight = 450
+	coverScale  = 2 // basicfont terlalu kecil untuk cover, teks digambar di kanvas kecil lalu diperbesar
+)
+
+// coverStyle is drawn from the seed so every book keeps the same cover across runs
+type coverStyle struct {
+	Background color.RGBA
+	Accent     color.RGBA
+	Bands      int
+}
+
+// renderCover draws a plain PNG cover: background, accent bands, title and author
+func renderCover(title, author string, style coverStyle) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, coverWidth, coverHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(style.Background), image.Point{}, draw.Src)
+
+	// Garis aksen di bagian bawah dan punggung buku di kiri
+	bandHeight := 12
+	for i := 0; i < style.Bands; i++ {
+		y := coverHeight - 60 - i*(bandHeight+8)
+		draw.Draw(img, image.Rect(0, y, coverWidth, y+bandHeight), image.NewUniform(style.Accent), image.Point{}, draw.Src)
+	}
+	draw.Draw(img, image.Rect(0, 0, 14, coverHeight), image.NewUniform(darken(style.Background)), image.Point{}, draw.Src)
+
+	// Teks digambar di kanvas setengah ukuran lalu di-scale supaya font bitmap tetap terbaca
+	text := image.NewRGBA(image.Rect(0, 0, coverWidth/coverScale, coverHeight/coverScale))
+	drawer := &font.Drawer{Dst: text, Src: image.NewUniform(color.White), Face: basicfont.Face7x13}
+	y := 30
+	for _, line := range wrap(title, 18) {
+		drawer.Dot = fixed.P(14, y)
+		drawer.DrawString(line)
+		y += 16
+	}
+	drawer.Dot = fixed.P(14, y+14)
+	drawer.DrawString(author)
+	draw.NearestNeighbor.Scale(img, img.Bounds(), text, text.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// wrap memecah teks per kata menjadi baris dengan panjang maksimal width karakter
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func darken(c color.RGBA) color.RGBA {
+	return color.RGBA{R: c.R / 2, G: c.G / 2, B: c.B / 2, A: 255}
+}
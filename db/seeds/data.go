@@ -0,0 +1,51 @@
+package seeds
+
+// Kategori tidak punya parent di skema, jadi pohon kategori disimpan sebagai nama "Induk / Anak"
+var categoryTree = []struct {
+	Name     string
+	Children []string
+}{
+	{"Fiction", []string{"Literary Fiction", "Fantasy", "Science Fiction", "Mystery & Thriller", "Romance", "Historical Fiction"}},
+	{"Non-Fiction", []string{"Biography & Memoir", "History", "Science", "Self-Help", "Travel", "True Crime"}},
+	{"Business", []string{"Management", "Finance", "Marketing", "Entrepreneurship"}},
+	{"Technology", []string{"Programming", "Data Science", "Networking", "Design"}},
+	{"Children", []string{"Picture Books", "Middle Grade", "Young Adult"}},
+	{"Arts & Hobbies", []string{"Cooking", "Photography", "Music", "Gardening"}},
+	{"Religion & Philosophy", []string{"Philosophy", "Religion", "Spirituality"}},
+	{"Education", []string{"Language Learning", "Test Preparation", "Textbooks"}},
+}
+
+var firstNames = []string{
+	"Adi", "Aisyah", "Andi", "Anya", "Bima", "Budi", "Citra", "Dewi", "Dimas", "Eka",
+	"Fajar", "Fitri", "Gita", "Hana", "Hendra", "Indah", "Intan", "Joko", "Kartika", "Lestari",
+	"Maya", "Nadia", "Nanda", "Oki", "Putri", "Rafi", "Rani", "Rizky", "Sari", "Siti",
+	"Taufik", "Tika", "Umar", "Vina", "Wahyu", "Wulan", "Yoga", "Yuni", "Zahra", "Zaki",
+	"Alice", "Ben", "Clara", "David", "Emma", "Felix", "Grace", "Henry", "Iris", "Jack",
+}
+
+var lastNames = []string{
+	"Pratama", "Saputra", "Wijaya", "Santoso", "Hidayat", "Kusuma", "Nugroho", "Siregar", "Lubis", "Putra",
+	"Halim", "Gunawan", "Susanto", "Rahman", "Hakim", "Wibowo", "Setiawan", "Purnomo", "Harahap", "Sitompul",
+	"Anderson", "Brown", "Carter", "Davis", "Evans", "Foster", "Garcia", "Hughes", "Miller", "Wilson",
+}
+
+var titleAdjectives = []string{
+	"Silent", "Hidden", "Last", "Broken", "Golden", "Forgotten", "Endless", "Crimson", "Quiet", "Wild",
+	"Distant", "Burning", "Hollow", "Secret", "Little", "Lost", "Bright", "Northern", "Midnight", "Paper",
+	"Iron", "Invisible", "Restless", "Ancient", "Frozen", "Honest", "Practical", "Modern", "Gentle", "Stolen",
+}
+
+var titleNouns = []string{
+	"River", "Garden", "Kingdom", "Library", "Promise", "Island", "Mountain", "Letter", "Harbor", "Engine",
+	"Season", "Lantern", "Archive", "Compass", "Orchard", "Mirror", "Voyage", "Forest", "Atlas", "Signal",
+	"Bridge", "Window", "Market", "Theory", "Journey", "Horizon", "Machine", "Daughter", "Storm", "Algorithm",
+}
+
+var titlePatterns = []string{
+	"The %s %s",
+	"%s %s",
+	"A %s %s",
+	"The %s of the %s",
+	"Beyond the %s %s",
+	"Notes on the %s %s",
+}
@@ -0,0 +1,733 @@
+package seeds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/imaging"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/storage"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Options controls how much data is generated. The same options always generate the same
+// rows, only the dates move along with Now.
+type Options struct {
+	Seed             int64
+	Books            int
+	Customers        int
+	MaxOrders        int  // order per customer, antara 0 dan MaxOrders
+	Covers           bool // butuh ImageStore
+	AdminEmail       string
+	AdminPassword    string
+	CustomerPassword string
+	PaymentProvider  string    // disimpan di payment supaya refund lewat provider yang sama
+	Now              time.Time // acuan tanggal order, default waktu saat Run dipanggil
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Seed:             1,
+		Books:            2000,
+		Customers:        200,
+		MaxOrders:        6,
+		Covers:           true,
+		AdminEmail:       "admin@example.com",
+		AdminPassword:    "Admin123!",
+		CustomerPassword: "Customer123!",
+		PaymentProvider:  "fake",
+	}
+}
+
+// Counts is the number of rows created by a run, rows that already existed are not counted
+type Counts struct {
+	Users      int
+	Categories int
+	Books      int
+	Covers     int
+	Orders     int
+}
+
+// Result holds the IDs of every seeded row, whether created by this run or an earlier one
+type Result struct {
+	AdminID     int
+	CustomerIDs []int
+	CategoryIDs []int
+	BookIDs     []int
+	Created     Counts
+}
+
+// Seeder fills a migrated database with demo data. Users, categories and books are matched by
+// email, name and title, and orders are only added for customers without orders, so running it
+// again with the same options creates nothing.
+type Seeder struct {
+	DB                           *gorm.DB
+	Log                          *logrus.Logger
+	ImageStore                   storage.ImageStore
+	Options                      Options
+	BookRepository               *repository.BookRepository
+	StockMovementRepository      *repository.StockMovementRepository
+	OrderRepository              *repository.OrderRepository
+	OrderStatusHistoryRepository *repository.OrderStatusHistoryRepository
+	PaymentRepository            *repository.PaymentRepository
+}
+
+func NewSeeder(db *gorm.DB, log *logrus.Logger, imageStore storage.ImageStore, options Options) *Seeder {
+	return &Seeder{
+		DB:                           db,
+		Log:                          log,
+		ImageStore:                   imageStore,
+		Options:                      options,
+		BookRepository:               repository.NewBookRepository(db, log),
+		StockMovementRepository:      repository.NewStockMovementRepository(db, log),
+		OrderRepository:              repository.NewOrderRepository(db, log),
+		OrderStatusHistoryRepository: repository.NewOrderStatusHistoryRepository(db, log),
+		PaymentRepository:            repository.NewPaymentRepository(db, log),
+	}
+}
+
+type bookPlan struct {
+	Title    string
+	Author   string
+	Price    money.Amount
+	Year     int
+	Category int // index di plan.Categories
+	Stock    int
+	Cover    coverStyle
+}
+
+type linePlan struct {
+	Book     int // index di plan.Books
+	Quantity int
+}
+
+type orderPlan struct {
+	Status    string
+	Lines     []linePlan
+	CreatedAt time.Time
+	ByExpiry  bool // order batal karena tidak dibayar, bukan dibatalkan customer
+}
+
+type customerPlan struct {
+	Name   string
+	Email  string
+	Orders []orderPlan
+}
+
+type plan struct {
+	Categories []string
+	Books      []bookPlan
+	Customers  []customerPlan
+}
+
+// orderStatuses semua status yang muncul di data seed, beserta bobotnya
+var orderStatuses = []struct {
+	Status string
+	Weight int
+}{
+	{enum.Pending, 8},
+	{enum.Paid, 8},
+	{enum.Processing, 8},
+	{enum.Shipped, 10},
+	{enum.Delivered, 35},
+	{enum.Cancelled, 15},
+	{enum.RefundRequested, 6},
+	{enum.Refunded, 10},
+}
+
+// statusPath is the sequence of statuses an order went through to reach status
+func statusPath(status string) []string {
+	switch status {
+	case enum.Pending:
+		return []string{enum.Pending}
+	case enum.Cancelled:
+		return []string{enum.Pending, enum.Cancelled}
+	}
+	path := []string{enum.Pending, enum.Paid, enum.Processing, enum.Shipped, enum.Delivered, enum.RefundRequested, enum.Refunded}
+	for i, s := range path {
+		if s == status {
+			return path[:i+1]
+		}
+	}
+	return path
+}
+
+// newPlan generates everything from the seed before touching the database, so the same
+// options give the same data no matter what is already stored
+func newPlan(options Options) *plan {
+	rng := rand.New(rand.NewSource(options.Seed))
+	p := &plan{}
+
+	for _, parent := range categoryTree {
+		for _, child := range parent.Children {
+			p.Categories = append(p.Categories, parent.Name+" / "+child)
+		}
+	}
+
+	titles := make(map[string]int)
+	for i := 0; i < options.Books; i++ {
+		title := fmt.Sprintf(titlePatterns[rng.Intn(len(titlePatterns))],
+			titleAdjectives[rng.Intn(len(titleAdjectives))], titleNouns[rng.Intn(len(titleNouns))])
+		titles[title]++
+		if n := titles[title]; n > 1 {
+			title = fmt.Sprintf("%s, Vol. %d", title, n)
+		}
+
+		// Sebagian kecil stoknya habis atau hampir habis
+		stock := 20 + rng.Intn(180)
+		switch r := rng.Intn(20); {
+		case r == 0:
+			stock = 0
+		case r < 3:
+			stock = 1 + rng.Intn(5)
+		}
+
+		p.Books = append(p.Books, bookPlan{
+			Title:    title,
+			Author:   firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))],
+			Price:    money.FromMinor(int64(5+rng.Intn(56))*100 - int64(rng.Intn(2))),
+			Year:     1950 + rng.Intn(76),
+			Category: rng.Intn(len(p.Categories)),
+			Stock:    stock,
+			Cover: coverStyle{
+				Background: color.RGBA{R: uint8(40 + rng.Intn(160)), G: uint8(40 + rng.Intn(160)), B: uint8(40 + rng.Intn(160)), A: 255},
+				Accent:     color.RGBA{R: uint8(rng.Intn(256)), G: uint8(rng.Intn(256)), B: uint8(rng.Intn(256)), A: 255},
+				Bands:      1 + rng.Intn(4),
+			},
+		})
+	}
+
+	totalWeight := 0
+	for _, s := range orderStatuses {
+		totalWeight += s.Weight
+	}
+	seq := 0
+	for i := 0; i < options.Customers; i++ {
+		first, last := firstNames[rng.Intn(len(firstNames))], lastNames[rng.Intn(len(lastNames))]
+		customer := customerPlan{
+			Name:  first + " " + last,
+			Email: fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1),
+		}
+
+		orders := 0
+		if options.MaxOrders > 0 {
+			orders = rng.Intn(options.MaxOrders + 1)
+		}
+		for j := 0; j < orders && len(p.Books) > 0; j++ {
+			// Order pertama tiap status dijamin ada, sisanya mengikuti bobot
+			status := orderStatuses[seq%len(orderStatuses)].Status
+			if seq >= len(orderStatuses) {
+				pick := rng.Intn(totalWeight)
+				for _, s := range orderStatuses {
+					if pick -= s.Weight; pick < 0 {
+						status = s.Status
+						break
+					}
+				}
+			}
+			seq++
+
+			// Order PENDING dibuat baru-baru ini supaya belum diambil job order-expiry
+			createdAt := options.Now.Add(-time.Duration(1+rng.Intn(365*24)) * time.Hour)
+			if status == enum.Pending {
+				createdAt = options.Now.Add(-time.Duration(1+rng.Intn(10)) * time.Minute)
+			}
+
+			order := orderPlan{Status: status, CreatedAt: createdAt, ByExpiry: rng.Intn(2) == 0}
+			picked := make(map[int]bool)
+			for k := 1 + rng.Intn(3); k > 0; k-- {
+				book := rng.Intn(len(p.Books))
+				if picked[book] {
+					continue
+				}
+				picked[book] = true
+				order.Lines = append(order.Lines, linePlan{Book: book, Quantity: 1 + rng.Intn(3)})
+			}
+			customer.Orders = append(customer.Orders, order)
+		}
+		p.Customers = append(p.Customers, customer)
+	}
+
+	return p
+}
+
+// Run seeds the database. Every step commits on its own, a failed run can simply be repeated.
+func (s *Seeder) Run(ctx context.Context) (*Result, error) {
+	if s.Options.Now.IsZero() {
+		s.Options.Now = time.Now()
+	}
+	if s.Options.Covers && s.ImageStore == nil {
+		return nil, fmt.Errorf("seed: covers need an image store")
+	}
+
+	p := newPlan(s.Options)
+	result := &Result{}
+	db := s.DB.WithContext(ctx)
+
+	if err := s.seedUsers(db, p, result); err != nil {
+		return nil, err
+	}
+	if err := s.seedCategories(db, p, result); err != nil {
+		return nil, err
+	}
+	if err := s.seedBooks(ctx, db, p, result); err != nil {
+		return nil, err
+	}
+	if err := s.seedOrders(db, p, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Seeder) seedUsers(db *gorm.DB, p *plan, result *Result) error {
+	admin, created, err := s.findOrCreateUser(db, "Admin", s.Options.AdminEmail, s.Options.AdminPassword, enum.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("seed admin: %w", err)
+	}
+	if created {
+		result.Created.Users++
+	}
+	result.AdminID = admin.ID
+
+	// Semua customer memakai password yang sama, cukup di-hash sekali
+	hash, err := bcrypt.GenerateFromPassword([]byte(s.Options.CustomerPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("seed customers: %w", err)
+	}
+
+	existing := make(map[string]int)
+	emails := make([]string, 0, len(p.Customers))
+	for _, customer := range p.Customers {
+		emails = append(emails, customer.Email)
+	}
+	for _, chunk := range chunks(emails, 500) {
+		var users []entity.User
+		if err := db.Select("id", "email").Where("email IN ?", chunk).Find(&users).Error; err != nil {
+			return fmt.Errorf("seed customers: %w", err)
+		}
+		for _, user := range users {
+			existing[user.Email] = user.ID
+		}
+	}
+
+	var missing []*entity.User
+	for _, customer := range p.Customers {
+		if _, ok := existing[customer.Email]; !ok {
+			missing = append(missing, &entity.User{Name: customer.Name, Email: customer.Email, Password: string(hash), Role: enum.RoleCustomer})
+		}
+	}
+	if len(missing) > 0 {
+		if err := db.CreateInBatches(missing, 200).Error; err != nil {
+			return fmt.Errorf("seed customers: %w", err)
+		}
+		for _, user := range missing {
+			existing[user.Email] = user.ID
+		}
+		result.Created.Users += len(missing)
+	}
+
+	for _, customer := range p.Customers {
+		result.CustomerIDs = append(result.CustomerIDs, existing[customer.Email])
+	}
+	s.Log.Infof("seeded %d users", result.Created.Users)
+	return nil
+}
+
+// findOrCreateUser tidak mengubah user yang sudah ada, termasuk password dan role-nya
+func (s *Seeder) findOrCreateUser(db *gorm.DB, name, email, password, role string) (*entity.User, bool, error) {
+	var user entity.User
+	err := db.Where("email = ?", email).Take(&user).Error
+	if err == nil {
+		return &user, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, false, err
+	}
+	user = entity.User{Name: name, Email: email, Password: string(hash), Role: role}
+	if err := db.Create(&user).Error; err != nil {
+		return nil, false, err
+	}
+	return &user, true, nil
+}
+
+func (s *Seeder) seedCategories(db *gorm.DB, p *plan, result *Result) error {
+	for _, name := range p.Categories {
+		var category entity.Category
+		err := db.Where("name = ?", name).Take(&category).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			category = entity.Category{Name: name}
+			if err = db.Create(&category).Error; err == nil {
+				result.Created.Categories++
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("seed category %s: %w", name, err)
+		}
+		result.CategoryIDs = append(result.CategoryIDs, category.ID)
+	}
+	s.Log.Infof("seeded %d categories", result.Created.Categories)
+	return nil
+}
+
+func (s *Seeder) seedBooks(ctx context.Context, db *gorm.DB, p *plan, result *Result) error {
+	existing := make(map[string]int)
+	titles := make([]string, 0, len(p.Books))
+	for _, book := range p.Books {
+		titles = append(titles, book.Title)
+	}
+	for _, chunk := range chunks(titles, 500) {
+		var books []entity.Book
+		if err := db.Select("id", "title").Where("title IN ?", chunk).Find(&books).Error; err != nil {
+			return fmt.Errorf("seed books: %w", err)
+		}
+		for _, book := range books {
+			existing[book.Title] = book.ID
+		}
+	}
+
+	var missing []int
+	for i, book := range p.Books {
+		if _, ok := existing[book.Title]; !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	// Cover disimpan lebih dulu, key-nya tetap per seed sehingga run ulang hanya menimpa file yang sama
+	keys := make(map[int]string)
+	if s.Options.Covers && len(missing) > 0 {
+		var err error
+		if keys, err = s.storeCovers(ctx, p, missing); err != nil {
+			return err
+		}
+		result.Created.Covers = len(keys)
+	}
+
+	for _, batch := range chunks(missing, 200) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			books := make([]*entity.Book, 0, len(batch))
+			for _, i := range batch {
+				plan := p.Books[i]
+				books = append(books, &entity.Book{
+					Title:      plan.Title,
+					Author:     plan.Author,
+					Price:      plan.Price,
+					Year:       plan.Year,
+					CategoryID: result.CategoryIDs[plan.Category],
+					Stock:      plan.Stock,
+					ImageKey:   keys[i],
+				})
+			}
+			if err := tx.Create(books).Error; err != nil {
+				return err
+			}
+
+			var movements []*entity.StockMovement
+			for _, book := range books {
+				existing[book.Title] = book.ID
+				if book.Stock > 0 {
+					movements = append(movements, &entity.StockMovement{BookID: book.ID, Change: book.Stock, BalanceAfter: book.Stock, Reason: "initial stock"})
+				}
+			}
+			if len(movements) > 0 {
+				return tx.Create(movements).Error
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("seed books: %w", err)
+		}
+	}
+	result.Created.Books = len(missing)
+
+	for _, book := range p.Books {
+		result.BookIDs = append(result.BookIDs, existing[book.Title])
+	}
+	s.Log.Infof("seeded %d books with %d covers", result.Created.Books, result.Created.Covers)
+	return nil
+}
+
+// storeCovers merender cover dan thumbnail secara paralel, hasilnya key original per index buku
+func (s *Seeder) storeCovers(ctx context.Context, p *plan, books []int) (map[int]string, error) {
+	keys := make(map[int]string, len(books))
+	jobs := make(chan int)
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				key, err := s.storeCover(ctx, p.Books[i], i)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("seed cover of %q: %w", p.Books[i].Title, err)
+				}
+				keys[i] = key
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, i := range books {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return keys, nil
+}
+
+func (s *Seeder) storeCover(ctx context.Context, book bookPlan, index int) (string, error) {
+	data, err := renderCover(book.Title, book.Author, book.Cover)
+	if err != nil {
+		return "", err
+	}
+	cover, err := imaging.ProcessCover(data, imaging.Options{})
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("covers/seed-%d-%05d/original.png", s.Options.Seed, index)
+	if err := s.ImageStore.Put(ctx, key, bytes.NewReader(cover.Original.Data), int64(len(cover.Original.Data)), cover.Original.ContentType); err != nil {
+		return "", err
+	}
+	for name, thumbnail := range cover.Thumbnails {
+		if err := s.ImageStore.Put(ctx, imaging.VariantKey(key, name), bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), thumbnail.ContentType); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+func (s *Seeder) seedOrders(db *gorm.DB, p *plan, result *Result) error {
+	// Customer yang sudah punya order dilewati, jadi run ulang tidak menggandakan riwayat
+	var withOrders []int
+	for _, chunk := range chunks(result.CustomerIDs, 500) {
+		var ids []int
+		if err := db.Model(&entity.Order{}).Distinct("user_id").Where("user_id IN ?", chunk).Pluck("user_id", &ids).Error; err != nil {
+			return fmt.Errorf("seed orders: %w", err)
+		}
+		withOrders = append(withOrders, ids...)
+	}
+	skip := make(map[int]bool, len(withOrders))
+	for _, id := range withOrders {
+		skip[id] = true
+	}
+
+	books := make(map[int]entity.Book)
+	for _, chunk := range chunks(result.BookIDs, 500) {
+		var list []entity.Book
+		if err := db.Select("id", "title", "author", "price").Where("id IN ?", chunk).Find(&list).Error; err != nil {
+			return fmt.Errorf("seed orders: %w", err)
+		}
+		for _, book := range list {
+			books[book.ID] = book
+		}
+	}
+
+	seq := 0
+	for i, customer := range p.Customers {
+		userID := result.CustomerIDs[i]
+		if skip[userID] {
+			seq += len(customer.Orders)
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			for j := range customer.Orders {
+				created, err := s.createOrder(tx, customer.Orders[j], userID, result, books, seq+j)
+				if err != nil {
+					return err
+				}
+				if created {
+					result.Created.Orders++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("seed orders of %s: %w", customer.Email, err)
+		}
+		seq += len(customer.Orders)
+	}
+	s.Log.Infof("seeded %d orders", result.Created.Orders)
+	return nil
+}
+
+// createOrder mengikuti jejak order asli: stok direservasi, setiap transisi tercatat di history,
+// order yang dibayar punya payment. Event outbox sengaja tidak ditulis supaya data lama tidak
+// terkirim ke webhook partner.
+func (s *Seeder) createOrder(tx *gorm.DB, plan orderPlan, userID int, result *Result, books map[int]entity.Book, seq int) (bool, error) {
+	order := &entity.Order{
+		UserID:    userID,
+		Currency:  money.Currency(),
+		Status:    plan.Status,
+		CreatedAt: plan.CreatedAt,
+		UpdateAt:  plan.CreatedAt,
+	}
+
+	// Baris yang stoknya tidak cukup dilewati, order tanpa baris tidak dibuat
+	var reserved []entity.BookOrder
+	for _, line := range plan.Lines {
+		book, ok := books[result.BookIDs[line.Book]]
+		if !ok {
+			continue
+		}
+		ok, err := s.BookRepository.DecrementStock(tx, book.ID, line.Quantity)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		reserved = append(reserved, entity.BookOrder{
+			BookID:    book.ID,
+			Quantity:  line.Quantity,
+			UnitPrice: book.Price,
+			Title:     book.Title,
+			Author:    book.Author,
+			CreatedAt: plan.CreatedAt,
+		})
+		order.Subtotal += book.Price.Mul(line.Quantity)
+	}
+	if len(reserved) == 0 {
+		return false, nil
+	}
+	order.TotalPrice = order.Subtotal
+	order.BookOrders = reserved
+
+	if err := s.OrderRepository.Create(tx, order); err != nil {
+		return false, err
+	}
+	for _, line := range reserved {
+		if err := s.recordStockMovement(tx, line.BookID, -line.Quantity, "order reserved", order.ID, &userID, plan.CreatedAt); err != nil {
+			return false, err
+		}
+	}
+
+	providerRef := fmt.Sprintf("seed_%d_%d", s.Options.Seed, seq)
+	var paid *entity.Payment
+	at := plan.CreatedAt
+	path := statusPath(plan.Status)
+	for k := 1; k < len(path); k++ {
+		from, to := path[k-1], path[k]
+		at = at.Add(time.Duration(2+seq%22) * time.Hour)
+		if at.After(s.Options.Now) {
+			at = s.Options.Now
+		}
+
+		adminID := result.AdminID
+		changedBy, reason := &adminID, ""
+		switch to {
+		case enum.Paid:
+			changedBy, reason = nil, "payment "+providerRef+" confirmed"
+		case enum.Cancelled:
+			changedBy, reason = &userID, "cancelled by customer"
+			if plan.ByExpiry {
+				changedBy, reason = nil, "payment window expired"
+			}
+		case enum.RefundRequested:
+			changedBy, reason = &userID, "damaged on arrival"
+		}
+
+		if err := s.OrderStatusHistoryRepository.Create(tx, &entity.OrderStatusHistory{
+			OrderID:    order.ID,
+			FromStatus: from,
+			ToStatus:   to,
+			ChangedBy:  changedBy,
+			Reason:     reason,
+			CreatedAt:  at,
+		}); err != nil {
+			return false, err
+		}
+
+		switch to {
+		case enum.Cancelled:
+			for _, line := range reserved {
+				if err := s.BookRepository.IncrementStock(tx, line.BookID, line.Quantity); err != nil {
+					return false, err
+				}
+				if err := s.recordStockMovement(tx, line.BookID, line.Quantity, "order cancelled", order.ID, nil, at); err != nil {
+					return false, err
+				}
+			}
+		case enum.Paid:
+			paid = &entity.Payment{
+				OrderID:     order.ID,
+				Provider:    s.Options.PaymentProvider,
+				ProviderRef: providerRef,
+				Amount:      order.TotalPrice,
+				Currency:    order.Currency,
+				Status:      enum.PaymentSucceeded,
+				CreatedAt:   plan.CreatedAt,
+				UpdatedAt:   at,
+			}
+			if err := s.PaymentRepository.Create(tx, paid); err != nil {
+				return false, err
+			}
+		case enum.Refunded:
+			if err := s.PaymentRepository.UpdateStatus(tx, paid.ID, enum.PaymentRefunded); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if at != plan.CreatedAt {
+		if err := tx.Model(order).UpdateColumn("updated_at", at).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (s *Seeder) recordStockMovement(tx *gorm.DB, bookID, change int, reason string, orderID int, createdBy *int, at time.Time) error {
+	balance, err := s.BookRepository.GetStock(tx, bookID)
+	if err != nil {
+		return err
+	}
+	return s.StockMovementRepository.Create(tx, &entity.StockMovement{
+		BookID:       bookID,
+		Change:       change,
+		BalanceAfter: balance,
+		Reason:       reason,
+		OrderID:      &orderID,
+		CreatedBy:    createdBy,
+		CreatedAt:    at,
+	})
+}
+
+func chunks[T any](items []T, size int) [][]T {
+	var out [][]T
+	for len(items) > size {
+		out = append(out, items[:size])
+		items = items[size:]
+	}
+	if len(items) > 0 {
+		out = append(out, items)
+	}
+	return out
+}
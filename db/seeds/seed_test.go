@@ -0,0 +1,69 @@
+package seeds
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+)
+
+func TestRunTwiceCreatesNothingNew(t *testing.T) {
+	db := testutil.NewDatabase(t)
+
+	options := DefaultOptions()
+	options.Books = 30
+	options.Customers = 5
+	options.MaxOrders = 3
+	options.Covers = false
+	options.Now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	first, err := NewSeeder(db, testutil.NewLogger(), nil, options).Run(ctx)
+	if err != nil {
+		t.Fatalf("first Run() error = %v", err)
+	}
+	if first.Created.Users != options.Customers+1 || first.Created.Books != options.Books || first.Created.Categories == 0 {
+		t.Fatalf("first run created %+v, want %d users and %d books", first.Created, options.Customers+1, options.Books)
+	}
+
+	var orders int64
+	if err := db.Model(&entity.Order{}).Count(&orders).Error; err != nil {
+		t.Fatalf("failed to count orders: %v", err)
+	}
+	if orders == 0 {
+		t.Fatalf("first run created no orders")
+	}
+
+	second, err := NewSeeder(db, testutil.NewLogger(), nil, options).Run(ctx)
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if second.Created != (Counts{}) {
+		t.Errorf("second run created %+v, want nothing", second.Created)
+	}
+
+	// Run kedua menemukan baris yang sama persis
+	if second.AdminID != first.AdminID {
+		t.Errorf("admin ID = %d, want %d", second.AdminID, first.AdminID)
+	}
+	for name, ids := range map[string][2][]int{
+		"customer": {first.CustomerIDs, second.CustomerIDs},
+		"category": {first.CategoryIDs, second.CategoryIDs},
+		"book":     {first.BookIDs, second.BookIDs},
+	} {
+		if !reflect.DeepEqual(ids[0], ids[1]) {
+			t.Errorf("%s IDs = %v, want %v", name, ids[1], ids[0])
+		}
+	}
+
+	var ordersAfter int64
+	if err := db.Model(&entity.Order{}).Count(&ordersAfter).Error; err != nil {
+		t.Fatalf("failed to count orders: %v", err)
+	}
+	if ordersAfter != orders || int64(first.Created.Orders) != orders {
+		t.Errorf("orders = %d after the second run, want %d", ordersAfter, orders)
+	}
+}
@@ -5,33 +5,39 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type JWTService struct {
-	secretKey      string
-	issuer         string
-	expireDuration time.Duration
+	secretKey             string
+	issuer                string
+	expireDuration        time.Duration
+	refreshExpireDuration time.Duration
 }
 
 type JWTCustomClaims struct {
-	UserID int `json:"user_id"`
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
 // NewJWTService creates a new JWTService
 func NewJWTService(cfg *JWTConfig) *JWTService {
 	return &JWTService{
-		secretKey:      cfg.SecretKey,
-		issuer:         cfg.Issuer,
-		expireDuration: cfg.ExpireDuration,
+		secretKey:             cfg.SecretKey,
+		issuer:                cfg.Issuer,
+		expireDuration:        cfg.ExpireDuration,
+		refreshExpireDuration: cfg.RefreshExpireDuration,
 	}
 }
 
 // GenerateToken generates a JWT token with custom claims
-func (j *JWTService) GenerateToken(userID int) (string, error) {
+func (j *JWTService) GenerateToken(userID int, role string) (string, error) {
 	claims := JWTCustomClaims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Issuer:    j.issuer,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expireDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -67,3 +73,7 @@ func (j *JWTService) ValidateToken(tokenString string) (*JWTCustomClaims, error)
 func (j *JWTService) ExpireDuration() time.Duration {
 	return j.expireDuration
 }
+
+func (j *JWTService) RefreshExpireDuration() time.Duration {
+	return j.refreshExpireDuration
+}
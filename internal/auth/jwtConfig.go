@@ -3,7 +3,8 @@ package auth
 import "time"
 
 type JWTConfig struct {
-	SecretKey      string
-	Issuer         string
-	ExpireDuration time.Duration
+	SecretKey             string
+	Issuer                string
+	ExpireDuration        time.Duration
+	RefreshExpireDuration time.Duration
 }
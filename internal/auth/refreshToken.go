@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// TokenDenylist reports whether an access token (by its jti) has been revoked
+type TokenDenylist interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// UserStatus reports whether the user behind an access token has been disabled
+type UserStatus interface {
+	IsDisabled(ctx context.Context, userID int) (bool, error)
+}
+
+// GenerateRefreshToken creates a random opaque refresh token
+func GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken hashes a refresh token so only the digest is stored in the database
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
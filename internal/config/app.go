@@ -12,40 +12,176 @@ import (
 	"github.com/fathirarya/online-bookstore-api/internal/delivery/http/handler"
 	"github.com/fathirarya/online-bookstore-api/internal/delivery/http/middleware"
 	"github.com/fathirarya/online-bookstore-api/internal/delivery/http/routes"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/jobs"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/outbox"
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
 	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/storage"
 	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/webhook"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
 type BootstrapConfig struct {
-	DB       *gorm.DB
-	App      *fiber.App
-	Log      *logrus.Logger
-	Validate *validator.Validate
-	Config   *viper.Viper
+	DB              *gorm.DB
+	ReadReplicas    *repository.ReadReplicas
+	App             *fiber.App
+	Log             *logrus.Logger
+	Validate        *validator.Validate
+	Config          *viper.Viper
+	ImageStore      storage.ImageStore
+	PaymentProvider payment.Provider
 }
 
-func Bootstrap(config *BootstrapConfig) {
-	// Run AutoMigrate
-	migrations.Migrate(config.DB)
-	log.Println("✅ Database migration completed")
+// Bootstrap wires the application and returns the job scheduler, already started,
+// so main can stop it on shutdown
+func Bootstrap(config *BootstrapConfig) *jobs.Scheduler {
+	// Mata uang toko, dicatat di setiap order dan payment
+	config.Config.SetDefault("CURRENCY", money.DefaultCurrency)
+	if err := money.SetCurrency(config.Config.GetString("CURRENCY")); err != nil {
+		log.Fatalf("invalid CURRENCY: %v", err)
+	}
+
+	// Skema dikelola lewat cmd/migrate, server hanya memastikan semua migrasi sudah dijalankan
+	migrator, err := migrations.NewMigrator(config.DB)
+	if err != nil {
+		log.Fatalf("failed to load migrations: %v", err)
+	}
+	if IsInMemory(config.Config) {
+		// Database in-memory kosong setiap start dan tidak bisa dimigrasi dari proses lain
+		if _, err := migrator.Up(); err != nil {
+			log.Fatalf("failed to migrate in-memory database: %v", err)
+		}
+	}
+	if err := migrator.Verify(); err != nil {
+		log.Fatalf("database schema is not up to date: %v", err)
+	}
+	log.Println("✅ Database schema verified")
 
 	// setup repositories
 	userRepository := repository.NewUserRepository(config.DB, config.Log)
 	categoryRepository := repository.NewCategoryRepository(config.DB, config.Log)
 	bookRepository := repository.NewBookRepository(config.DB, config.Log)
 	orderRepository := repository.NewOrderRepository(config.DB, config.Log)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(config.DB, config.Log)
+	revokedTokenRepository := repository.NewRevokedTokenRepository(config.DB, config.Log)
+	stockMovementRepository := repository.NewStockMovementRepository(config.DB, config.Log)
+	orderStatusHistoryRepository := repository.NewOrderStatusHistoryRepository(config.DB, config.Log)
+	paymentRepository := repository.NewPaymentRepository(config.DB, config.Log)
+	idempotencyKeyRepository := repository.NewIdempotencyKeyRepository(config.DB, config.Log)
+	cartRepository := repository.NewCartRepository(config.DB, config.Log)
+	couponRepository := repository.NewCouponRepository(config.DB, config.Log)
+	orderNoteRepository := repository.NewOrderNoteRepository(config.DB, config.Log)
+	jobRepository := repository.NewJobRepository(config.DB, config.Log)
+	outboxRepository := repository.NewOutboxRepository(config.DB, config.Log)
+	webhookRepository := repository.NewWebhookRepository(config.DB, config.Log)
+
+	// aturan bisnis order
+	orderRules := NewOrderRules(config.Config)
+	lowStockThreshold := NewLowStockThreshold(config.Config)
 
 	// setup usecases
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, userRepository)
-	categoryUseCase := usecase.NewCategoryUseCase(config.DB, config.Log, categoryRepository)
-	bookUseCase := usecase.NewBookUseCase(config.DB, config.Log, bookRepository, categoryRepository)
-	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validate, orderRepository, bookRepository)
+	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, userRepository, refreshTokenRepository, revokedTokenRepository)
+	categoryUseCase := usecase.NewCategoryUseCase(config.DB, config.ReadReplicas, config.Log, categoryRepository)
+	bookUseCase := usecase.NewBookUseCase(config.DB, config.ReadReplicas, config.Log, config.Validate, bookRepository, categoryRepository, stockMovementRepository, outboxRepository, config.ImageStore, NewImageOptions(config.Config))
+	orderStateMachine := usecase.NewOrderStateMachine(config.Log, orderRepository, orderStatusHistoryRepository, bookRepository,
+		stockMovementRepository, paymentRepository, config.PaymentProvider, couponRepository, outboxRepository)
+	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validate, orderRepository, bookRepository,
+		stockMovementRepository, orderStatusHistoryRepository, orderStateMachine, couponRepository, orderNoteRepository, outboxRepository, orderRules, lowStockThreshold)
+	cartUseCase := usecase.NewCartUseCase(config.DB, config.Log, config.Validate, cartRepository, bookRepository, orderUseCase)
+	couponUseCase := usecase.NewCouponUseCase(config.DB, config.Log, couponRepository, bookRepository, categoryRepository)
+	stockUseCase := usecase.NewStockUseCase(config.DB, config.Log, bookRepository, stockMovementRepository, outboxRepository, lowStockThreshold)
+	paymentUseCase := usecase.NewPaymentUseCase(config.DB, config.Log, config.PaymentProvider, paymentRepository, orderRepository, orderStateMachine)
+	orderCronjob := usecase.NewOrderCronJob(config.DB, config.Log, orderRepository, orderStateMachine, orderRules)
+	eventUseCase := usecase.NewEventUseCase(config.DB, config.Log, outboxRepository)
+
+	// setup outbox dispatcher, webhook partner di-antrikan lewat sink sendiri
+	outboxSinks, outboxHandlers := NewOutboxSinks(config.Config, config.Log)
+	SubscribeOutboxHandlers(outboxHandlers, config.Log)
+	outboxSinks = append(outboxSinks, webhook.NewSink(config.DB, webhookRepository))
+	dispatcher := outbox.NewDispatcher(config.DB, config.Log, outboxRepository, outboxSinks, NewOutboxOptions(config.Config))
+	outboxRetention := NewOutboxRetention(config.Config)
+	jobRunRetention := NewJobRunRetention(config.Config)
+
+	// setup webhook deliverer
+	deliverer := webhook.NewDeliverer(config.DB, config.Log, webhookRepository, NewWebhookOptions(config.Config))
+	webhookUseCase := usecase.NewWebhookUseCase(config.DB, config.Log, webhookRepository, deliverer)
+
+	// setup background jobs
+	scheduler := jobs.NewScheduler(config.DB, config.Log, jobRepository)
+	registerJobs(scheduler, []jobs.Job{
+		{
+			Name:       "order-expiry",
+			Schedule:   orderRules.ExpirySchedule,
+			Timeout:    time.Minute,
+			MaxRetries: 2,
+			Run:        orderCronjob.CheckingOrderPaymentStatus,
+		},
+		{
+			Name:       "refresh-token-purge",
+			Schedule:   "0 * * * *",
+			MaxRetries: 2,
+			Run:        userUseCase.PurgeExpiredTokens,
+		},
+		{
+			Name:       "idempotency-key-cleanup",
+			Schedule:   "30 * * * *",
+			MaxRetries: 2,
+			Run: func(ctx context.Context) error {
+				_, err := idempotencyKeyRepository.DeleteExpired(config.DB.WithContext(ctx))
+				return err
+			},
+		},
+		{
+			// Berjalan hampir satu menit penuh dan polling outbox setiap OUTBOX_POLL_INTERVAL_SECONDS
+			Name:     "outbox-dispatch",
+			Schedule: "* * * * *",
+			Timeout:  time.Minute,
+			Run: func(ctx context.Context) error {
+				return dispatcher.Run(ctx, 55*time.Second)
+			},
+		},
+		{
+			Name:     "webhook-deliver",
+			Schedule: "* * * * *",
+			Timeout:  time.Minute,
+			Run: func(ctx context.Context) error {
+				return deliverer.Run(ctx, 55*time.Second)
+			},
+		},
+		{
+			Name:       "outbox-cleanup",
+			Schedule:   "15 3 * * *",
+			MaxRetries: 2,
+			Run: func(ctx context.Context) error {
+				_, err := outboxRepository.DeletePublishedBefore(config.DB.WithContext(ctx), time.Now().Add(-outboxRetention))
+				return err
+			},
+		},
+		{
+			Name:       "job-run-cleanup",
+			Schedule:   "45 3 * * *",
+			MaxRetries: 2,
+			Run: func(ctx context.Context) error {
+				_, err := jobRepository.DeleteFinishedBefore(config.DB.WithContext(ctx), time.Now().Add(-jobRunRetention))
+				return err
+			},
+		},
+	})
+	jobUseCase := usecase.NewJobUseCase(config.DB, config.Log, scheduler, jobRepository)
+
+	// promote the first admin if configured and no admin exists yet
+	if adminEmail := config.Config.GetString("ADMIN_EMAIL"); adminEmail != "" {
+		if err := userUseCase.PromoteFirstAdmin(context.Background(), adminEmail); err != nil {
+			config.Log.Warnf("failed to promote first admin %s: %v", adminEmail, err)
+		}
+	}
 
 	// setup JWT config & service
 	jwtConfig := LoadJWTConfig()
@@ -56,27 +192,56 @@ func Bootstrap(config *BootstrapConfig) {
 	categoryHandler := handler.NewCategoryHandler(categoryUseCase, config.Log, config.Validate)
 	bookHandler := handler.NewBookHandler(bookUseCase, config.Log, config.Validate)
 	orderHandler := handler.NewOrderHandler(orderUseCase, config.Log)
+	stockHandler := handler.NewStockHandler(stockUseCase, config.Log, config.Validate)
+	paymentHandler := handler.NewPaymentHandler(paymentUseCase, config.Log)
+	cartHandler := handler.NewCartHandler(cartUseCase, config.Log)
+	couponHandler := handler.NewCouponHandler(couponUseCase, config.Log, config.Validate)
+	configHandler := handler.NewConfigHandler(orderRules)
+	jobHandler := handler.NewJobHandler(jobUseCase, config.Log, config.Validate)
+	eventHandler := handler.NewEventHandler(eventUseCase, config.Log, config.Validate)
+	webhookHandler := handler.NewWebhookHandler(webhookUseCase, config.Log, config.Validate)
+
+	idempotencyTTL := NewIdempotencyTTL(config.Config)
+
+	// Query dari satu request dibatalkan setelah DB_QUERY_TIMEOUT_SECONDS detik
+	config.Config.SetDefault("DB_QUERY_TIMEOUT_SECONDS", 15)
+	queryTimeout := time.Duration(config.Config.GetInt("DB_QUERY_TIMEOUT_SECONDS")) * time.Second
+	if queryTimeout <= 0 {
+		log.Fatalf("invalid DB_QUERY_TIMEOUT_SECONDS: must be at least 1")
+	}
 
 	// setup routes
 	routeConfig := routes.RouteConfig{
-		App:            config.App,
-		User:           userHandler,
-		AuthMiddleware: middleware.JWTProtected(jwtService),
-		Category:       categoryHandler,
-		Book:           bookHandler,
-		Order:          orderHandler,
+		App:             config.App,
+		User:            userHandler,
+		AuthMiddleware:  middleware.JWTProtected(jwtService, revokedTokenRepository, userRepository),
+		AdminMiddleware: middleware.RoleRequired(enum.RoleAdmin),
+		Idempotency:     middleware.Idempotency(idempotencyKeyRepository, idempotencyTTL),
+		QueryTimeout:    middleware.QueryTimeout(queryTimeout),
+		Category:        categoryHandler,
+		Book:            bookHandler,
+		Order:           orderHandler,
+		Stock:           stockHandler,
+		Payment:         paymentHandler,
+		Cart:            cartHandler,
+		Coupon:          couponHandler,
+		Config:          configHandler,
+		Jobs:            jobHandler,
+		Event:           eventHandler,
+		Webhook:         webhookHandler,
 	}
 	routeConfig.Setup()
 
-	// setup cron job
-	ctx := context.Background()
-	scheduler := cron.New(cron.WithLocation(time.Local))
-	orderCronjob := usecase.NewOrderCronJob(config.DB, config.Log, orderRepository)
-	_, err := scheduler.AddFunc("*/2 * * * *", func() { orderCronjob.CheckingOrderPaymentStatus(ctx) })
-	if err != nil {
-		slog.Error("Failed to add cron job", "error", err.Error())
-		os.Exit(1)
+	scheduler.Start()
+	slog.Info("Job scheduler started")
+	return scheduler
+}
+
+func registerJobs(scheduler *jobs.Scheduler, list []jobs.Job) {
+	for _, job := range list {
+		if err := scheduler.Register(job); err != nil {
+			slog.Error("Failed to register job", "job", job.Name, "error", err.Error())
+			os.Exit(1)
+		}
 	}
-	go scheduler.Start()
-	slog.Info("Cron job started")
 }
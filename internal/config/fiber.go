@@ -6,10 +6,18 @@ import (
 )
 
 func NewFiber(config *viper.Viper) *fiber.App {
+	// Body limit harus cukup untuk upload cover ditambah field form lainnya
+	config.SetDefault("IMAGE_MAX_SIZE_MB", defaultImageMaxSizeMB)
+	bodyLimit := fiber.DefaultBodyLimit
+	if imageLimit := (config.GetInt("IMAGE_MAX_SIZE_MB") + 1) * 1024 * 1024; imageLimit > bodyLimit {
+		bodyLimit = imageLimit
+	}
+
 	app := fiber.New(fiber.Config{
 		AppName:      config.GetString("APP_NAME"),
 		ErrorHandler: NewErrorHandler(),
 		Prefork:      config.GetBool("WEB_PREFORK"),
+		BodyLimit:    bodyLimit,
 	})
 
 	return app
@@ -1,30 +1,133 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/glebarez/sqlite"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewDatabase(viper *viper.Viper) *gorm.DB {
+// NewDatabase opens the database selected by DB_DRIVER (mysql, postgres or sqlite) with the
+// configured pool limits, queries are logged through logger
+func NewDatabase(viper *viper.Viper, logger *logrus.Logger) *gorm.DB {
+	viper.SetDefault("DB_DRIVER", "mysql")
+
+	return openDatabase(viper, logger, viper.GetString("DB_HOST"), viper.GetInt("DB_PORT"))
+}
+
+// NewReadReplicas opens every host in DB_REPLICA_HOSTS ("host:port,host:port") with the
+// credentials of the primary. Without replicas all reads go to the primary.
+func NewReadReplicas(viper *viper.Viper, logger *logrus.Logger, primary *gorm.DB) *repository.ReadReplicas {
+	viper.SetDefault("DB_REPLICA_HEALTH_CHECK_SECONDS", 10)
+
+	var replicas []*gorm.DB
+	for _, address := range strings.Split(viper.GetString("DB_REPLICA_HOSTS"), ",") {
+		address = strings.TrimSpace(address)
+		if address == "" {
+			continue
+		}
+		if viper.GetString("DB_DRIVER") == "sqlite" {
+			log.Fatalf("DB_REPLICA_HOSTS is not supported with sqlite")
+		}
+
+		host, port := address, viper.GetInt("DB_PORT")
+		if h, p, found := strings.Cut(address, ":"); found {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				log.Fatalf("invalid DB_REPLICA_HOSTS entry %q: %v", address, err)
+			}
+			host, port = h, n
+		}
+		replicas = append(replicas, openDatabase(viper, logger, host, port))
+	}
+
+	readReplicas := repository.NewReadReplicas(primary, logger, replicas...)
+	if len(replicas) > 0 {
+		interval := time.Duration(viper.GetInt("DB_REPLICA_HEALTH_CHECK_SECONDS")) * time.Second
+		if interval < time.Second {
+			log.Fatalf("invalid DB_REPLICA_HEALTH_CHECK_SECONDS: must be at least 1")
+		}
+		readReplicas.CheckHealth(context.Background())
+		go readReplicas.Monitor(context.Background(), interval)
+	}
+	return readReplicas
+}
+
+func openDatabase(viper *viper.Viper, logger *logrus.Logger, host string, port int) *gorm.DB {
+	viper.SetDefault("DB_SSLMODE", "disable")
+	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	viper.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	viper.SetDefault("DB_CONN_MAX_LIFETIME_MINUTES", 30)
+	viper.SetDefault("DB_CONN_MAX_IDLE_TIME_MINUTES", 5)
+	viper.SetDefault("DB_SLOW_QUERY_MS", 200)
+
 	username := viper.GetString("DB_USER")
 	password := viper.GetString("DB_PASS")
-	host := viper.GetString("DB_HOST")
-	port := viper.GetInt("DB_PORT")
 	database := viper.GetString("DB_NAME")
 
-	// DSN MySQL
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		username, password, host, port, database)
+	var dialector gorm.Dialector
+	switch driver := viper.GetString("DB_DRIVER"); driver {
+	case "mysql":
+		// DSN MySQL
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			username, password, host, port, database)
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+			host, port, username, password, database, viper.GetString("DB_SSLMODE"))
+		dialector = postgres.Open(dsn)
+	case "sqlite":
+		// DB_NAME adalah path file, atau :memory: untuk database sementara
+		dialector = sqlite.Open(SQLiteDSN(database))
+	default:
+		log.Fatalf("unknown DB_DRIVER: %s", driver)
+	}
 
 	// Open DB connection
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: NewGormLogger(logger, time.Duration(viper.GetInt("DB_SLOW_QUERY_MS"))*time.Millisecond),
+	})
 	if err != nil {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("failed to get database pool: %v", err)
+	}
+	maxOpen, maxIdle := viper.GetInt("DB_MAX_OPEN_CONNS"), viper.GetInt("DB_MAX_IDLE_CONNS")
+	if maxOpen < 1 || maxIdle < 0 || maxIdle > maxOpen {
+		log.Fatalf("invalid DB_MAX_OPEN_CONNS/DB_MAX_IDLE_CONNS: need 0 <= idle <= open and open >= 1")
+	}
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(maxIdle)
+	sqlDB.SetConnMaxLifetime(time.Duration(viper.GetInt("DB_CONN_MAX_LIFETIME_MINUTES")) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(viper.GetInt("DB_CONN_MAX_IDLE_TIME_MINUTES")) * time.Minute)
+
 	return db
 }
+
+// SQLiteDSN enables foreign keys and a busy timeout on every connection. An in-memory database
+// uses a shared cache so all connections of the pool see the same data.
+func SQLiteDSN(database string) string {
+	const pragmas = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	if database == ":memory:" {
+		return "file::memory:?cache=shared&" + pragmas
+	}
+	return "file:" + database + "?_pragma=journal_mode(WAL)&" + pragmas
+}
+
+// IsInMemory reports whether the database only lives as long as this process
+func IsInMemory(viper *viper.Viper) bool {
+	return viper.GetString("DB_DRIVER") == "sqlite" && viper.GetString("DB_NAME") == ":memory:"
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// gormLogger sends gorm logs to logrus. Queries slower than slowThreshold are logged as
+// warnings, every other query only at debug level.
+type gormLogger struct {
+	log           *logrus.Logger
+	level         logger.LogLevel
+	slowThreshold time.Duration
+}
+
+func NewGormLogger(log *logrus.Logger, slowThreshold time.Duration) logger.Interface {
+	return &gormLogger{log: log, level: logger.Info, slowThreshold: slowThreshold}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.log.WithContext(ctx).Infof(msg, args...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.log.WithContext(ctx).Warnf(msg, args...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.log.WithContext(ctx).Errorf(msg, args...)
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if !slow && !failed && !l.log.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	sql, rows := fc()
+	entry := l.log.WithContext(ctx).WithFields(logrus.Fields{
+		"sql":         sql,
+		"duration_ms": elapsed.Milliseconds(),
+		"rows":        rows,
+	})
+	switch {
+	case failed && l.level >= logger.Error:
+		entry.WithError(err).Error("query failed")
+	case slow && l.level >= logger.Warn:
+		entry.Warn("slow query")
+	case l.level >= logger.Info:
+		entry.Debug("query")
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NewIdempotencyTTL reads how many hours an Idempotency-Key and its response are kept. Zero
+// would turn idempotency off without anyone noticing, so it stops the application.
+func NewIdempotencyTTL(viper *viper.Viper) time.Duration {
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", 24)
+
+	hours := viper.GetInt("IDEMPOTENCY_KEY_TTL")
+	if hours < 1 {
+		log.Fatalf("invalid IDEMPOTENCY_KEY_TTL: must be at least 1")
+	}
+	return time.Duration(hours) * time.Hour
+}
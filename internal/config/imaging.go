@@ -0,0 +1,22 @@
+package config
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/imaging"
+	"github.com/spf13/viper"
+)
+
+// defaultImageMaxSizeMB juga dipakai NewFiber, yang dipanggil sebelum NewImageOptions
+const defaultImageMaxSizeMB = 5
+
+// NewImageOptions loads cover upload limits from configuration
+func NewImageOptions(viper *viper.Viper) imaging.Options {
+	viper.SetDefault("IMAGE_MAX_SIZE_MB", defaultImageMaxSizeMB)
+	viper.SetDefault("IMAGE_MIN_DIMENSION", 100)
+	viper.SetDefault("IMAGE_MAX_DIMENSION", 5000)
+
+	return imaging.Options{
+		MaxBytes:     viper.GetInt64("IMAGE_MAX_SIZE_MB") * 1024 * 1024,
+		MinDimension: viper.GetInt("IMAGE_MIN_DIMENSION"),
+		MaxDimension: viper.GetInt("IMAGE_MAX_DIMENSION"),
+	}
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NewJobRunRetention reads how long finished job runs are kept before job-run-cleanup deletes them
+func NewJobRunRetention(viper *viper.Viper) time.Duration {
+	viper.SetDefault("JOB_RUN_RETENTION_DAYS", 30)
+
+	days := viper.GetInt("JOB_RUN_RETENTION_DAYS")
+	if days < 1 {
+		log.Fatalf("invalid JOB_RUN_RETENTION_DAYS: must be at least 1")
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -11,15 +11,18 @@ import (
 func LoadJWTConfig() *auth.JWTConfig {
 	viper.SetDefault("JWT_SECRET_KEY", "your_default_secret")
 	viper.SetDefault("JWT_ISSUER", "your_app_name")
-	viper.SetDefault("JWT_EXPIRE_DURATION", "60") // in minutes
+	viper.SetDefault("JWT_EXPIRE_DURATION", "15")            // in minutes
+	viper.SetDefault("JWT_REFRESH_EXPIRE_DURATION", "10080") // in minutes (7 days)
 
 	secret := viper.GetString("JWT_SECRET_KEY")
 	issuer := viper.GetString("JWT_ISSUER")
 	expireMinutes := viper.GetInt("JWT_EXPIRE_DURATION")
+	refreshExpireMinutes := viper.GetInt("JWT_REFRESH_EXPIRE_DURATION")
 
 	return &auth.JWTConfig{
-		SecretKey:      secret,
-		Issuer:         issuer,
-		ExpireDuration: time.Duration(expireMinutes) * time.Minute,
+		SecretKey:             secret,
+		Issuer:                issuer,
+		ExpireDuration:        time.Duration(expireMinutes) * time.Minute,
+		RefreshExpireDuration: time.Duration(refreshExpireMinutes) * time.Minute,
 	}
 }
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+)
+
+// NewOrderRules loads the order business rules, an invalid configuration stops the application
+func NewOrderRules(viper *viper.Viper) usecase.OrderRules {
+	viper.SetDefault("ORDER_PAYMENT_WINDOW_MINUTES", 15)
+	viper.SetDefault("ORDER_EXPIRY_SCHEDULE", "*/2 * * * *")
+	viper.SetDefault("ORDER_MAX_QUANTITY_PER_ITEM", 5)
+	viper.SetDefault("ORDER_MAX_ITEMS", 5)
+	viper.SetDefault("ORDER_MIN_VALUE", "0")
+	viper.SetDefault("ORDER_CATEGORY_MAX_QUANTITY", "")
+
+	minOrderValue, err := money.Parse(viper.GetString("ORDER_MIN_VALUE"))
+	if err != nil {
+		log.Fatalf("invalid ORDER_MIN_VALUE: %v", err)
+	}
+
+	categoryLimits, err := parseCategoryLimits(viper.GetString("ORDER_CATEGORY_MAX_QUANTITY"))
+	if err != nil {
+		log.Fatalf("invalid ORDER_CATEGORY_MAX_QUANTITY: %v", err)
+	}
+
+	rules := usecase.OrderRules{
+		PaymentWindow:              time.Duration(viper.GetInt("ORDER_PAYMENT_WINDOW_MINUTES")) * time.Minute,
+		ExpirySchedule:             viper.GetString("ORDER_EXPIRY_SCHEDULE"),
+		MaxQuantityPerItem:         viper.GetInt("ORDER_MAX_QUANTITY_PER_ITEM"),
+		MaxItemsPerOrder:           viper.GetInt("ORDER_MAX_ITEMS"),
+		MinOrderValue:              minOrderValue,
+		CategoryMaxQuantityPerItem: categoryLimits,
+	}
+
+	if _, err := cron.ParseStandard(rules.ExpirySchedule); err != nil {
+		log.Fatalf("invalid ORDER_EXPIRY_SCHEDULE: %v", err)
+	}
+	if err := rules.Validate(); err != nil {
+		log.Fatalf("invalid order rules: %v", err)
+	}
+	return rules
+}
+
+// parseCategoryLimits membaca format "category_id:limit" dipisah koma, mis. "3:2,7:1"
+func parseCategoryLimits(raw string) (map[int]int, error) {
+	limits := make(map[int]int)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		categoryRaw, limitRaw, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected category_id:limit, got %q", pair)
+		}
+		categoryID, err := strconv.Atoi(strings.TrimSpace(categoryRaw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid category id %q", categoryRaw)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitRaw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q", limitRaw)
+		}
+		limits[categoryID] = limit
+	}
+	return limits, nil
+}
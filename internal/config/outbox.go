@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/outbox"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewOutboxOptions reads the dispatcher settings, invalid values stop the application
+func NewOutboxOptions(viper *viper.Viper) outbox.Options {
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL_SECONDS", 2)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+
+	options := outbox.Options{
+		BatchSize:    viper.GetInt("OUTBOX_BATCH_SIZE"),
+		PollInterval: time.Duration(viper.GetInt("OUTBOX_POLL_INTERVAL_SECONDS")) * time.Second,
+		MaxAttempts:  viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		Backoff:      10 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+	if options.BatchSize < 1 {
+		log.Fatalf("invalid OUTBOX_BATCH_SIZE: must be at least 1")
+	}
+	if options.PollInterval < time.Second || options.PollInterval > 30*time.Second {
+		log.Fatalf("invalid OUTBOX_POLL_INTERVAL_SECONDS: must be between 1 and 30")
+	}
+	if options.MaxAttempts < 1 {
+		log.Fatalf("invalid OUTBOX_MAX_ATTEMPTS: must be at least 1")
+	}
+	return options
+}
+
+// NewOutboxRetention reads how long PUBLISHED events are kept before outbox-cleanup deletes them
+func NewOutboxRetention(viper *viper.Viper) time.Duration {
+	viper.SetDefault("OUTBOX_RETENTION_DAYS", 30)
+
+	days := viper.GetInt("OUTBOX_RETENTION_DAYS")
+	if days < 1 {
+		log.Fatalf("invalid OUTBOX_RETENTION_DAYS: must be at least 1")
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// NewOutboxSinks builds the sinks events are delivered to. The handler sink is always present
+// and returned separately so in-process subscribers can be registered on it.
+func NewOutboxSinks(viper *viper.Viper, logger *logrus.Logger) ([]outbox.Sink, *outbox.HandlerSink) {
+	viper.SetDefault("OUTBOX_HTTP_TIMEOUT_SECONDS", 10)
+	viper.SetDefault("OUTBOX_BROKER_TOPIC_PREFIX", "bookstore.")
+
+	handlers := outbox.NewHandlerSink()
+	sinks := []outbox.Sink{handlers}
+
+	if url := viper.GetString("OUTBOX_HTTP_URL"); url != "" {
+		timeout := time.Duration(viper.GetInt("OUTBOX_HTTP_TIMEOUT_SECONDS")) * time.Second
+		sinks = append(sinks, outbox.NewHTTPSink(url, viper.GetString("OUTBOX_HTTP_SECRET"), timeout))
+	}
+
+	switch broker := viper.GetString("OUTBOX_BROKER"); broker {
+	case "":
+	case "log":
+		sinks = append(sinks, outbox.NewBrokerSink(&outbox.LogPublisher{Log: logger}, viper.GetString("OUTBOX_BROKER_TOPIC_PREFIX")))
+	default:
+		log.Fatalf("unknown OUTBOX_BROKER: %s", broker)
+	}
+
+	return sinks, handlers
+}
+
+// SubscribeOutboxHandlers registers the in-process subscribers on the handler sink returned by
+// NewOutboxSinks. It must be called before the dispatcher starts.
+func SubscribeOutboxHandlers(handlers *outbox.HandlerSink, logger *logrus.Logger) {
+	// Stok menipis dicatat sebagai warning supaya terlihat di log operasional
+	handlers.Subscribe(enum.EventStockLow, func(ctx context.Context, event outbox.Event) error {
+		var payload model.StockLowEvent
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			// Payload rusak tidak akan membaik dengan retry
+			logger.Errorf("outbox event %s: invalid %s payload: %v", event.ID, event.Type, err)
+			return nil
+		}
+		logger.WithFields(logrus.Fields{
+			"book_id":   payload.BookID,
+			"stock":     payload.Stock,
+			"threshold": payload.Threshold,
+		}).Warnf("stock of %q is low, restock needed", payload.Title)
+		return nil
+	})
+}
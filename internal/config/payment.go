@@ -0,0 +1,28 @@
+package config
+
+import (
+	"log"
+
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewPaymentProvider picks the payment gateway from PAYMENT_PROVIDER. PAYMENT_WEBHOOK_SECRET has
+// no default, a secret published in the repository would let anyone forge a paid callback.
+func NewPaymentProvider(viper *viper.Viper, logger *logrus.Logger) payment.Provider {
+	viper.SetDefault("PAYMENT_PROVIDER", "fake")
+
+	secret := viper.GetString("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatalf("PAYMENT_WEBHOOK_SECRET is required")
+	}
+
+	switch provider := viper.GetString("PAYMENT_PROVIDER"); provider {
+	case "fake":
+		return payment.NewFakeProvider(secret, logger)
+	default:
+		log.Fatalf("unknown PAYMENT_PROVIDER: %s", provider)
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"log"
+
+	"github.com/fathirarya/online-bookstore-api/internal/storage"
+	"github.com/spf13/viper"
+)
+
+// NewImageStore picks the cover image backend from STORAGE_DRIVER (local or s3)
+func NewImageStore(viper *viper.Viper) storage.ImageStore {
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "./storage")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_USE_PATH_STYLE", true)
+
+	switch driver := viper.GetString("STORAGE_DRIVER"); driver {
+	case "local":
+		store, err := storage.NewLocalImageStore(viper.GetString("STORAGE_LOCAL_DIR"))
+		if err != nil {
+			log.Fatalf("failed to setup local image store: %v", err)
+		}
+		return store
+	case "s3":
+		store, err := storage.NewS3ImageStore(
+			viper.GetString("S3_ENDPOINT"),
+			viper.GetString("S3_REGION"),
+			viper.GetString("S3_BUCKET"),
+			viper.GetString("S3_ACCESS_KEY"),
+			viper.GetString("S3_SECRET_KEY"),
+			viper.GetBool("S3_USE_PATH_STYLE"),
+		)
+		if err != nil {
+			log.Fatalf("failed to setup s3 image store: %v", err)
+		}
+		return store
+	default:
+		log.Fatalf("unknown STORAGE_DRIVER: %s", driver)
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/webhook"
+	"github.com/spf13/viper"
+)
+
+// NewWebhookOptions reads the partner webhook delivery settings, invalid values stop the application
+func NewWebhookOptions(viper *viper.Viper) webhook.Options {
+	viper.SetDefault("WEBHOOK_TIMEOUT_SECONDS", 10)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_DISABLE_AFTER", 20)
+	viper.SetDefault("WEBHOOK_CONCURRENCY", 4)
+
+	options := webhook.Options{
+		Timeout:      time.Duration(viper.GetInt("WEBHOOK_TIMEOUT_SECONDS")) * time.Second,
+		MaxAttempts:  viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		DisableAfter: viper.GetInt("WEBHOOK_DISABLE_AFTER"),
+		Concurrency:  viper.GetInt("WEBHOOK_CONCURRENCY"),
+		BatchSize:    50,
+		PollInterval: 2 * time.Second,
+		Backoff:      30 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+	}
+	if options.Timeout < time.Second || options.Timeout > 30*time.Second {
+		log.Fatalf("invalid WEBHOOK_TIMEOUT_SECONDS: must be between 1 and 30")
+	}
+	if options.MaxAttempts < 1 {
+		log.Fatalf("invalid WEBHOOK_MAX_ATTEMPTS: must be at least 1")
+	}
+	if options.DisableAfter < 1 {
+		log.Fatalf("invalid WEBHOOK_DISABLE_AFTER: must be at least 1")
+	}
+	if options.Concurrency < 1 {
+		log.Fatalf("invalid WEBHOOK_CONCURRENCY: must be at least 1")
+	}
+	return options
+}
+
+// NewLowStockThreshold reads the stock level at or below which a stock.low event is published
+func NewLowStockThreshold(viper *viper.Viper) int {
+	viper.SetDefault("STOCK_LOW_THRESHOLD", 5)
+
+	threshold := viper.GetInt("STOCK_LOW_THRESHOLD")
+	if threshold < 0 {
+		log.Fatalf("invalid STOCK_LOW_THRESHOLD: must not be negative")
+	}
+	return threshold
+}
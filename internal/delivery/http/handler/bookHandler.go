@@ -1,10 +1,10 @@
 package handler
 
 import (
-	"mime/multipart"
 	"strconv"
 
 	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
 	"github.com/fathirarya/online-bookstore-api/internal/usecase"
 	"github.com/fathirarya/online-bookstore-api/internal/utils"
 	"github.com/go-playground/validator/v10"
@@ -31,10 +31,21 @@ func (h *BookHandler) Create(ctx *fiber.Ctx) error {
 	var req model.CreateBookRequest
 	req.Title = ctx.FormValue("title")
 	req.Author = ctx.FormValue("author")
-	req.Price, _ = strconv.ParseFloat(ctx.FormValue("price"), 64)
+	req.Price, _ = money.Parse(ctx.FormValue("price"))
 	req.Year, _ = strconv.Atoi(ctx.FormValue("year"))
 	req.CategoryID, _ = strconv.Atoi(ctx.FormValue("category_id"))
 
+	// parse optional initial stock
+	if stockStr := ctx.FormValue("stock"); stockStr != "" {
+		stock, err := strconv.Atoi(stockStr)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+				Message: "invalid stock value",
+			})
+		}
+		req.Stock = stock
+	}
+
 	// Validate input fields
 	if err := h.Validate.Struct(req); err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
@@ -43,32 +54,17 @@ func (h *BookHandler) Create(ctx *fiber.Ctx) error {
 		})
 	}
 
-	// Handle file upload
+	// Handle file upload, isi file divalidasi di usecase
 	fileHeader, err := ctx.FormFile("image")
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
 			Message: "image file is required",
 		})
 	}
-
-	file, err := fileHeader.Open()
-	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
-			Message: "failed to open image file",
-		})
-	}
-	defer file.Close()
-
-	// Convert file to base64
-	imageBase64, err := utils.FileToBase64(file)
-	if err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
-			Message: "failed to encode image",
-		})
-	}
+	req.Image = fileHeader
 
 	// Call usecase
-	response, err := h.UseCase.CreateBook(ctx.Context(), &req, imageBase64)
+	response, err := h.UseCase.CreateBook(ctx.UserContext(), &req)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
@@ -87,11 +83,45 @@ func (h *BookHandler) Create(ctx *fiber.Ctx) error {
 }
 
 func (h *BookHandler) List(ctx *fiber.Ctx) error {
-	page := ctx.QueryInt("page", 1)
-	size := ctx.QueryInt("size", 10)
+	// Parse pagination, filter & sort dari query string
+	var req model.ListBooksRequest
+	if err := ctx.QueryParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	// Mode keyset (opt-in) jika client mengirim cursor atau limit
+	if req.Cursor != "" || req.Limit > 0 {
+		books, next, prev, err := h.UseCase.ListBooksByCursor(ctx.UserContext(), &req)
+		if err != nil {
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+					Message: fiberErr.Message,
+				})
+			}
+			return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+				Message: "internal server error",
+			})
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.BookResponse]{
+			Size:       len(books),
+			NextCursor: next,
+			PrevCursor: prev,
+			Data:       books,
+		})
+	}
 
 	// Call usecase
-	books, pageNum, pageSize, totalItems, totalPages, err := h.UseCase.ListBooks(ctx.Context(), page, size)
+	books, pageNum, pageSize, totalItems, totalPages, err := h.UseCase.ListBooks(ctx.UserContext(), &req)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
@@ -121,7 +151,7 @@ func (h *BookHandler) GetByID(ctx *fiber.Ctx) error {
 		})
 	}
 
-	book, err := h.UseCase.GetBookByID(ctx.Context(), id)
+	book, err := h.UseCase.GetBookByID(ctx.UserContext(), id)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
@@ -139,6 +169,35 @@ func (h *BookHandler) GetByID(ctx *fiber.Ctx) error {
 	})
 }
 
+// Cover streams the book cover image from the image store, ?size=small|medium|large for thumbnails
+func (h *BookHandler) Cover(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid book id",
+		})
+	}
+
+	// Query buku memakai UserContext yang dibatasi QueryTimeout. Body dikirim lewat SendStream
+	// setelah handler selesai dan UserContext sudah dibatalkan, jadi file dibuka dengan ctx.Context()
+	object, err := h.UseCase.GetBookCover(ctx.UserContext(), ctx.Context(), id, ctx.Query("size"))
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	// Body ditutup oleh fasthttp setelah selesai dikirim
+	ctx.Set(fiber.HeaderContentType, object.ContentType)
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return ctx.Status(fiber.StatusOK).SendStream(object.Body, int(object.Size))
+}
+
 func (h *BookHandler) Update(ctx *fiber.Ctx) error {
 	//  Parse ID dari param
 	id, err := ctx.ParamsInt("id")
@@ -155,7 +214,7 @@ func (h *BookHandler) Update(ctx *fiber.Ctx) error {
 
 	// parse optional price
 	if priceStr := ctx.FormValue("price"); priceStr != "" {
-		price, err := strconv.ParseFloat(priceStr, 64)
+		price, err := money.Parse(priceStr)
 		if err != nil || price <= 0 {
 			return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
 				Message: "invalid price value",
@@ -187,20 +246,12 @@ func (h *BookHandler) Update(ctx *fiber.Ctx) error {
 	}
 
 	// Ambil file image (optional)
-	var file multipart.File
 	if fileHeader, err := ctx.FormFile("image"); err == nil {
-		f, err := fileHeader.Open()
-		if err != nil {
-			return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
-				Message: "failed to open image file",
-			})
-		}
-		defer f.Close()
-		file = f
+		req.Image = fileHeader
 	}
 
 	//  Panggil usecase
-	response, err := h.UseCase.UpdateBook(ctx.Context(), id, &req, file)
+	response, err := h.UseCase.UpdateBook(ctx.UserContext(), id, &req)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
@@ -228,7 +279,7 @@ func (h *BookHandler) Delete(ctx *fiber.Ctx) error {
 	}
 
 	//  Panggil usecase untuk delete
-	if err := h.UseCase.DeleteBook(ctx.Context(), id); err != nil {
+	if err := h.UseCase.DeleteBook(ctx.UserContext(), id); err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
 				Message: fiberErr.Message,
@@ -247,7 +298,7 @@ func (h *BookHandler) Delete(ctx *fiber.Ctx) error {
 
 func (h *BookHandler) GetTotalBooks(ctx *fiber.Ctx) error {
 	// 1️⃣ Panggil UseCase
-	response, err := h.UseCase.GetTotalBooks(ctx.Context())
+	response, err := h.UseCase.GetTotalBooks(ctx.UserContext())
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
@@ -268,7 +319,7 @@ func (h *BookHandler) GetTotalBooks(ctx *fiber.Ctx) error {
 
 func (h *BookHandler) GetBookPriceStats(ctx *fiber.Ctx) error {
 	//  Panggil UseCase
-	stats, err := h.UseCase.GetBookPriceStats(ctx.Context())
+	stats, err := h.UseCase.GetBookPriceStats(ctx.UserContext())
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CartHandler struct {
+	Log     *logrus.Logger
+	UseCase *usecase.CartUseCase
+}
+
+func NewCartHandler(useCase *usecase.CartUseCase, logger *logrus.Logger) *CartHandler {
+	return &CartHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (h *CartHandler) Get(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.GetCart(ctx.UserContext(), userID)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CartResponse]{
+		Data: response,
+	})
+}
+
+func (h *CartHandler) AddItem(ctx *fiber.Ctx) error {
+	var request model.AddCartItemRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid request body",
+		})
+	}
+
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.AddItem(ctx.UserContext(), userID, &request)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CartResponse]{
+		Data: response,
+	})
+}
+
+func (h *CartHandler) UpdateItem(ctx *fiber.Ctx) error {
+	bookID, err := ctx.ParamsInt("book_id")
+	if err != nil || bookID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid book ID",
+		})
+	}
+
+	var request model.UpdateCartItemRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid request body",
+		})
+	}
+
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.UpdateItem(ctx.UserContext(), userID, bookID, &request)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CartResponse]{
+		Data: response,
+	})
+}
+
+func (h *CartHandler) RemoveItem(ctx *fiber.Ctx) error {
+	bookID, err := ctx.ParamsInt("book_id")
+	if err != nil || bookID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid book ID",
+		})
+	}
+
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.RemoveItem(ctx.UserContext(), userID, bookID)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CartResponse]{
+		Data: response,
+	})
+}
+
+func (h *CartHandler) Checkout(ctx *fiber.Ctx) error {
+	// Body optional, hanya berisi coupon_code
+	var request model.CheckoutCartRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+				Message: "Invalid request body",
+			})
+		}
+	}
+
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.Checkout(ctx.UserContext(), userID, &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok && fiberErr.Code == fiber.StatusBadRequest &&
+			(strings.HasPrefix(fiberErr.Message, "maximum ") || strings.HasPrefix(fiberErr.Message, "minimum order value")) {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: "Validation failed",
+				Errors:  map[string]string{"items": fiberErr.Message},
+			})
+		}
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.OrderResponse]{
+		Data: response,
+	})
+}
+
+func (h *CartHandler) errorResponse(ctx *fiber.Ctx, err error) error {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		if fiberErr.Code == fiber.StatusInternalServerError {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: "Internal server error, please try again later",
+			})
+		}
+		return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+			Message: fiberErr.Message,
+		})
+	}
+
+	return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+		Message: "Internal server error",
+	})
+}
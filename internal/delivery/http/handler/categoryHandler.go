@@ -42,7 +42,7 @@ func (h *CategoryHandler) Create(ctx *fiber.Ctx) error {
 	}
 
 	// 3️⃣ Call UseCase
-	response, err := h.UseCase.CreateCategory(ctx.Context(), &request)
+	response, err := h.UseCase.CreateCategory(ctx.UserContext(), &request)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			// Case: category already exists
@@ -72,12 +72,45 @@ func (h *CategoryHandler) Create(ctx *fiber.Ctx) error {
 }
 
 func (h *CategoryHandler) List(ctx *fiber.Ctx) error {
-	// Parse query params with default values
-	page := ctx.QueryInt("page", 1)
-	size := ctx.QueryInt("size", 10)
+	// Parse query params (pagination, search & sort)
+	var request model.ListCategoriesRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
 
-	// Call UseCase
-	data, total, totalPages, err := h.UseCase.ListCategories(ctx.Context(), page, size)
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	// Mode keyset (opt-in) jika client mengirim cursor atau limit
+	if request.Cursor != "" || request.Limit > 0 {
+		data, next, prev, err := h.UseCase.ListCategoriesByCursor(ctx.UserContext(), &request)
+		if err != nil {
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+					Message: fiberErr.Message,
+				})
+			}
+			return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+				Message: "internal server error",
+			})
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.CategoryResponse]{
+			Size:       len(data),
+			NextCursor: next,
+			PrevCursor: prev,
+			Data:       data,
+		})
+	}
+
+	// Call UseCase (page & size diisi default oleh usecase)
+	data, total, totalPages, err := h.UseCase.ListCategories(ctx.UserContext(), &request)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
@@ -91,8 +124,8 @@ func (h *CategoryHandler) List(ctx *fiber.Ctx) error {
 
 	// Return success response with pagination metadata
 	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.CategoryResponse]{
-		Page:       page,
-		Size:       size,
+		Page:       request.Page,
+		Size:       request.Size,
 		TotalItems: total,
 		TotalPages: totalPages,
 		Data:       data,
@@ -125,7 +158,7 @@ func (h *CategoryHandler) Update(ctx *fiber.Ctx) error {
 	}
 
 	// Call UseCase
-	response, err := h.UseCase.UpdateCategory(ctx.Context(), id, &request)
+	response, err := h.UseCase.UpdateCategory(ctx.UserContext(), id, &request)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			// Case: category not found
@@ -169,7 +202,7 @@ func (h *CategoryHandler) Delete(ctx *fiber.Ctx) error {
 	}
 
 	// Call UseCase
-	if err := h.UseCase.DeleteCategory(ctx.Context(), id); err != nil {
+	if err := h.UseCase.DeleteCategory(ctx.UserContext(), id); err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
 			if fiberErr.Code == fiber.StatusNotFound {
 				return ctx.Status(fiber.StatusNotFound).JSON(model.WebResponse[any]{
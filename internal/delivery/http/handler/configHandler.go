@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConfigHandler exposes read-only configuration that clients need, never secrets
+type ConfigHandler struct {
+	Rules usecase.OrderRules
+}
+
+func NewConfigHandler(rules usecase.OrderRules) *ConfigHandler {
+	return &ConfigHandler{
+		Rules: rules,
+	}
+}
+
+func (h *ConfigHandler) Public(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.PublicConfigResponse]{
+		Data: h.Rules.Public(),
+	})
+}
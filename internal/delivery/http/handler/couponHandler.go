@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type CouponHandler struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.CouponUseCase
+	Validate *validator.Validate
+}
+
+func NewCouponHandler(useCase *usecase.CouponUseCase, logger *logrus.Logger, validate *validator.Validate) *CouponHandler {
+	return &CouponHandler{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *CouponHandler) Create(ctx *fiber.Ctx) error {
+	var request model.CreateCouponRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.CreateCoupon(ctx.UserContext(), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			if fiberErr.Code == fiber.StatusConflict {
+				return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+					Message: "validation failed",
+					Errors:  map[string]string{"code": "coupon code already exists"},
+				})
+			}
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.CouponResponse]{
+		Data: response,
+	})
+}
+
+func (h *CouponHandler) List(ctx *fiber.Ctx) error {
+	var request model.ListCouponsRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	data, total, totalPages, err := h.UseCase.ListCoupons(ctx.UserContext(), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.CouponResponse]{
+		Page:       request.Page,
+		Size:       request.Size,
+		TotalItems: total,
+		TotalPages: totalPages,
+		Data:       data,
+	})
+}
+
+func (h *CouponHandler) GetByID(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid coupon id",
+		})
+	}
+
+	response, err := h.UseCase.GetCoupon(ctx.UserContext(), id)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CouponResponse]{
+		Data: response,
+	})
+}
+
+func (h *CouponHandler) Update(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid coupon id",
+		})
+	}
+
+	var request model.UpdateCouponRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.UpdateCoupon(ctx.UserContext(), id, &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			if fiberErr.Code == fiber.StatusConflict {
+				return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+					Message: "validation failed",
+					Errors:  map[string]string{"code": "coupon code already exists"},
+				})
+			}
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.CouponResponse]{
+		Data: response,
+	})
+}
+
+func (h *CouponHandler) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid coupon id",
+		})
+	}
+
+	if err := h.UseCase.DeleteCoupon(ctx.UserContext(), id); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[any]{
+		Message: "coupon deleted successfully",
+	})
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type EventHandler struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.EventUseCase
+	Validate *validator.Validate
+}
+
+func NewEventHandler(useCase *usecase.EventUseCase, logger *logrus.Logger, validate *validator.Validate) *EventHandler {
+	return &EventHandler{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *EventHandler) List(ctx *fiber.Ctx) error {
+	var request model.ListEventsRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	data, total, totalPages, err := h.UseCase.ListEvents(ctx.UserContext(), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.OutboxEventResponse]{
+		Page:       request.Page,
+		Size:       request.Size,
+		TotalItems: total,
+		TotalPages: totalPages,
+		Data:       data,
+	})
+}
+
+func (h *EventHandler) Replay(ctx *fiber.Ctx) error {
+	var request model.ReplayEventsRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.ReplayEvents(ctx.UserContext(), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.ReplayEventsResponse]{
+		Data: response,
+	})
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type JobHandler struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.JobUseCase
+	Validate *validator.Validate
+}
+
+func NewJobHandler(useCase *usecase.JobUseCase, logger *logrus.Logger, validate *validator.Validate) *JobHandler {
+	return &JobHandler{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *JobHandler) List(ctx *fiber.Ctx) error {
+	response, err := h.UseCase.ListJobs(ctx.UserContext())
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.JobResponse]{
+		Data: response,
+	})
+}
+
+func (h *JobHandler) Runs(ctx *fiber.Ctx) error {
+	var request model.ListJobRunsRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	data, total, totalPages, err := h.UseCase.ListRuns(ctx.UserContext(), ctx.Params("name"), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.JobRunResponse]{
+		Page:       request.Page,
+		Size:       request.Size,
+		TotalItems: total,
+		TotalPages: totalPages,
+		Data:       data,
+	})
+}
+
+// Trigger menjalankan job sekarang, response 202 berisi run yang baru dibuat
+func (h *JobHandler) Trigger(ctx *fiber.Ctx) error {
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.TriggerJob(ctx.UserContext(), ctx.Params("name"), adminID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(model.WebResponse[*model.JobRunResponse]{
+		Data: response,
+	})
+}
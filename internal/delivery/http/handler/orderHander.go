@@ -1,7 +1,10 @@
 package handler
 
 import (
-	"strconv"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/fathirarya/online-bookstore-api/internal/usecase"
@@ -38,7 +41,7 @@ func (h *OrderHandler) Create(ctx *fiber.Ctx) error {
 	}
 
 	// Panggil UseCase untuk membuat order
-	response, err := h.UseCase.CreateOrder(ctx.Context(), &request, userID)
+	response, err := h.UseCase.CreateOrder(ctx.UserContext(), &request, userID)
 	if err != nil {
 		// Handle fiber.Error dari UseCase
 		if fiberErr, ok := err.(*fiber.Error); ok {
@@ -50,10 +53,11 @@ func (h *OrderHandler) Create(ctx *fiber.Ctx) error {
 				if msg == "validation failed, please check your input" {
 					errorsMap["body"] = "Please check your input fields"
 					msg = "Request validation failed"
-				} else if msg == "maximum 5 books per transaction" {
-					errorsMap["items"] = "You can order maximum 5 books per transaction"
+				} else if strings.HasPrefix(msg, "maximum ") || strings.HasPrefix(msg, "minimum order value") {
+					// Batas diambil dari OrderRules, pesan usecase sudah berisi angkanya
+					errorsMap["items"] = msg
 					msg = "Validation failed"
-				} else if msg == "book not found" {
+				} else if strings.HasPrefix(msg, "book not found") {
 					errorsMap["book"] = "One or more books not found"
 					msg = "Validation failed"
 				}
@@ -89,17 +93,86 @@ func (h *OrderHandler) Create(ctx *fiber.Ctx) error {
 	})
 }
 
-func (h *OrderHandler) Pay(ctx *fiber.Ctx) error {
-	// 1. Ambil orderID dari URL param
-	orderIDStr := ctx.Params("id")
-	orderID, err := strconv.Atoi(orderIDStr)
+func (h *OrderHandler) List(ctx *fiber.Ctx) error {
+	// Ambil userID dari token (middleware)
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	var request model.ListOrdersRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid query parameters",
+		})
+	}
+
+	// Mode keyset (opt-in) jika client mengirim cursor atau limit
+	if request.Cursor != "" || request.Limit > 0 {
+		response, next, prev, err := h.UseCase.GetOrdersByUserCursor(ctx.UserContext(), userID, &request)
+		if err != nil {
+			if fiberErr, ok := err.(*fiber.Error); ok {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: fiberErr.Message,
+				})
+			}
+			return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+				Message: "Internal server error",
+			})
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderListResponse]{
+			Size:       len(response.Orders),
+			NextCursor: next,
+			PrevCursor: prev,
+			Data:       response,
+		})
+	}
+
+	// Panggil UseCase untuk ambil daftar order per halaman
+	response, page, size, totalItems, totalPages, err := h.UseCase.GetOrdersByUser(ctx.UserContext(), userID, &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			// InternalServerError
+			if fiberErr.Code == fiber.StatusInternalServerError {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: "Internal server error, please try again later",
+				})
+			}
+
+			// Default fallback
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+
+		// Non-fiber error fallback
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	// Response sukses with paging
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderListResponse]{
+		Page:       page,
+		Size:       size,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		Data:       response,
+	})
+}
+
+// GetByID returns the detail of the user's own order
+func (h *OrderHandler) GetByID(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
 	if err != nil || orderID <= 0 {
 		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
 			Message: "Invalid order ID",
 		})
 	}
 
-	// 2. Ambil userID dari token (middleware)
 	userID, ok := ctx.Locals("user_id").(int)
 	if !ok {
 		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
@@ -107,34 +180,94 @@ func (h *OrderHandler) Pay(ctx *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Panggil UseCase untuk membayar order
-	response, err := h.UseCase.PayOrder(ctx.Context(), orderID, userID)
+	response, err := h.UseCase.GetOrder(ctx.UserContext(), userID, orderID)
 	if err != nil {
-		// Handle fiber.Error dari UseCase
 		if fiberErr, ok := err.(*fiber.Error); ok {
-			errorsMap := make(map[string]string)
-			msg := fiberErr.Message
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
 
-			if fiberErr.Code == fiber.StatusBadRequest {
-				if msg == "order is not pending" {
-					errorsMap["status"] = "Only pending orders can be paid"
-					msg = "Validation failed"
-				}
-				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
-					Message: msg,
-					Errors:  errorsMap,
-				})
-			}
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderResponse]{
+		Data: response,
+	})
+}
+
+// Cancel cancels the user's own PENDING order
+func (h *OrderHandler) Cancel(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
 
-			if fiberErr.Code == fiber.StatusForbidden {
+	response, err := h.UseCase.CancelOrder(ctx.UserContext(), userID, orderID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			if fiberErr.Code == fiber.StatusInternalServerError {
 				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
-					Message: "You are not allowed to pay this order",
+					Message: "Internal server error, please try again later",
 				})
 			}
 
-			if fiberErr.Code == fiber.StatusNotFound {
+			// Default fallback (403, 404, 409 order sudah bukan PENDING)
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderResponse]{
+		Data:    response,
+		Message: "order cancelled successfully",
+	})
+}
+
+// UpdateStatus advances an order to the next status (admin only)
+func (h *OrderHandler) UpdateStatus(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	var request model.UpdateOrderStatusRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid request body",
+		})
+	}
+
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.UpdateOrderStatus(ctx.UserContext(), orderID, &request, adminID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			if fiberErr.Code == fiber.StatusBadRequest {
 				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
-					Message: "Order not found",
+					Message: "Request validation failed",
+					Errors:  map[string]string{"status": "status must be a valid order status and reason at most 255 characters"},
 				})
 			}
 
@@ -144,58 +277,174 @@ func (h *OrderHandler) Pay(ctx *fiber.Ctx) error {
 				})
 			}
 
-			// Default fallback
+			// Default fallback (404, 409 transisi tidak valid)
 			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
 				Message: fiberErr.Message,
 			})
 		}
 
-		// Non-fiber error fallback
 		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
 			Message: "Internal server error",
 		})
 	}
 
-	// 4. Response sukses
 	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderResponse]{
+		Data:    response,
+		Message: "order status updated successfully",
+	})
+}
+
+// History lists the status changes of an order (admin only)
+func (h *OrderHandler) History(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	response, err := h.UseCase.GetOrderStatusHistory(ctx.UserContext(), orderID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.OrderStatusHistoryResponse]{
 		Data: response,
 	})
 }
 
-func (h *OrderHandler) List(ctx *fiber.Ctx) error {
-	// Ambil userID dari token (middleware)
-	userID, ok := ctx.Locals("user_id").(int)
+// AdminList lists every order with the back-office filters (admin only)
+func (h *OrderHandler) AdminList(ctx *fiber.Ctx) error {
+	var request model.AdminListOrdersRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid query parameters",
+		})
+	}
+
+	response, page, size, totalItems, totalPages, err := h.UseCase.ListAllOrders(ctx.UserContext(), &request)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderListResponse]{
+		Page:       page,
+		Size:       size,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		Data:       response,
+	})
+}
+
+// AdminExport downloads the filtered orders as CSV (admin only)
+func (h *OrderHandler) AdminExport(ctx *fiber.Ctx) error {
+	var request model.AdminListOrdersRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid query parameters",
+		})
+	}
+
+	// Ditulis ke buffer dulu supaya error di tengah export masih bisa dikirim sebagai JSON
+	var buf bytes.Buffer
+	if err := h.UseCase.ExportOrdersCSV(ctx.UserContext(), &request, &buf); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	ctx.Attachment(fmt.Sprintf("orders-%s.csv", time.Now().Format("20060102-150405")))
+	return ctx.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// AdminGetByID returns any order with customer details and internal notes (admin only)
+func (h *OrderHandler) AdminGetByID(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	response, err := h.UseCase.GetOrderForAdmin(ctx.UserContext(), orderID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.AdminOrderResponse]{
+		Data: response,
+	})
+}
+
+// AddNote adds an internal staff note to an order (admin only)
+func (h *OrderHandler) AddNote(ctx *fiber.Ctx) error {
+	orderID, err := ctx.ParamsInt("id")
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	var request model.CreateOrderNoteRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid request body",
+		})
+	}
+
+	adminID, ok := ctx.Locals("user_id").(int)
 	if !ok {
 		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
 			Message: "Unauthorized, user not found",
 		})
 	}
 
-	// Panggil UseCase untuk ambil daftar order
-	response, err := h.UseCase.GetOrdersByUser(ctx.Context(), userID)
+	response, err := h.UseCase.AddOrderNote(ctx.UserContext(), orderID, adminID, &request)
 	if err != nil {
 		if fiberErr, ok := err.(*fiber.Error); ok {
-			// InternalServerError
-			if fiberErr.Code == fiber.StatusInternalServerError {
+			if fiberErr.Code == fiber.StatusBadRequest {
 				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
-					Message: "Internal server error, please try again later",
+					Message: "Request validation failed",
+					Errors:  map[string]string{"note": "note is required and at most 2000 characters"},
 				})
 			}
-
-			// Default fallback
 			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
 				Message: fiberErr.Message,
 			})
 		}
-
-		// Non-fiber error fallback
 		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
 			Message: "Internal server error",
 		})
 	}
 
-	// Response sukses
-	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.OrderListResponse]{
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.OrderNoteResponse]{
 		Data: response,
 	})
 }
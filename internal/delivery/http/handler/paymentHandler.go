@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentHandler struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PaymentUseCase
+}
+
+func NewPaymentHandler(useCase *usecase.PaymentUseCase, logger *logrus.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (h *PaymentHandler) Pay(ctx *fiber.Ctx) error {
+	// 1. Ambil orderID dari URL param
+	orderIDStr := ctx.Params("id")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil || orderID <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "Invalid order ID",
+		})
+	}
+
+	// 2. Ambil userID dari token (middleware)
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	// 3. Buat payment intent, order baru menjadi PAID setelah webhook dari provider
+	response, err := h.UseCase.CreatePayment(ctx.UserContext(), orderID, userID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			if fiberErr.Code == fiber.StatusConflict {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: "Validation failed",
+					Errors:  map[string]string{"status": "Only pending orders can be paid"},
+				})
+			}
+
+			if fiberErr.Code == fiber.StatusForbidden {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: "You are not allowed to pay this order",
+				})
+			}
+
+			if fiberErr.Code == fiber.StatusNotFound {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: "Order not found",
+				})
+			}
+
+			if fiberErr.Code == fiber.StatusInternalServerError {
+				return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+					Message: "Internal server error, please try again later",
+				})
+			}
+
+			// Default fallback
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+
+		// Non-fiber error fallback
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "Internal server error",
+		})
+	}
+
+	// 4. Response sukses
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.PaymentResponse]{
+		Data: response,
+	})
+}
+
+// Webhook receives signed callbacks from the payment provider
+func (h *PaymentHandler) Webhook(ctx *fiber.Ctx) error {
+	if err := h.UseCase.HandleWebhook(ctx.UserContext(), ctx.Body(), ctx.Get("X-Payment-Signature")); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[any]{
+		Message: "ok",
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type StockHandler struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.StockUseCase
+	Validate *validator.Validate
+}
+
+func NewStockHandler(useCase *usecase.StockUseCase, logger *logrus.Logger, validate *validator.Validate) *StockHandler {
+	return &StockHandler{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *StockHandler) Adjust(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid book id",
+		})
+	}
+
+	var request model.AdjustStockRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	// Admin yang melakukan adjustment dicatat di ledger
+	adminID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+
+	response, err := h.UseCase.AdjustStock(ctx.UserContext(), id, &request, adminID)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.StockResponse]{
+		Data:    response,
+		Message: "stock adjusted successfully",
+	})
+}
+
+func (h *StockHandler) ListMovements(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid book id",
+		})
+	}
+
+	page := ctx.QueryInt("page", 1)
+	size := ctx.QueryInt("size", 10)
+
+	movements, totalItems, totalPages, err := h.UseCase.ListMovements(ctx.UserContext(), id, page, size)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.WebResponse[any]{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.WebResponse[any]{
+			Message: "internal server error",
+		})
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.StockMovementResponse]{
+		Page:       page,
+		Size:       size,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		Data:       movements,
+	})
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"time"
+
 	"github.com/fathirarya/online-bookstore-api/internal/auth"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/fathirarya/online-bookstore-api/internal/usecase"
@@ -45,7 +47,7 @@ func (h *UserHandler) Register(ctx *fiber.Ctx) error {
 	}
 
 	// Call UseCase to handle user registration
-	response, err := h.UseCase.Register(ctx.Context(), &request)
+	response, err := h.UseCase.Register(ctx.UserContext(), &request)
 	if err != nil {
 		// Handle error returned from UseCase
 		if fiberErr, ok := err.(*fiber.Error); ok {
@@ -95,7 +97,7 @@ func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 	}
 
 	// Call UseCase to perform login and generate JWT
-	response, err := h.UseCase.Login(ctx.Context(), &request, h.JWTService)
+	response, err := h.UseCase.Login(ctx.UserContext(), &request, h.JWTService)
 	if err != nil {
 		// Handle error returned from UseCase
 		if fiberErr, ok := err.(*fiber.Error); ok {
@@ -127,3 +129,83 @@ func (h *UserHandler) Login(ctx *fiber.Ctx) error {
 		Data: response,
 	})
 }
+
+// Refresh rotates the refresh token and returns a new token pair
+func (h *UserHandler) Refresh(ctx *fiber.Ctx) error {
+	var request model.RefreshTokenRequest
+
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.RefreshToken(ctx.UserContext(), &request, h.JWTService)
+	if err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.AuthResponse]{
+		Data: response,
+	})
+}
+
+// Logout revokes the refresh token family and the access token used for this request
+func (h *UserHandler) Logout(ctx *fiber.Ctx) error {
+	var request model.LogoutUserRequest
+
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	// Ambil info token dari middleware
+	userID, ok := ctx.Locals("user_id").(int)
+	if !ok {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(model.ValidationErrorResponse{
+			Message: "Unauthorized, user not found",
+		})
+	}
+	jti, _ := ctx.Locals("jti").(string)
+	expiresAt, ok := ctx.Locals("token_expires_at").(time.Time)
+	if !ok {
+		expiresAt = time.Now().Add(h.JWTService.ExpireDuration())
+	}
+
+	if err := h.UseCase.Logout(ctx.UserContext(), &request, userID, jti, expiresAt); err != nil {
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+				Message: fiberErr.Message,
+			})
+		}
+		return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+			Message: "internal server error",
+		})
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[any]{
+		Message: "logged out successfully",
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/usecase"
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookHandler struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.WebhookUseCase
+	Validate *validator.Validate
+}
+
+func NewWebhookHandler(useCase *usecase.WebhookUseCase, logger *logrus.Logger, validate *validator.Validate) *WebhookHandler {
+	return &WebhookHandler{
+		Log:      logger,
+		UseCase:  useCase,
+		Validate: validate,
+	}
+}
+
+func (h *WebhookHandler) Create(ctx *fiber.Ctx) error {
+	var request model.CreateWebhookRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.CreateWebhook(ctx.UserContext(), &request)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.WebhookResponse]{
+		Data: response,
+	})
+}
+
+func (h *WebhookHandler) List(ctx *fiber.Ctx) error {
+	response, err := h.UseCase.ListWebhooks(ctx.UserContext())
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.WebhookResponse]{
+		Data: response,
+	})
+}
+
+func (h *WebhookHandler) GetByID(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook id",
+		})
+	}
+
+	response, err := h.UseCase.GetWebhook(ctx.UserContext(), id)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.WebhookResponse]{
+		Data: response,
+	})
+}
+
+func (h *WebhookHandler) Update(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook id",
+		})
+	}
+
+	var request model.UpdateWebhookRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid request body",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	response, err := h.UseCase.UpdateWebhook(ctx.UserContext(), id, &request)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.WebhookResponse]{
+		Data: response,
+	})
+}
+
+func (h *WebhookHandler) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook id",
+		})
+	}
+
+	if err := h.UseCase.DeleteWebhook(ctx.UserContext(), id); err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[any]{
+		Message: "webhook deleted successfully",
+	})
+}
+
+func (h *WebhookHandler) Deliveries(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook id",
+		})
+	}
+
+	var request model.ListWebhookDeliveriesRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.WebResponse[any]{
+			Message: "invalid query parameters",
+		})
+	}
+
+	if err := h.Validate.Struct(request); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "validation failed",
+			Errors:  utils.TranslateValidationErrors(err),
+		})
+	}
+
+	data, total, totalPages, err := h.UseCase.ListDeliveries(ctx.UserContext(), id, &request)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[[]*model.WebhookDeliveryResponse]{
+		Page:       request.Page,
+		Size:       request.Size,
+		TotalItems: total,
+		TotalPages: totalPages,
+		Data:       data,
+	})
+}
+
+func (h *WebhookHandler) Delivery(ctx *fiber.Ctx) error {
+	id, deliveryID, ok := h.deliveryParams(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook or delivery id",
+		})
+	}
+
+	response, err := h.UseCase.GetDelivery(ctx.UserContext(), id, deliveryID)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.WebhookDeliveryResponse]{
+		Data: response,
+	})
+}
+
+// Redeliver mengirim ulang satu delivery sekarang, hasilnya ada di attempt_log
+func (h *WebhookHandler) Redeliver(ctx *fiber.Ctx) error {
+	id, deliveryID, ok := h.deliveryParams(ctx)
+	if !ok {
+		return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+			Message: "invalid webhook or delivery id",
+		})
+	}
+
+	response, err := h.UseCase.Redeliver(ctx.UserContext(), id, deliveryID)
+	if err != nil {
+		return h.errorResponse(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusOK).JSON(model.WebResponse[*model.WebhookDeliveryResponse]{
+		Data: response,
+	})
+}
+
+func (h *WebhookHandler) deliveryParams(ctx *fiber.Ctx) (int, int, bool) {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id < 1 {
+		return 0, 0, false
+	}
+	deliveryID, err := ctx.ParamsInt("deliveryId")
+	if err != nil || deliveryID < 1 {
+		return 0, 0, false
+	}
+	return id, deliveryID, true
+}
+
+func (h *WebhookHandler) errorResponse(ctx *fiber.Ctx, err error) error {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		if fiberErr.Code == fiber.StatusBadRequest {
+			return ctx.Status(fiber.StatusBadRequest).JSON(model.ValidationErrorResponse{
+				Message: "validation failed",
+				Errors:  map[string]string{"url": fiberErr.Message},
+			})
+		}
+		return ctx.Status(fiberErr.Code).JSON(model.ValidationErrorResponse{
+			Message: fiberErr.Message,
+		})
+	}
+	return ctx.Status(fiber.StatusInternalServerError).JSON(model.ValidationErrorResponse{
+		Message: "internal server error",
+	})
+}
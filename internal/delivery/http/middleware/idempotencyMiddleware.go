@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/gofiber/fiber/v2"
+)
+
+// recordTimeout membatasi penyimpanan hasil request setelah handler selesai
+const recordTimeout = 5 * time.Second
+
+// IdempotencyStore persists Idempotency-Key reservations and their responses
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, userID int, key, requestHash string, ttl time.Duration) (*entity.IdempotencyKey, bool, error)
+	Complete(ctx context.Context, id int, code int, body []byte) error
+	Release(ctx context.Context, id int) error
+}
+
+// Idempotency replays the stored response when a request is retried with the same
+// Idempotency-Key header. Requests without the header are passed through untouched.
+// It must be registered after JWTProtected because keys are scoped per user.
+func Idempotency(store IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("Idempotency-Key")
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Idempotency-Key must be at most 255 characters",
+			})
+		}
+
+		userID, ok := c.Locals("user_id").(int)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "unauthorized, user not found",
+			})
+		}
+
+		// Key yang sama hanya boleh dipakai untuk request yang sama persis
+		hash := sha256.New()
+		hash.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		hash.Write(c.Body())
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		record, created, err := store.Reserve(c.UserContext(), userID, key, requestHash, ttl)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "internal server error",
+			})
+		}
+
+		if !created {
+			if record.RequestHash != requestHash {
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"message": "Idempotency-Key has already been used for a different request",
+				})
+			}
+			if record.Status == enum.IdempotencyInProgress {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"message": "a request with this Idempotency-Key is still being processed",
+				})
+			}
+
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(record.ResponseCode).Send(record.ResponseBody)
+		}
+
+		err = c.Next()
+
+		// Hasil tetap dicatat walaupun deadline request sudah lewat, kalau tidak key
+		// tertahan IN_PROGRESS sampai kadaluarsa
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.UserContext()), recordTimeout)
+		defer cancel()
+		if err != nil {
+			_ = store.Release(ctx, record.ID)
+			return err
+		}
+
+		// Error server tidak disimpan supaya client bisa mencoba lagi dengan key yang sama
+		code := c.Response().StatusCode()
+		if code >= fiber.StatusInternalServerError {
+			_ = store.Release(ctx, record.ID)
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		if err := store.Complete(ctx, record.ID, code, body); err != nil {
+			_ = store.Release(ctx, record.ID)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/gofiber/fiber/v2"
+)
+
+// memoryStore is an IdempotencyStore kept in memory, with a clock the test moves by hand
+type memoryStore struct {
+	mu      sync.Mutex
+	now     time.Time
+	nextID  int
+	records map[string]*entity.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{now: time.Now(), records: map[string]*entity.IdempotencyKey{}}
+}
+
+func (s *memoryStore) Reserve(ctx context.Context, userID int, key, requestHash string, ttl time.Duration) (*entity.IdempotencyKey, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := fmt.Sprintf("%d/%s", userID, key)
+	if existing, ok := s.records[name]; ok && existing.ExpiresAt.After(s.now) {
+		copied := *existing
+		return &copied, false, nil
+	}
+	s.nextID++
+	record := &entity.IdempotencyKey{
+		ID:          s.nextID,
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      enum.IdempotencyInProgress,
+		ExpiresAt:   s.now.Add(ttl),
+	}
+	s.records[name] = record
+	copied := *record
+	return &copied, true, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, id int, code int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range s.records {
+		if record.ID == id {
+			record.Status = enum.IdempotencyCompleted
+			record.ResponseCode = code
+			record.ResponseBody = body
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, record := range s.records {
+		if record.ID == id {
+			delete(s.records, name)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// newIdempotencyApp registers handler behind Idempotency. The X-User header stands in for
+// JWTProtected setting user_id.
+func newIdempotencyApp(store IdempotencyStore, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		userID, _ := strconv.Atoi(c.Get("X-User"))
+		c.Locals("user_id", userID)
+		return c.Next()
+	}, Idempotency(store, time.Hour), handler)
+	return app
+}
+
+func post(t *testing.T, app *fiber.App, user, key, body string) (int, string, http.Header) {
+	t.Helper()
+	code, responseBody, header, err := send(app, user, key, body)
+	if err != nil {
+		t.Fatalf("app.Test() error = %v", err)
+	}
+	return code, responseBody, header
+}
+
+func send(app *fiber.App, user, key, body string) (int, string, http.Header, error) {
+	req := httptest.NewRequest(fiber.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	defer resp.Body.Close()
+	responseBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(responseBody), resp.Header, nil
+}
+
+// countingHandler creates a new "order" on every call
+func countingHandler(calls *int) fiber.Handler {
+	var mu sync.Mutex
+	return func(c *fiber.Ctx) error {
+		mu.Lock()
+		*calls++
+		id := *calls
+		mu.Unlock()
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": id})
+	}
+}
+
+func TestIdempotencyReplaysTheStoredResponse(t *testing.T) {
+	store := newMemoryStore()
+	calls := 0
+	app := newIdempotencyApp(store, countingHandler(&calls))
+
+	code, body, _ := post(t, app, "1", "key-1", `{"book_id":1}`)
+	if code != fiber.StatusCreated || body != `{"id":1}` {
+		t.Fatalf("first request = %d %s, want 201 {\"id\":1}", code, body)
+	}
+
+	code, body, header := post(t, app, "1", "key-1", `{"book_id":1}`)
+	if code != fiber.StatusCreated || body != `{"id":1}` || header.Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry = %d %s replayed=%q, want the stored 201 {\"id\":1}", code, body, header.Get("Idempotent-Replayed"))
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+
+	// Key dipisah per user, user lain dengan key yang sama membuat order sendiri
+	if code, body, _ := post(t, app, "2", "key-1", `{"book_id":1}`); code != fiber.StatusCreated || body != `{"id":2}` {
+		t.Errorf("other user = %d %s, want a new 201 {\"id\":2}", code, body)
+	}
+
+	// Setelah TTL habis key boleh dipakai lagi
+	store.advance(time.Hour + time.Second)
+	if code, body, _ := post(t, app, "1", "key-1", `{"book_id":1}`); code != fiber.StatusCreated || body != `{"id":3}` {
+		t.Errorf("after the TTL = %d %s, want a new 201 {\"id\":3}", code, body)
+	}
+
+	// Tanpa header request tidak pernah di-replay
+	post(t, app, "1", "", `{"book_id":1}`)
+	post(t, app, "1", "", `{"book_id":1}`)
+	if calls != 5 {
+		t.Errorf("handler ran %d times, want 5", calls)
+	}
+}
+
+func TestIdempotencyRejectsADifferentBody(t *testing.T) {
+	calls := 0
+	app := newIdempotencyApp(newMemoryStore(), countingHandler(&calls))
+
+	post(t, app, "1", "key-1", `{"book_id":1}`)
+	code, body, _ := post(t, app, "1", "key-1", `{"book_id":2}`)
+	if code != fiber.StatusUnprocessableEntity || !strings.Contains(body, "different request") {
+		t.Errorf("different body = %d %s, want 422", code, body)
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyRejectsAConcurrentRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	app := newIdempotencyApp(newMemoryStore(), func(c *fiber.Ctx) error {
+		close(started)
+		<-release
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+	})
+
+	first := make(chan int, 1)
+	go func() {
+		code, _, _, _ := send(app, "1", "key-1", `{"book_id":1}`)
+		first <- code
+	}()
+	<-started
+
+	// Request pertama masih berjalan, key berstatus IN_PROGRESS
+	code, body, _ := post(t, app, "1", "key-1", `{"book_id":1}`)
+	if code != fiber.StatusConflict || !strings.Contains(body, "still being processed") {
+		t.Errorf("concurrent request = %d %s, want 409", code, body)
+	}
+
+	close(release)
+	if code := <-first; code != fiber.StatusCreated {
+		t.Errorf("first request = %d, want 201", code)
+	}
+	if code, _, header := post(t, app, "1", "key-1", `{"book_id":1}`); code != fiber.StatusCreated || header.Get("Idempotent-Replayed") != "true" {
+		t.Errorf("retry after the first finished = %d, want the replayed 201", code)
+	}
+}
+
+func TestIdempotencyReleasesTheKeyOnServerErrors(t *testing.T) {
+	store := newMemoryStore()
+	calls := 0
+	app := newIdempotencyApp(store, func(c *fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"message": "try again"})
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": calls})
+	})
+
+	if code, _, _ := post(t, app, "1", "key-1", `{"book_id":1}`); code != fiber.StatusServiceUnavailable {
+		t.Fatalf("first request = %d, want 503", code)
+	}
+	if len(store.records) != 0 {
+		t.Fatalf("key is still stored after a 5xx")
+	}
+
+	// Retry dengan key yang sama dijalankan ulang, bukan me-replay 503
+	code, body, header := post(t, app, "1", "key-1", `{"book_id":1}`)
+	if code != fiber.StatusCreated || body != `{"id":2}` || header.Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry = %d %s, want a fresh 201 {\"id\":2}", code, body)
+	}
+	if code, body, _ := post(t, app, "1", "key-1", `{"book_id":1}`); code != fiber.StatusCreated || body != `{"id":2}` {
+		t.Errorf("second retry = %d %s, want the stored 201 {\"id\":2}", code, body)
+	}
+}
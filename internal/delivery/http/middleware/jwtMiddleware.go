@@ -7,7 +7,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-func JWTProtected(jwtService *auth.JWTService) fiber.Handler {
+func JWTProtected(jwtService *auth.JWTService, denylist auth.TokenDenylist, users auth.UserStatus) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
@@ -24,8 +24,39 @@ func JWTProtected(jwtService *auth.JWTService) fiber.Handler {
 			})
 		}
 
+		// Token yang sudah logout ada di denylist
+		revoked, err := denylist.IsRevoked(c.UserContext(), claims.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "internal server error",
+			})
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "token has been revoked",
+			})
+		}
+
+		// User yang dinonaktifkan ditolak walaupun access token-nya belum kedaluwarsa
+		disabled, err := users.IsDisabled(c.UserContext(), claims.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": "internal server error",
+			})
+		}
+		if disabled {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": "account is disabled",
+			})
+		}
+
 		// Set user info ke context jika perlu
 		c.Locals("user_id", claims.UserID)
+		c.Locals("role", claims.Role)
+		c.Locals("jti", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Locals("token_expires_at", claims.ExpiresAt.Time)
+		}
 
 		return c.Next()
 	}
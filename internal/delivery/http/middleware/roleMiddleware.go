@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleRequired only lets the request through when the role set by JWTProtected
+// is one of the allowed roles. It must be registered after JWTProtected.
+func RoleRequired(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, ok := c.Locals("role").(string)
+		if !ok || role == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": "you are not allowed to access this resource",
+			})
+		}
+
+		for _, allowed := range roles {
+			if role == allowed {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": "you are not allowed to access this resource",
+		})
+	}
+}
@@ -6,15 +6,27 @@ import (
 )
 
 type RouteConfig struct {
-	App            *fiber.App
-	User           *handler.UserHandler
-	AuthMiddleware fiber.Handler
-	Category       *handler.CategoryHandler
-	Book           *handler.BookHandler
-	Order          *handler.OrderHandler
+	App             *fiber.App
+	User            *handler.UserHandler
+	AuthMiddleware  fiber.Handler
+	AdminMiddleware fiber.Handler
+	Idempotency     fiber.Handler
+	QueryTimeout    fiber.Handler
+	Category        *handler.CategoryHandler
+	Book            *handler.BookHandler
+	Order           *handler.OrderHandler
+	Stock           *handler.StockHandler
+	Payment         *handler.PaymentHandler
+	Cart            *handler.CartHandler
+	Coupon          *handler.CouponHandler
+	Config          *handler.ConfigHandler
+	Jobs            *handler.JobHandler
+	Event           *handler.EventHandler
+	Webhook         *handler.WebhookHandler
 }
 
 func (c *RouteConfig) Setup() {
+	c.App.Use(c.QueryTimeout)
 	c.SetupGuestRoutes()
 
 }
@@ -24,27 +36,86 @@ func (c *RouteConfig) SetupGuestRoutes() {
 	apiV1 := c.App.Group("/api")
 	apiV1.Post("/register", c.User.Register)
 	apiV1.Post("/login", c.User.Login)
+	apiV1.Post("/token/refresh", c.User.Refresh)
+
+	// Cover dibuka tanpa token supaya bisa dipakai langsung di tag <img>
+	apiV1.Get("/books/:id/cover", c.Book.Cover)
+
+	// Aturan order untuk validasi di client
+	apiV1.Get("/config/public", c.Config.Public)
+
+	// Callback payment provider, diverifikasi lewat signature bukan JWT
+	apiV1.Post("/payments/webhook", c.Payment.Webhook)
 
 	apiV1.Use(c.AuthMiddleware)
+	apiV1.Post("/logout", c.User.Logout)
+
 	// Categories
-	apiV1.Post("/categories", c.Category.Create)
+	apiV1.Post("/categories", c.AdminMiddleware, c.Category.Create)
 	apiV1.Get("/categories", c.Category.List)
-	apiV1.Put("/categories/:id", c.Category.Update)
-	apiV1.Delete("/categories/:id", c.Category.Delete)
+	apiV1.Put("/categories/:id", c.AdminMiddleware, c.Category.Update)
+	apiV1.Delete("/categories/:id", c.AdminMiddleware, c.Category.Delete)
 
 	// Books
-	apiV1.Post("/books", c.Book.Create)
+	apiV1.Post("/books", c.AdminMiddleware, c.Book.Create)
 	apiV1.Get("/books", c.Book.List)
 	apiV1.Get("/books/:id", c.Book.GetByID)
-	apiV1.Put("/books/:id", c.Book.Update)
-	apiV1.Delete("/books/:id", c.Book.Delete)
+	apiV1.Put("/books/:id", c.AdminMiddleware, c.Book.Update)
+	apiV1.Delete("/books/:id", c.AdminMiddleware, c.Book.Delete)
+
+	// Inventory (admin only)
+	apiV1.Post("/books/:id/stock", c.AdminMiddleware, c.Stock.Adjust)
+	apiV1.Get("/books/:id/stock/movements", c.AdminMiddleware, c.Stock.ListMovements)
 
 	// Orders
-	apiV1.Post("/orders", c.Order.Create)
-	apiV1.Post("/orders/:id/pay", c.Order.Pay)
+	apiV1.Post("/orders", c.Idempotency, c.Order.Create)
+	apiV1.Post("/orders/:id/pay", c.Idempotency, c.Payment.Pay)
 	apiV1.Get("/orders", c.Order.List)
+	apiV1.Get("/orders/:id", c.Order.GetByID)
+	apiV1.Post("/orders/:id/cancel", c.Order.Cancel)
+
+	// Cart
+	apiV1.Get("/cart", c.Cart.Get)
+	apiV1.Post("/cart/items", c.Cart.AddItem)
+	apiV1.Put("/cart/items/:book_id", c.Cart.UpdateItem)
+	apiV1.Delete("/cart/items/:book_id", c.Cart.RemoveItem)
+	apiV1.Post("/cart/checkout", c.Idempotency, c.Cart.Checkout)
+
+	// Coupons (admin only)
+	apiV1.Post("/coupons", c.AdminMiddleware, c.Coupon.Create)
+	apiV1.Get("/coupons", c.AdminMiddleware, c.Coupon.List)
+	apiV1.Get("/coupons/:id", c.AdminMiddleware, c.Coupon.GetByID)
+	apiV1.Put("/coupons/:id", c.AdminMiddleware, c.Coupon.Update)
+	apiV1.Delete("/coupons/:id", c.AdminMiddleware, c.Coupon.Delete)
+
+	// Order fulfilment (admin only), export didaftarkan sebelum :id
+	apiV1.Get("/admin/orders", c.AdminMiddleware, c.Order.AdminList)
+	apiV1.Get("/admin/orders/export", c.AdminMiddleware, c.Order.AdminExport)
+	apiV1.Get("/admin/orders/:id", c.AdminMiddleware, c.Order.AdminGetByID)
+	apiV1.Post("/admin/orders/:id/notes", c.AdminMiddleware, c.Order.AddNote)
+	apiV1.Put("/admin/orders/:id/status", c.AdminMiddleware, c.Order.UpdateStatus)
+	apiV1.Get("/admin/orders/:id/history", c.AdminMiddleware, c.Order.History)
+
+	// Background jobs (admin only)
+	apiV1.Get("/admin/jobs", c.AdminMiddleware, c.Jobs.List)
+	apiV1.Get("/admin/jobs/:name/runs", c.AdminMiddleware, c.Jobs.Runs)
+	apiV1.Post("/admin/jobs/:name/run", c.AdminMiddleware, c.Jobs.Trigger)
+
+	// Domain events di outbox (admin only)
+	apiV1.Get("/admin/events", c.AdminMiddleware, c.Event.List)
+	apiV1.Post("/admin/events/replay", c.AdminMiddleware, c.Event.Replay)
+
+	// Webhook partner (admin only)
+	apiV1.Post("/admin/webhooks", c.AdminMiddleware, c.Webhook.Create)
+	apiV1.Get("/admin/webhooks", c.AdminMiddleware, c.Webhook.List)
+	apiV1.Get("/admin/webhooks/:id", c.AdminMiddleware, c.Webhook.GetByID)
+	apiV1.Put("/admin/webhooks/:id", c.AdminMiddleware, c.Webhook.Update)
+	apiV1.Delete("/admin/webhooks/:id", c.AdminMiddleware, c.Webhook.Delete)
+	apiV1.Get("/admin/webhooks/:id/deliveries", c.AdminMiddleware, c.Webhook.Deliveries)
+	apiV1.Get("/admin/webhooks/:id/deliveries/:deliveryId", c.AdminMiddleware, c.Webhook.Delivery)
+	apiV1.Post("/admin/webhooks/:id/deliveries/:deliveryId/redeliver", c.AdminMiddleware, c.Webhook.Redeliver)
 
-	// Statistics
-	apiV1.Get("/books/stats/total", c.Book.GetTotalBooks)
-	apiV1.Get("/books/stats/price", c.Book.GetBookPriceStats)
+	// Statistics (admin only)
+	apiV1.Get("/books/stats/total", c.AdminMiddleware, c.Book.GetTotalBooks)
+	apiV1.Get("/books/stats/price", c.AdminMiddleware, c.Book.GetBookPriceStats)
 }
@@ -1,17 +1,22 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
 
 type Book struct {
-	ID          int       `gorm:"column:id;primaryKey;autoIncrement"`
-	Title       string    `gorm:"column:title;size:255;not null"`
-	Author      string    `gorm:"column:author;size:100;not null"`
-	Price       float64   `gorm:"column:price;type:decimal(10,2);not null"`
-	Year        int       `gorm:"column:year"`
-	CategoryID  int       `gorm:"column:category_id;not null"`
-	ImageBase64 string    `gorm:"column:image_base64;type:text"`
-	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdateAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID         int          `gorm:"column:id;primaryKey;autoIncrement"`
+	Title      string       `gorm:"column:title;size:255;not null"`
+	Author     string       `gorm:"column:author;size:100;not null"`
+	Price      money.Amount `gorm:"column:price;type:decimal(10,2);not null"`
+	Year       int          `gorm:"column:year"`
+	CategoryID int          `gorm:"column:category_id;not null"`
+	Stock      int          `gorm:"column:stock;not null;default:0"`
+	ImageKey   string       `gorm:"column:image_key;size:255"`
+	CreatedAt  time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdateAt   time.Time    `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	Category   Category    `gorm:"foreignKey:CategoryID;references:ID"`
@@ -1,12 +1,21 @@
 package entity
 
-import "time"
+import (
+	"time"
 
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// BookOrder adalah satu baris order. UnitPrice, Title dan Author adalah snapshot buku saat dibeli,
+// jadi perubahan atau penghapusan buku tidak mengubah riwayat order.
 type BookOrder struct {
-	BookID    int       `gorm:"column:book_id;primaryKey"`
-	OrderID   int       `gorm:"column:order_id;primaryKey"`
-	Quantity  int       `gorm:"column:quantity;not null"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	BookID    int          `gorm:"column:book_id;primaryKey"`
+	OrderID   int          `gorm:"column:order_id;primaryKey"`
+	Quantity  int          `gorm:"column:quantity;not null"`
+	UnitPrice money.Amount `gorm:"column:unit_price;type:decimal(10,2);not null;default:0"`
+	Title     string       `gorm:"column:title;size:255;not null;default:''"`
+	Author    string       `gorm:"column:author;size:100;not null;default:''"`
+	CreatedAt time.Time    `gorm:"column:created_at;autoCreateTime"`
 
 	// Relations
 	Book  Book  `gorm:"foreignKey:BookID;references:ID"`
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Cart adalah keranjang belanja, satu per user
+type Cart struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    int       `gorm:"column:user_id;not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdateAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relations
+	User  User       `gorm:"foreignKey:UserID;references:ID"`
+	Items []CartItem `gorm:"foreignKey:CartID;references:ID"`
+}
+
+func (Cart) TableName() string {
+	return "carts"
+}
+
+type CartItem struct {
+	CartID    int       `gorm:"column:cart_id;primaryKey"`
+	BookID    int       `gorm:"column:book_id;primaryKey"`
+	Quantity  int       `gorm:"column:quantity;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdateAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relations, item ikut terhapus jika buku dihapus
+	Cart Cart `gorm:"foreignKey:CartID;references:ID;constraint:OnDelete:CASCADE"`
+	Book Book `gorm:"foreignKey:BookID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (CartItem) TableName() string {
+	return "cart_items"
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// Coupon adalah kode promo. Scope ke CategoryID atau BookID, nil berarti berlaku untuk semua buku.
+// Limit yang nil berarti tidak dibatasi.
+type Coupon struct {
+	ID            int           `gorm:"column:id;primaryKey;autoIncrement"`
+	Code          string        `gorm:"column:code;size:50;not null;uniqueIndex"`
+	Type          string        `gorm:"column:type;size:20;not null"`
+	Value         money.Amount  `gorm:"column:value;type:decimal(10,2);not null"`
+	CategoryID    *int          `gorm:"column:category_id"`
+	BookID        *int          `gorm:"column:book_id"`
+	MinOrderValue money.Amount  `gorm:"column:min_order_value;type:decimal(10,2);not null;default:0"`
+	MaxDiscount   *money.Amount `gorm:"column:max_discount;type:decimal(10,2)"`
+	StartsAt      *time.Time    `gorm:"column:starts_at"`
+	EndsAt        *time.Time    `gorm:"column:ends_at"`
+	UsageLimit    *int          `gorm:"column:usage_limit"`
+	PerUserLimit  *int          `gorm:"column:per_user_limit"`
+	UsedCount     int           `gorm:"column:used_count;not null;default:0"`
+	Active        bool          `gorm:"column:active;not null"`
+	CreatedAt     time.Time     `gorm:"column:created_at;autoCreateTime"`
+	UpdateAt      time.Time     `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponRedemption mencatat pemakaian kupon per order, dihapus lagi jika order dibatalkan
+type CouponRedemption struct {
+	ID             int          `gorm:"column:id;primaryKey;autoIncrement"`
+	CouponID       int          `gorm:"column:coupon_id;not null;index:idx_coupon_redemption_user"`
+	UserID         int          `gorm:"column:user_id;not null;index:idx_coupon_redemption_user"`
+	OrderID        int          `gorm:"column:order_id;not null;uniqueIndex"`
+	DiscountAmount money.Amount `gorm:"column:discount_amount;type:decimal(10,2);not null"`
+	CreatedAt      time.Time    `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Coupon Coupon `gorm:"foreignKey:CouponID;references:ID"`
+}
+
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// IdempotencyKey menyimpan response pertama dari request dengan header Idempotency-Key per user
+type IdempotencyKey struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID       int       `gorm:"column:user_id;not null;uniqueIndex:idx_idempotency_user_key"`
+	Key          string    `gorm:"column:idempotency_key;size:255;not null;uniqueIndex:idx_idempotency_user_key"`
+	RequestHash  string    `gorm:"column:request_hash;size:64;not null"`
+	Status       string    `gorm:"column:status;size:20;not null"`
+	ResponseCode int       `gorm:"column:response_code"`
+	ResponseBody []byte    `gorm:"column:response_body"`
+	ExpiresAt    time.Time `gorm:"column:expires_at;not null;index"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
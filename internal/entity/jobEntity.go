@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+// JobRun mencatat satu eksekusi background job termasuk semua retry-nya
+type JobRun struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement"`
+	JobName     string     `gorm:"column:job_name;size:100;not null;index"`
+	Trigger     string     `gorm:"column:trigger_type;size:20;not null"`
+	TriggeredBy *int       `gorm:"column:triggered_by"` // admin yang menjalankan manual
+	Owner       string     `gorm:"column:owner;size:255;not null"`
+	Status      string     `gorm:"column:status;size:20;not null;index"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0"`
+	Error       string     `gorm:"column:error;type:text"`
+	StartedAt   time.Time  `gorm:"column:started_at;not null"`
+	FinishedAt  *time.Time `gorm:"column:finished_at"`
+	DurationMs  int64      `gorm:"column:duration_ms;not null;default:0"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// JobLease adalah lock per job supaya hanya satu replica yang menjalankannya dalam satu waktu
+type JobLease struct {
+	Name      string    `gorm:"column:name;primaryKey;size:100"`
+	Owner     string    `gorm:"column:owner;size:255;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+}
+
+func (JobLease) TableName() string {
+	return "job_leases"
+}
@@ -1,14 +1,23 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
 
 type Order struct {
-	ID         int       `gorm:"column:id;primaryKey;autoIncrement"`
-	UserID     int       `gorm:"column:user_id;not null"`
-	TotalPrice float64   `gorm:"column:total_price;type:decimal(10,2);not null"`
-	Status     string    `gorm:"column:status;type:enum('PENDING','PAID','CANCELLED');default:'PENDING'"`
-	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdateAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID             int          `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID         int          `gorm:"column:user_id;not null"`
+	Subtotal       money.Amount `gorm:"column:subtotal;type:decimal(10,2);not null;default:0"` // sebelum diskon
+	DiscountAmount money.Amount `gorm:"column:discount_amount;type:decimal(10,2);not null;default:0"`
+	CouponID       *int         `gorm:"column:coupon_id"`
+	CouponCode     string       `gorm:"column:coupon_code;size:50"`
+	TotalPrice     money.Amount `gorm:"column:total_price;type:decimal(10,2);not null"`
+	Currency       string       `gorm:"column:currency;size:3;not null;default:''"` // ISO 4217, diisi dari CURRENCY
+	Status         string       `gorm:"column:status;type:varchar(20);not null;default:'PENDING'"`
+	CreatedAt      time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdateAt       time.Time    `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Relations
 	User       User        `gorm:"foreignKey:UserID;references:ID"`
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// OrderNote adalah catatan internal staf pada order, tidak pernah ditampilkan ke customer
+type OrderNote struct {
+	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID   int       `gorm:"column:order_id;not null;index"`
+	AuthorID  int       `gorm:"column:author_id;not null"`
+	Note      string    `gorm:"column:note;type:text;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Order  Order `gorm:"foreignKey:OrderID;references:ID"`
+	Author User  `gorm:"foreignKey:AuthorID;references:ID"`
+}
+
+func (OrderNote) TableName() string {
+	return "order_notes"
+}
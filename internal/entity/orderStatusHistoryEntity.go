@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// OrderStatusHistory mencatat setiap perubahan status order, oleh siapa dan kenapa
+type OrderStatusHistory struct {
+	ID         int       `gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID    int       `gorm:"column:order_id;not null;index"`
+	FromStatus string    `gorm:"column:from_status;size:20;not null"`
+	ToStatus   string    `gorm:"column:to_status;size:20;not null"`
+	ChangedBy  *int      `gorm:"column:changed_by"` // nil jika diubah oleh sistem (cron job)
+	Reason     string    `gorm:"column:reason;size:255"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Order Order `gorm:"foreignKey:OrderID;references:ID"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_histories"
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// OutboxEvent adalah domain event yang ditulis dalam transaksi yang sama dengan perubahan datanya,
+// lalu dikirim ke sink oleh outbox dispatcher
+type OutboxEvent struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	EventID       string     `gorm:"column:event_id;size:36;not null;uniqueIndex"`
+	AggregateType string     `gorm:"column:aggregate_type;size:50;not null;index:idx_outbox_aggregate"`
+	AggregateID   int        `gorm:"column:aggregate_id;not null;index:idx_outbox_aggregate"`
+	EventType     string     `gorm:"column:event_type;size:100;not null;index"`
+	Payload       string     `gorm:"column:payload;type:text;not null"`
+	Status        string     `gorm:"column:status;size:20;not null;index"`
+	Attempts      int        `gorm:"column:attempts;not null;default:0"`
+	LastError     string     `gorm:"column:last_error;type:text"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;not null"`
+	PublishedAt   *time.Time `gorm:"column:published_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime;index"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// Payment adalah satu percobaan pembayaran order di payment provider
+type Payment struct {
+	ID          int          `gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID     int          `gorm:"column:order_id;not null;index"`
+	Provider    string       `gorm:"column:provider;size:30;not null"`
+	ProviderRef string       `gorm:"column:provider_ref;size:100;not null;uniqueIndex"`
+	Amount      money.Amount `gorm:"column:amount;type:decimal(10,2);not null"`
+	Currency    string       `gorm:"column:currency;size:3;not null;default:''"` // ISO 4217, diisi dari CURRENCY
+	Status      string       `gorm:"column:status;size:20;not null;default:'PENDING'"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Relations
+	Order Order `gorm:"foreignKey:OrderID;references:ID"`
+}
+
+func (Payment) TableName() string {
+	return "payments"
+}
+
+// PaymentEvent menyimpan ID setiap webhook yang sudah diproses supaya delivery ganda diabaikan
+type PaymentEvent struct {
+	EventID   string    `gorm:"column:event_id;primaryKey;size:100"`
+	PaymentID int       `gorm:"column:payment_id;not null;index"`
+	Type      string    `gorm:"column:type;size:50;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
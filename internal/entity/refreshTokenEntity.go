@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	ID        int        `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    int        `gorm:"column:user_id;not null;index"`
+	FamilyID  string     `gorm:"column:family_id;size:36;not null;index"`
+	TokenHash string     `gorm:"column:token_hash;size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID;references:ID"`
+}
+
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// RevokedToken is a denylisted access token, kept until the token itself expires
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;primaryKey;size:36"`
+	UserID    int       `gorm:"column:user_id;not null"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (RevokedToken) TableName() string {
+	return "revoked_tokens"
+}
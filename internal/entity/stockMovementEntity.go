@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// StockMovement adalah ledger perubahan stok buku (reservasi order, pembatalan, penyesuaian admin)
+type StockMovement struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
+	BookID       int       `gorm:"column:book_id;not null;index"`
+	Change       int       `gorm:"column:quantity_change;not null"`
+	BalanceAfter int       `gorm:"column:balance_after;not null"`
+	Reason       string    `gorm:"column:reason;size:255;not null"`
+	OrderID      *int      `gorm:"column:order_id;index"`
+	CreatedBy    *int      `gorm:"column:created_by"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Book Book `gorm:"foreignKey:BookID;references:ID"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
@@ -3,11 +3,13 @@ package entity
 import "time"
 
 type User struct {
-	ID        int       `gorm:"column:id;primaryKey;autoIncrement"`
-	Name      string    `gorm:"column:name;size:100;not null"`
-	Email     string    `gorm:"column:email;size:100;unique;not null"`
-	Password  string    `gorm:"column:password;size:255;not null"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	ID         int        `gorm:"column:id;primaryKey;autoIncrement"`
+	Name       string     `gorm:"column:name;size:100;not null"`
+	Email      string     `gorm:"column:email;size:100;unique;not null"`
+	Password   string     `gorm:"column:password;size:255;not null"`
+	Role       string     `gorm:"column:role;size:20;not null;default:'CUSTOMER'"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	DisabledAt *time.Time `gorm:"column:disabled_at"` // nil selama user aktif
 
 	// Relations
 	Orders []Order `gorm:"foreignKey:UserID;references:ID"`
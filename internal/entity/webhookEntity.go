@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// WebhookSubscription adalah endpoint partner yang menerima event yang dilanggan
+type WebhookSubscription struct {
+	ID                  int        `gorm:"column:id;primaryKey;autoIncrement"`
+	URL                 string     `gorm:"column:url;size:2048;not null"`
+	Secret              string     `gorm:"column:secret;size:100;not null"`
+	EventTypes          string     `gorm:"column:event_types;size:500;not null"` // dipisah koma
+	Description         string     `gorm:"column:description;size:255"`
+	Active              bool       `gorm:"column:active;not null;default:true"`
+	ConsecutiveFailures int        `gorm:"column:consecutive_failures;not null;default:0"`
+	DisabledAt          *time.Time `gorm:"column:disabled_at"`
+	DisabledReason      string     `gorm:"column:disabled_reason;size:255"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery adalah satu event untuk satu subscription, dikirim ulang sampai berhasil
+type WebhookDelivery struct {
+	ID             int        `gorm:"column:id;primaryKey;autoIncrement"`
+	SubscriptionID int        `gorm:"column:subscription_id;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string     `gorm:"column:event_id;size:36;not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string     `gorm:"column:event_type;size:100;not null"`
+	Payload        string     `gorm:"column:payload;type:text;not null"`
+	Status         string     `gorm:"column:status;size:20;not null;index"`
+	Attempts       int        `gorm:"column:attempts;not null;default:0"`
+	ResponseCode   *int       `gorm:"column:response_code"` // response terakhir, nil jika tidak ada response
+	LastError      string     `gorm:"column:last_error;type:text"`
+	NextAttemptAt  time.Time  `gorm:"column:next_attempt_at;not null;index"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+	// Relations
+	Subscription WebhookSubscription `gorm:"foreignKey:SubscriptionID;references:ID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt mencatat setiap request HTTP ke endpoint partner
+type WebhookDeliveryAttempt struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement"`
+	DeliveryID   int       `gorm:"column:delivery_id;not null;index"`
+	ResponseCode *int      `gorm:"column:response_code"`
+	ResponseBody string    `gorm:"column:response_body;type:text"` // dipotong maksimal 1 KB
+	Error        string    `gorm:"column:error;type:text"`
+	DurationMs   int64     `gorm:"column:duration_ms;not null"`
+	Manual       bool      `gorm:"column:manual;not null;default:false"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
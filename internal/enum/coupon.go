@@ -0,0 +1,6 @@
+package enum
+
+const (
+	CouponPercentage = "PERCENTAGE"
+	CouponFixed      = "FIXED"
+)
@@ -0,0 +1,41 @@
+package enum
+
+// Domain event types yang ditulis ke outbox
+const (
+	EventOrderCreated = "order.created"
+	EventBookCreated  = "book.created"
+	EventBookUpdated  = "book.updated"
+	EventBookDeleted  = "book.deleted"
+	EventStockLow     = "stock.low"
+)
+
+// Perubahan status order dipublikasikan sebagai "order." + status huruf kecil,
+// mis. order.paid, order.cancelled, order.refund_requested
+const EventOrderStatusPrefix = "order."
+
+// Aggregate pemilik event, urutan delivery dijaga per aggregate
+const (
+	AggregateOrder = "order"
+	AggregateBook  = "book"
+)
+
+const (
+	OutboxPending   = "PENDING"
+	OutboxPublished = "PUBLISHED"
+	OutboxDead      = "DEAD"
+)
+
+// Event yang bisa dilanggan lewat webhook partner
+var WebhookEventTypes = []string{
+	EventOrderCreated,
+	EventOrderStatusPrefix + "paid",
+	EventOrderStatusPrefix + "cancelled",
+	EventBookUpdated,
+	EventStockLow,
+}
+
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliverySucceeded = "SUCCEEDED"
+	WebhookDeliveryFailed    = "FAILED"
+)
@@ -0,0 +1,13 @@
+package enum
+
+const (
+	JobRunning   = "RUNNING"
+	JobSucceeded = "SUCCEEDED"
+	JobFailed    = "FAILED"
+)
+
+// Asal sebuah job run
+const (
+	JobTriggerSchedule = "SCHEDULE"
+	JobTriggerManual   = "MANUAL"
+)
@@ -1,7 +1,32 @@
 package enum
 
 const (
-	Pending   = "PENDING"
-	Paid      = "PAID"
-	Cancelled = "CANCELLED"
+	Pending         = "PENDING"
+	Paid            = "PAID"
+	Processing      = "PROCESSING"
+	Shipped         = "SHIPPED"
+	Delivered       = "DELIVERED"
+	Cancelled       = "CANCELLED"
+	RefundRequested = "REFUND_REQUESTED"
+	Refunded        = "REFUNDED"
 )
+
+// OrderTransitions adalah tabel transisi status order yang diizinkan
+var OrderTransitions = map[string][]string{
+	Pending:         {Paid, Cancelled},
+	Paid:            {Processing, RefundRequested},
+	Processing:      {Shipped, RefundRequested},
+	Shipped:         {Delivered},
+	Delivered:       {RefundRequested},
+	RefundRequested: {Refunded},
+}
+
+// CanTransition reports whether an order may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, next := range OrderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
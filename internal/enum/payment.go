@@ -0,0 +1,11 @@
+package enum
+
+const (
+	PaymentPending   = "PENDING"
+	PaymentSucceeded = "SUCCEEDED"
+	PaymentFailed    = "FAILED"
+	PaymentRefunding = "REFUNDING" // refund sudah dicatat, menunggu konfirmasi provider
+	PaymentRefunded  = "REFUNDED"
+	// PaymentMismatched: provider melaporkan jumlah atau mata uang yang berbeda, perlu dicek manual
+	PaymentMismatched = "MISMATCHED"
+)
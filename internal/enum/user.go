@@ -0,0 +1,6 @@
+package enum
+
+const (
+	RoleAdmin    = "ADMIN"
+	RoleCustomer = "CUSTOMER"
+)
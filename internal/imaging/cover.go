@@ -0,0 +1,161 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"net/http"
+	"path"
+
+	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register WebP decoder
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooLarge          = errors.New("image is too large")
+	ErrInvalidDimensions = errors.New("invalid image dimensions")
+)
+
+// Options batas validasi cover yang diupload
+type Options struct {
+	MaxBytes     int64
+	MinDimension int
+	MaxDimension int
+}
+
+// Variant adalah ukuran thumbnail, MaxSize adalah sisi terpanjang dalam pixel
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+// Variants are rendered for every uploaded cover
+var Variants = []Variant{
+	{Name: "small", MaxSize: 150},
+	{Name: "medium", MaxSize: 300},
+	{Name: "large", MaxSize: 600},
+}
+
+// ThumbnailContentType is the format every thumbnail is encoded in
+const ThumbnailContentType = "image/jpeg"
+
+var allowedContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type Rendition struct {
+	Data        []byte
+	ContentType string
+}
+
+type Cover struct {
+	Original   Rendition
+	Thumbnails map[string]Rendition
+}
+
+// ProcessCover sniffs, validates and renders thumbnails for an uploaded cover
+func ProcessCover(data []byte, opts Options) (*Cover, error) {
+	if opts.MaxBytes > 0 && int64(len(data)) > opts.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	// Content type diambil dari isi file, bukan dari header upload
+	contentType := http.DetectContentType(data)
+	if !allowedContentTypes[contentType] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Cek dimensi dari header dulu sebelum decode penuh (hindari decompression bomb)
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if err := checkDimensions(config.Width, config.Height, opts); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	cover := &Cover{
+		Original:   Rendition{Data: data, ContentType: contentType},
+		Thumbnails: make(map[string]Rendition, len(Variants)),
+	}
+	for _, variant := range Variants {
+		thumbnail, err := renderThumbnail(src, variant.MaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s thumbnail: %w", variant.Name, err)
+		}
+		cover.Thumbnails[variant.Name] = Rendition{Data: thumbnail, ContentType: ThumbnailContentType}
+	}
+
+	return cover, nil
+}
+
+// VariantKey: covers/<uuid>/original.png -> covers/<uuid>/small.jpg
+func VariantKey(originalKey, variant string) string {
+	return path.Dir(originalKey) + "/" + variant + utils.ImageExtension(ThumbnailContentType)
+}
+
+// IsVariant reports whether name is one of the thumbnail variants
+func IsVariant(name string) bool {
+	for _, variant := range Variants {
+		if variant.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func checkDimensions(width, height int, opts Options) error {
+	if opts.MinDimension > 0 && (width < opts.MinDimension || height < opts.MinDimension) {
+		return ErrInvalidDimensions
+	}
+	if opts.MaxDimension > 0 && (width > opts.MaxDimension || height > opts.MaxDimension) {
+		return ErrInvalidDimensions
+	}
+	return nil
+}
+
+// renderThumbnail mengecilkan gambar ke dalam kotak maxSize x maxSize, tidak pernah memperbesar
+func renderThumbnail(src image.Image, maxSize int) ([]byte, error) {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			height = height * maxSize / width
+			width = maxSize
+		} else {
+			width = width * maxSize / height
+			height = maxSize
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	// JPEG tidak punya alpha, jadi background transparan diisi putih
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
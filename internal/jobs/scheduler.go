@@ -0,0 +1,303 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Default untuk field Job yang tidak diisi
+const (
+	DefaultTimeout = 5 * time.Minute
+	DefaultBackoff = 10 * time.Second
+)
+
+var (
+	ErrUnknownJob = errors.New("jobs: unknown job")
+	ErrJobRunning = errors.New("jobs: job is already running")
+	ErrStopped    = errors.New("jobs: scheduler stopped")
+)
+
+// Job is a named background task run on a cron schedule or triggered manually
+type Job struct {
+	Name       string
+	Schedule   string        // standard 5 field cron expression
+	Timeout    time.Duration // deadline of a single attempt
+	MaxRetries int           // attempts after the first failure
+	Backoff    time.Duration // delay before the first retry, doubled on every retry
+	Run        func(ctx context.Context) error
+}
+
+// leaseTTL covers the worst case of every attempt timing out plus all backoff delays
+func (j Job) leaseTTL() time.Duration {
+	ttl := j.Timeout * time.Duration(j.MaxRetries+1)
+	for i := 0; i < j.MaxRetries; i++ {
+		ttl += j.Backoff << i
+	}
+	return ttl + time.Minute
+}
+
+// Info describes a registered job, Next is zero until the scheduler is started
+type Info struct {
+	Job
+	Next time.Time
+}
+
+type registered struct {
+	job     Job
+	entryID cron.EntryID
+}
+
+// Scheduler runs registered jobs. Every run takes a lease in the database so only one
+// replica runs a job at a time, and is recorded in job_runs.
+type Scheduler struct {
+	DB            *gorm.DB
+	Log           *logrus.Logger
+	JobRepository *repository.JobRepository
+	Owner         string // identitas proses ini di job_leases dan job_runs
+
+	cron    *cron.Cron
+	jobs    map[string]*registered
+	names   []string // urutan registrasi
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	running sync.WaitGroup
+	stopped bool
+}
+
+func NewScheduler(db *gorm.DB, log *logrus.Logger, jobRepository *repository.JobRepository) *Scheduler {
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		DB:            db,
+		Log:           log,
+		JobRepository: jobRepository,
+		Owner:         fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		cron:          cron.New(cron.WithLocation(time.Local)),
+		jobs:          make(map[string]*registered),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// Register adds a job, it fails on a duplicate name or an invalid schedule
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("jobs: name and run function are required")
+	}
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("jobs: job %q already registered", job.Name)
+	}
+	if job.Timeout <= 0 {
+		job.Timeout = DefaultTimeout
+	}
+	if job.Backoff <= 0 {
+		job.Backoff = DefaultBackoff
+	}
+
+	entry := &registered{job: job}
+	entryID, err := s.cron.AddFunc(job.Schedule, func() {
+		if _, err := s.dispatch(entry.job, enum.JobTriggerSchedule, nil); err != nil {
+			if errors.Is(err, ErrJobRunning) || errors.Is(err, ErrStopped) {
+				s.Log.Debugf("job %s skipped: %v", job.Name, err)
+				return
+			}
+			s.Log.Errorf("failed to start job %s: %v", job.Name, err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("jobs: invalid schedule for %s: %w", job.Name, err)
+	}
+	entry.entryID = entryID
+
+	s.jobs[job.Name] = entry
+	s.names = append(s.names, job.Name)
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop stops scheduling new runs and waits for running jobs. When ctx ends first the
+// running jobs are cancelled and given a few seconds to record their result.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cron.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		s.Log.Warn("jobs still running after shutdown deadline")
+	}
+	return ctx.Err()
+}
+
+// Jobs returns the registered jobs in registration order
+func (s *Scheduler) Jobs() []Info {
+	infos := make([]Info, 0, len(s.names))
+	for _, name := range s.names {
+		entry := s.jobs[name]
+		infos = append(infos, Info{Job: entry.job, Next: s.cron.Entry(entry.entryID).Next})
+	}
+	return infos
+}
+
+// Lookup returns a registered job by name
+func (s *Scheduler) Lookup(name string) (Info, bool) {
+	entry, ok := s.jobs[name]
+	if !ok {
+		return Info{}, false
+	}
+	return Info{Job: entry.job, Next: s.cron.Entry(entry.entryID).Next}, true
+}
+
+// Trigger starts a run now in the background and returns the run record
+func (s *Scheduler) Trigger(name string, triggeredBy *int) (*entity.JobRun, error) {
+	entry, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrUnknownJob
+	}
+	return s.dispatch(entry.job, enum.JobTriggerManual, triggeredBy)
+}
+
+// dispatch takes the lease and records the run synchronously, the job itself runs in a goroutine
+func (s *Scheduler) dispatch(job Job, trigger string, triggeredBy *int) (*entity.JobRun, error) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil, ErrStopped
+	}
+	s.running.Add(1)
+	s.mu.Unlock()
+
+	run, err := s.begin(job, trigger, triggeredBy)
+	if err != nil {
+		s.running.Done()
+		return nil, err
+	}
+
+	go func() {
+		defer s.running.Done()
+		s.execute(job, run)
+	}()
+	return run, nil
+}
+
+func (s *Scheduler) begin(job Job, trigger string, triggeredBy *int) (*entity.JobRun, error) {
+	db := s.DB.WithContext(s.ctx)
+
+	acquired, err := s.JobRepository.AcquireLease(db, job.Name, s.Owner, job.leaseTTL())
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrJobRunning
+	}
+
+	if err := s.JobRepository.MarkInterrupted(db, job.Name); err != nil {
+		s.Log.Warnf("failed to mark interrupted runs of job %s: %v", job.Name, err)
+	}
+
+	run := &entity.JobRun{
+		JobName:     job.Name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Owner:       s.Owner,
+		Status:      enum.JobRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := s.JobRepository.Create(db, run); err != nil {
+		_ = s.JobRepository.ReleaseLease(s.DB, job.Name, s.Owner)
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *Scheduler) execute(job Job, run *entity.JobRun) {
+	var err error
+	for attempt := 1; ; attempt++ {
+		run.Attempts = attempt
+		if err = s.attempt(job); err == nil || attempt > job.MaxRetries {
+			break
+		}
+
+		delay := job.Backoff << (attempt - 1)
+		s.Log.Warnf("job %s attempt %d failed, retrying in %s: %v", job.Name, attempt, delay, err)
+		if !sleep(s.ctx, delay) {
+			break
+		}
+	}
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	if err != nil {
+		run.Status = enum.JobFailed
+		run.Error = err.Error()
+		s.Log.Errorf("job %s failed after %d attempts: %v", job.Name, run.Attempts, err)
+	} else {
+		run.Status = enum.JobSucceeded
+		s.Log.Infof("job %s done in %dms", job.Name, run.DurationMs)
+	}
+
+	// Tanpa s.ctx supaya hasil tetap tercatat saat scheduler sedang berhenti
+	if err := s.JobRepository.Update(s.DB, run); err != nil {
+		s.Log.Errorf("failed to record run of job %s: %v", job.Name, err)
+	}
+	if err := s.JobRepository.ReleaseLease(s.DB, job.Name, s.Owner); err != nil {
+		s.Log.Errorf("failed to release lease of job %s: %v", job.Name, err)
+	}
+}
+
+// attempt runs the job once with its timeout, a panic is returned as an error
+func (s *Scheduler) attempt(job Job) (err error) {
+	ctx, cancel := context.WithTimeout(s.ctx, job.Timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// sleep waits for d, it returns false when ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -1,34 +1,65 @@
 package model
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
 
 type CreateBookRequest struct {
-	Title       string                `json:"title" validate:"required,max=255"`
-	Author      string                `json:"author" validate:"required,max=100"`
-	Price       float64               `json:"price" validate:"required,gt=0"`
-	Year        int                   `json:"year" validate:"omitempty,numeric"`
-	CategoryID  int                   `json:"category_id" validate:"required"`
-	ImageBase64 *multipart.FileHeader `json:"image_base64,omitempty" validate:"omitempty,base64"`
+	Title      string                `json:"title" validate:"required,max=255"`
+	Author     string                `json:"author" validate:"required,max=100"`
+	Price      money.Amount          `json:"price" validate:"required,gt=0"`
+	Year       int                   `json:"year" validate:"omitempty,numeric"`
+	CategoryID int                   `json:"category_id" validate:"required"`
+	Stock      int                   `json:"stock" validate:"omitempty,min=0"`
+	Image      *multipart.FileHeader `json:"-" validate:"-"` // divalidasi di usecase (tipe, ukuran, dimensi)
 }
 
 type BookResponse struct {
-	ID           int     `json:"id"`
-	Title        string  `json:"title"`
-	Author       string  `json:"author"`
-	Price        float64 `json:"price"`
-	Year         int     `json:"year"`
-	CategoryID   int     `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	ImageURL     string  `json:"image_url"`
+	ID           int             `json:"id"`
+	Title        string          `json:"title"`
+	Author       string          `json:"author"`
+	Price        money.Amount    `json:"price"`
+	Currency     string          `json:"currency"`
+	Year         int             `json:"year"`
+	CategoryID   int             `json:"category_id"`
+	CategoryName string          `json:"category_name"`
+	Stock        int             `json:"stock"`
+	ImageURL     string          `json:"image_url"`
+	Thumbnails   *BookThumbnails `json:"thumbnails,omitempty"`
+}
+
+type BookThumbnails struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
 }
 
 type UpdateBookRequest struct {
-	Title       string                `json:"title" validate:"required,max=255"`
-	Author      string                `json:"author" validate:"required,max=100"`
-	Price       float64               `json:"price" validate:"required,gt=0"`
-	Year        int                   `json:"year" validate:"omitempty,numeric"`
-	CategoryID  int                   `json:"category_id" validate:"required"`
-	ImageBase64 *multipart.FileHeader `json:"image_base64,omitempty" validate:"omitempty,base64"`
+	Title      string                `json:"title" validate:"required,max=255"`
+	Author     string                `json:"author" validate:"required,max=100"`
+	Price      money.Amount          `json:"price" validate:"required,gt=0"`
+	Year       int                   `json:"year" validate:"omitempty,numeric"`
+	CategoryID int                   `json:"category_id" validate:"required"`
+	Image      *multipart.FileHeader `json:"-" validate:"-"` // divalidasi di usecase (tipe, ukuran, dimensi)
+}
+
+// ListBooksRequest adalah query param untuk GET /api/books
+type ListBooksRequest struct {
+	Page       int          `query:"page"`
+	Size       int          `query:"size"`
+	Cursor     string       `query:"cursor"` // mode keyset, dipakai bersama limit
+	Limit      int          `query:"limit" validate:"omitempty,min=1,max=100"`
+	Q          string       `query:"q" validate:"omitempty,max=255"` // substring judul atau penulis
+	CategoryID int          `query:"category_id" validate:"omitempty,min=1"`
+	Author     string       `query:"author" validate:"omitempty,max=100"`
+	MinPrice   money.Amount `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice   money.Amount `query:"max_price" validate:"omitempty,gte=0"`
+	YearFrom   int          `query:"year_from" validate:"omitempty,min=0"`
+	YearTo     int          `query:"year_to" validate:"omitempty,min=0"`
+	Sort       string       `query:"sort"` // price, -price, title, year, created_at (prefix - untuk descending)
 }
 
 type BookStatsResponse struct {
@@ -36,7 +67,56 @@ type BookStatsResponse struct {
 }
 
 type BookPriceStatsResponse struct {
-	MaxPrice float64 `json:"max_price"`
-	MinPrice float64 `json:"min_price"`
-	AvgPrice float64 `json:"avg_price"`
+	MaxPrice money.Amount `json:"max_price"`
+	MinPrice money.Amount `json:"min_price"`
+	AvgPrice money.Amount `json:"avg_price"` // dibulatkan half away from zero ke 2 desimal
+	Currency string       `json:"currency"`
+}
+
+// CatalogBookRequest adalah satu baris CSV katalog. Buku dicocokkan lewat judul,
+// kategori lewat nama dan dibuat jika belum ada.
+type CatalogBookRequest struct {
+	Title    string       `validate:"required,max=255"`
+	Author   string       `validate:"required,max=100"`
+	Price    money.Amount `validate:"required,gt=0"`
+	Year     int          `validate:"omitempty,min=0"`
+	Category string       `validate:"required,max=100"`
+	Stock    int          `validate:"omitempty,min=0"` // hanya dipakai untuk buku baru
+}
+
+type CatalogImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// CatalogImportResponse is the outcome of an import. When Errors is not empty nothing was saved.
+type CatalogImportResponse struct {
+	Created           int                  `json:"created"`
+	Updated           int                  `json:"updated"`
+	Unchanged         int                  `json:"unchanged"`
+	CategoriesCreated int                  `json:"categories_created"`
+	DryRun            bool                 `json:"dry_run"`
+	Errors            []CatalogImportError `json:"errors,omitempty"`
+}
+
+// AdjustStockRequest adalah penyesuaian stok manual oleh admin, Change bisa negatif
+type AdjustStockRequest struct {
+	Change int    `json:"change" validate:"required"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type StockResponse struct {
+	BookID int `json:"book_id"`
+	Stock  int `json:"stock"`
+}
+
+type StockMovementResponse struct {
+	ID           int       `json:"id"`
+	BookID       int       `json:"book_id"`
+	Change       int       `json:"change"`
+	BalanceAfter int       `json:"balance_after"`
+	Reason       string    `json:"reason"`
+	OrderID      *int      `json:"order_id,omitempty"`
+	CreatedBy    *int      `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
 }
@@ -0,0 +1,34 @@
+package model
+
+import "github.com/fathirarya/online-bookstore-api/internal/money"
+
+type AddCartItemRequest struct {
+	BookID   int `json:"book_id" validate:"required"`
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type CheckoutCartRequest struct {
+	CouponCode string `json:"coupon_code" validate:"omitempty,max=50"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+// CartResponse berisi harga terbaru dari tabel books, bukan harga saat item ditambahkan
+type CartResponse struct {
+	Items         []CartItemResponse `json:"items"`
+	TotalQuantity int                `json:"total_quantity"`
+	TotalPrice    money.Amount       `json:"total_price"`
+	Currency      string             `json:"currency"`
+}
+
+type CartItemResponse struct {
+	BookID   int          `json:"book_id"`
+	Title    string       `json:"title"`
+	Author   string       `json:"author"`
+	Price    money.Amount `json:"price"`
+	Quantity int          `json:"quantity"`
+	SubTotal money.Amount `json:"sub_total"`
+	Stock    int          `json:"stock"`
+}
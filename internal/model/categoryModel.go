@@ -17,3 +17,13 @@ type CategoryResponse struct {
 type UpdateCategoryRequest struct {
 	Name string `json:"name" validate:"required,max=100"`
 }
+
+// ListCategoriesRequest adalah query param untuk GET /api/categories
+type ListCategoriesRequest struct {
+	Page   int    `query:"page"`
+	Size   int    `query:"size"`
+	Cursor string `query:"cursor"` // mode keyset, dipakai bersama limit
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Q      string `query:"q" validate:"omitempty,max=100"` // substring nama kategori
+	Sort   string `query:"sort"`                           // name, created_at (prefix - untuk descending)
+}
@@ -0,0 +1,13 @@
+package model
+
+import "github.com/fathirarya/online-bookstore-api/internal/money"
+
+// PublicConfigResponse adalah aturan order yang boleh dibaca client tanpa login
+type PublicConfigResponse struct {
+	Currency                   string       `json:"currency"`
+	PaymentWindowMinutes       int          `json:"payment_window_minutes"`
+	MaxQuantityPerItem         int          `json:"max_quantity_per_item"`
+	MaxItemsPerOrder           int          `json:"max_items_per_order"`
+	MinOrderValue              money.Amount `json:"min_order_value"`
+	CategoryMaxQuantityPerItem map[int]int  `json:"category_max_quantity_per_item,omitempty"` // key: category_id
+}
@@ -1,8 +1,11 @@
 package converter
 
 import (
+	"fmt"
+
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
 )
 
 func BookToResponse(book *entity.Book) *model.BookResponse {
@@ -11,10 +14,13 @@ func BookToResponse(book *entity.Book) *model.BookResponse {
 		Title:        book.Title,
 		Author:       book.Author,
 		Price:        book.Price,
+		Currency:     money.Currency(),
 		Year:         book.Year,
 		CategoryID:   book.CategoryID,
 		CategoryName: book.Category.Name,
-		ImageURL:     book.ImageBase64,
+		Stock:        book.Stock,
+		ImageURL:     bookCoverURL(book),
+		Thumbnails:   bookThumbnails(book),
 	}
 }
 
@@ -26,10 +32,50 @@ func BooksToResponse(books []entity.Book) []*model.BookResponse {
 			Title:        b.Title,
 			Author:       b.Author,
 			Price:        b.Price,
+			Currency:     money.Currency(),
 			Year:         b.Year,
 			CategoryID:   b.CategoryID,
 			CategoryName: b.Category.Name,
-			ImageURL:     b.ImageBase64,
+			Stock:        b.Stock,
+			ImageURL:     bookCoverURL(&b),
+			Thumbnails:   bookThumbnails(&b),
+		}
+	}
+	return responses
+}
+
+// bookCoverURL mengarah ke endpoint streaming cover, kosong jika buku belum punya cover
+func bookCoverURL(book *entity.Book) string {
+	if book.ImageKey == "" {
+		return ""
+	}
+	return fmt.Sprintf("/api/books/%d/cover", book.ID)
+}
+
+func bookThumbnails(book *entity.Book) *model.BookThumbnails {
+	coverURL := bookCoverURL(book)
+	if coverURL == "" {
+		return nil
+	}
+	return &model.BookThumbnails{
+		Small:  coverURL + "?size=small",
+		Medium: coverURL + "?size=medium",
+		Large:  coverURL + "?size=large",
+	}
+}
+
+func StockMovementsToResponse(movements []entity.StockMovement) []*model.StockMovementResponse {
+	responses := make([]*model.StockMovementResponse, len(movements))
+	for i, m := range movements {
+		responses[i] = &model.StockMovementResponse{
+			ID:           m.ID,
+			BookID:       m.BookID,
+			Change:       m.Change,
+			BalanceAfter: m.BalanceAfter,
+			Reason:       m.Reason,
+			OrderID:      m.OrderID,
+			CreatedBy:    m.CreatedBy,
+			CreatedAt:    m.CreatedAt,
 		}
 	}
 	return responses
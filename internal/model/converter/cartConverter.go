@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// CartToResponse menghitung subtotal dari harga buku saat ini, Items.Book harus sudah dipreload
+func CartToResponse(cart *entity.Cart) *model.CartResponse {
+	response := &model.CartResponse{
+		Items:    make([]model.CartItemResponse, 0, len(cart.Items)),
+		Currency: money.Currency(),
+	}
+
+	for _, item := range cart.Items {
+		subTotal := item.Book.Price.Mul(item.Quantity)
+		response.Items = append(response.Items, model.CartItemResponse{
+			BookID:   item.BookID,
+			Title:    item.Book.Title,
+			Author:   item.Book.Author,
+			Price:    item.Book.Price,
+			Quantity: item.Quantity,
+			SubTotal: subTotal,
+			Stock:    item.Book.Stock,
+		})
+		response.TotalQuantity += item.Quantity
+		response.TotalPrice += subTotal
+	}
+	return response
+}
@@ -0,0 +1,34 @@
+package converter
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func CouponToResponse(coupon *entity.Coupon) *model.CouponResponse {
+	return &model.CouponResponse{
+		ID:            coupon.ID,
+		Code:          coupon.Code,
+		Type:          coupon.Type,
+		Value:         coupon.Value,
+		CategoryID:    coupon.CategoryID,
+		BookID:        coupon.BookID,
+		MinOrderValue: coupon.MinOrderValue,
+		MaxDiscount:   coupon.MaxDiscount,
+		StartsAt:      coupon.StartsAt,
+		EndsAt:        coupon.EndsAt,
+		UsageLimit:    coupon.UsageLimit,
+		PerUserLimit:  coupon.PerUserLimit,
+		UsedCount:     coupon.UsedCount,
+		Active:        coupon.Active,
+		CreatedAt:     coupon.CreatedAt,
+	}
+}
+
+func CouponsToResponse(coupons []entity.Coupon) []*model.CouponResponse {
+	responses := make([]*model.CouponResponse, len(coupons))
+	for i := range coupons {
+		responses[i] = CouponToResponse(&coupons[i])
+	}
+	return responses
+}
@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+func OutboxEventToResponse(event *entity.OutboxEvent) *model.OutboxEventResponse {
+	return &model.OutboxEventResponse{
+		ID:            event.ID,
+		EventID:       event.EventID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Data:          json.RawMessage(event.Payload),
+		Status:        event.Status,
+		Attempts:      event.Attempts,
+		LastError:     event.LastError,
+		NextAttemptAt: event.NextAttemptAt,
+		PublishedAt:   event.PublishedAt,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+func OutboxEventsToResponse(events []entity.OutboxEvent) []*model.OutboxEventResponse {
+	responses := make([]*model.OutboxEventResponse, len(events))
+	for i := range events {
+		responses[i] = OutboxEventToResponse(&events[i])
+	}
+	return responses
+}
+
+func BookToEvent(book *entity.Book) *model.BookEvent {
+	return &model.BookEvent{
+		BookID:     book.ID,
+		Title:      book.Title,
+		Author:     book.Author,
+		Price:      book.Price,
+		Currency:   money.Currency(),
+		Year:       book.Year,
+		CategoryID: book.CategoryID,
+		Stock:      book.Stock,
+	}
+}
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func JobRunToResponse(run *entity.JobRun) *model.JobRunResponse {
+	return &model.JobRunResponse{
+		ID:          run.ID,
+		JobName:     run.JobName,
+		Trigger:     run.Trigger,
+		TriggeredBy: run.TriggeredBy,
+		Owner:       run.Owner,
+		Status:      run.Status,
+		Attempts:    run.Attempts,
+		Error:       run.Error,
+		StartedAt:   run.StartedAt,
+		FinishedAt:  run.FinishedAt,
+		DurationMs:  run.DurationMs,
+	}
+}
+
+func JobRunsToResponse(runs []entity.JobRun) []*model.JobRunResponse {
+	responses := make([]*model.JobRunResponse, len(runs))
+	for i := range runs {
+		responses[i] = JobRunToResponse(&runs[i])
+	}
+	return responses
+}
@@ -5,13 +5,15 @@ import (
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 )
 
-func BookOrderToResponse(bookOrder *entity.BookOrder, book *entity.Book) model.OrderItemResponse {
+// BookOrderToResponse memakai snapshot di baris order, bukan data buku saat ini
+func BookOrderToResponse(bookOrder *entity.BookOrder) model.OrderItemResponse {
 	return model.OrderItemResponse{
-		BookID:   book.ID,
-		Title:    book.Title,
-		Price:    book.Price,
+		BookID:   bookOrder.BookID,
+		Title:    bookOrder.Title,
+		Author:   bookOrder.Author,
+		Price:    bookOrder.UnitPrice,
 		Quantity: bookOrder.Quantity,
-		SubTotal: float64(bookOrder.Quantity) * book.Price,
+		SubTotal: bookOrder.UnitPrice.Mul(bookOrder.Quantity),
 	}
 }
 
@@ -20,16 +22,59 @@ func OrderToResponse(order *entity.Order) *model.OrderResponse {
 	var items []model.OrderItemResponse
 
 	for _, bo := range order.BookOrders {
-		// Asumsikan Book sudah dipreload di entity.Order.BookOrders[i].Book
-		items = append(items, BookOrderToResponse(&bo, &bo.Book))
+		items = append(items, BookOrderToResponse(&bo))
 	}
 
 	return &model.OrderResponse{
-		ID:         order.ID,
-		UserID:     order.UserID,
-		TotalPrice: order.TotalPrice,
-		Status:     order.Status,
-		CreatedAt:  order.CreatedAt,
-		Items:      items,
+		ID:             order.ID,
+		UserID:         order.UserID,
+		Subtotal:       order.Subtotal,
+		DiscountAmount: order.DiscountAmount,
+		CouponCode:     order.CouponCode,
+		TotalPrice:     order.TotalPrice,
+		Currency:       order.Currency,
+		Status:         order.Status,
+		CreatedAt:      order.CreatedAt,
+		Items:          items,
+	}
+}
+
+func OrderStatusHistoriesToResponse(histories []entity.OrderStatusHistory) []*model.OrderStatusHistoryResponse {
+	responses := make([]*model.OrderStatusHistoryResponse, 0, len(histories))
+	for _, h := range histories {
+		responses = append(responses, &model.OrderStatusHistoryResponse{
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			ChangedBy:  h.ChangedBy,
+			Reason:     h.Reason,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return responses
+}
+
+func AdminOrderToResponse(order *entity.Order, notes []entity.OrderNote) *model.AdminOrderResponse {
+	response := &model.AdminOrderResponse{
+		OrderResponse: *OrderToResponse(order),
+		Customer: model.OrderCustomerResponse{
+			ID:    order.User.ID,
+			Name:  order.User.Name,
+			Email: order.User.Email,
+		},
+		Notes: make([]model.OrderNoteResponse, 0, len(notes)),
+	}
+	for _, note := range notes {
+		response.Notes = append(response.Notes, *OrderNoteToResponse(&note))
+	}
+	return response
+}
+
+func OrderNoteToResponse(note *entity.OrderNote) *model.OrderNoteResponse {
+	return &model.OrderNoteResponse{
+		ID:         note.ID,
+		AuthorID:   note.AuthorID,
+		AuthorName: note.Author.Name,
+		Note:       note.Note,
+		CreatedAt:  note.CreatedAt,
 	}
 }
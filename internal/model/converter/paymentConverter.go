@@ -0,0 +1,20 @@
+package converter
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func PaymentToResponse(payment *entity.Payment, clientSecret string) *model.PaymentResponse {
+	return &model.PaymentResponse{
+		ID:           payment.ID,
+		OrderID:      payment.OrderID,
+		Provider:     payment.Provider,
+		ProviderRef:  payment.ProviderRef,
+		ClientSecret: clientSecret,
+		Amount:       payment.Amount,
+		Currency:     payment.Currency,
+		Status:       payment.Status,
+		CreatedAt:    payment.CreatedAt,
+	}
+}
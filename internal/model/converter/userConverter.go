@@ -9,16 +9,21 @@ import (
 
 func UserToResponse(user *entity.User) *model.UserResponse {
 	return &model.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		CreatedAt: user.CreatedAt,
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       user.Role,
+		CreatedAt:  user.CreatedAt,
+		DisabledAt: user.DisabledAt,
 	}
 }
 
-func AuthToResponse(user *entity.User, token string, expiresAt time.Time) *model.AuthResponse {
+func AuthToResponse(user *entity.User, token string, expiresAt time.Time, refreshToken string, refreshExpiresAt time.Time) *model.AuthResponse {
 	return &model.AuthResponse{
-		Token:     token,
-		User:      *UserToResponse(user),
-		ExpiresAt: expiresAt,
+		Token:            token,
+		RefreshToken:     refreshToken,
+		User:             *UserToResponse(user),
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
 	}
 }
@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+)
+
+func WebhookToResponse(subscription *entity.WebhookSubscription) *model.WebhookResponse {
+	return &model.WebhookResponse{
+		ID:                  subscription.ID,
+		URL:                 subscription.URL,
+		EventTypes:          strings.Split(subscription.EventTypes, ","),
+		Description:         subscription.Description,
+		Active:              subscription.Active,
+		ConsecutiveFailures: subscription.ConsecutiveFailures,
+		DisabledAt:          subscription.DisabledAt,
+		DisabledReason:      subscription.DisabledReason,
+		CreatedAt:           subscription.CreatedAt,
+		UpdatedAt:           subscription.UpdatedAt,
+	}
+}
+
+func WebhooksToResponse(subscriptions []entity.WebhookSubscription) []*model.WebhookResponse {
+	responses := make([]*model.WebhookResponse, len(subscriptions))
+	for i := range subscriptions {
+		responses[i] = WebhookToResponse(&subscriptions[i])
+	}
+	return responses
+}
+
+func WebhookDeliveryToResponse(delivery *entity.WebhookDelivery) *model.WebhookDeliveryResponse {
+	return &model.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseCode:   delivery.ResponseCode,
+		LastError:      delivery.LastError,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func WebhookDeliveriesToResponse(deliveries []entity.WebhookDelivery) []*model.WebhookDeliveryResponse {
+	responses := make([]*model.WebhookDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		responses[i] = WebhookDeliveryToResponse(&deliveries[i])
+	}
+	return responses
+}
+
+// WebhookDeliveryDetailToResponse includes the payload and every attempt
+func WebhookDeliveryDetailToResponse(delivery *entity.WebhookDelivery, attempts []entity.WebhookDeliveryAttempt) *model.WebhookDeliveryResponse {
+	response := WebhookDeliveryToResponse(delivery)
+	response.Payload = json.RawMessage(delivery.Payload)
+	response.AttemptLog = make([]model.WebhookAttemptResponse, 0, len(attempts))
+	for i := range attempts {
+		response.AttemptLog = append(response.AttemptLog, *WebhookAttemptToResponse(&attempts[i]))
+	}
+	return response
+}
+
+func WebhookAttemptToResponse(attempt *entity.WebhookDeliveryAttempt) *model.WebhookAttemptResponse {
+	return &model.WebhookAttemptResponse{
+		ID:           attempt.ID,
+		ResponseCode: attempt.ResponseCode,
+		ResponseBody: attempt.ResponseBody,
+		Error:        attempt.Error,
+		DurationMs:   attempt.DurationMs,
+		Manual:       attempt.Manual,
+		CreatedAt:    attempt.CreatedAt,
+	}
+}
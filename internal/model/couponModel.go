@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// CreateCouponRequest juga dipakai untuk update (PUT mengganti seluruh field)
+type CreateCouponRequest struct {
+	Code          string        `json:"code" validate:"required,max=50"`
+	Type          string        `json:"type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value         money.Amount  `json:"value" validate:"required,gt=0"`
+	CategoryID    *int          `json:"category_id" validate:"omitempty,gt=0"`
+	BookID        *int          `json:"book_id" validate:"omitempty,gt=0"`
+	MinOrderValue money.Amount  `json:"min_order_value" validate:"min=0"`
+	MaxDiscount   *money.Amount `json:"max_discount" validate:"omitempty,gt=0"`
+	StartsAt      *time.Time    `json:"starts_at"`
+	EndsAt        *time.Time    `json:"ends_at"`
+	UsageLimit    *int          `json:"usage_limit" validate:"omitempty,min=1"`
+	PerUserLimit  *int          `json:"per_user_limit" validate:"omitempty,min=1"`
+	Active        *bool         `json:"active"` // default true
+}
+
+type UpdateCouponRequest = CreateCouponRequest
+
+// ListCouponsRequest adalah query param untuk GET /api/coupons
+type ListCouponsRequest struct {
+	Page   int    `query:"page"`
+	Size   int    `query:"size"`
+	Q      string `query:"q" validate:"omitempty,max=50"` // substring kode kupon
+	Active *bool  `query:"active"`
+	Sort   string `query:"sort"` // code, created_at, ends_at (prefix - untuk descending)
+}
+
+type CouponResponse struct {
+	ID            int           `json:"id"`
+	Code          string        `json:"code"`
+	Type          string        `json:"type"`
+	Value         money.Amount  `json:"value"`
+	CategoryID    *int          `json:"category_id"`
+	BookID        *int          `json:"book_id"`
+	MinOrderValue money.Amount  `json:"min_order_value"`
+	MaxDiscount   *money.Amount `json:"max_discount"`
+	StartsAt      *time.Time    `json:"starts_at"`
+	EndsAt        *time.Time    `json:"ends_at"`
+	UsageLimit    *int          `json:"usage_limit"`
+	PerUserLimit  *int          `json:"per_user_limit"`
+	UsedCount     int           `json:"used_count"`
+	Active        bool          `json:"active"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
@@ -1,6 +1,6 @@
 package model
 
 type ValidationErrorResponse struct {
-    Message string            `json:"message"`
-    Errors  map[string]string `json:"errors,omitempty"`
-}
\ No newline at end of file
+	Message string            `json:"message"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// OrderStatusEvent is the payload of order.<status> events
+type OrderStatusEvent struct {
+	OrderID        int          `json:"order_id"`
+	UserID         int          `json:"user_id"`
+	PreviousStatus string       `json:"previous_status"`
+	Status         string       `json:"status"`
+	TotalPrice     money.Amount `json:"total_price"`
+	Currency       string       `json:"currency"`
+	Reason         string       `json:"reason,omitempty"`
+	ChangedBy      *int         `json:"changed_by,omitempty"` // nil untuk perubahan oleh sistem
+}
+
+// BookEvent is the payload of book.created, book.updated and book.deleted
+type BookEvent struct {
+	BookID     int          `json:"book_id"`
+	Title      string       `json:"title"`
+	Author     string       `json:"author"`
+	Price      money.Amount `json:"price"`
+	Currency   string       `json:"currency"`
+	Year       int          `json:"year"`
+	CategoryID int          `json:"category_id"`
+	Stock      int          `json:"stock"`
+}
+
+// StockLowEvent is the payload of stock.low, published when a decrease takes the stock
+// of a book from above the threshold to at or below it
+type StockLowEvent struct {
+	BookID    int    `json:"book_id"`
+	Title     string `json:"title"`
+	Stock     int    `json:"stock"`
+	Threshold int    `json:"threshold"`
+}
+
+// ListEventsRequest adalah query param untuk GET /api/admin/events
+type ListEventsRequest struct {
+	Page          int    `query:"page"`
+	Size          int    `query:"size" validate:"omitempty,min=1,max=100"`
+	Status        string `query:"status" validate:"omitempty,oneof=PENDING PUBLISHED DEAD"`
+	Type          string `query:"type" validate:"omitempty,max=100"`
+	AggregateType string `query:"aggregate_type" validate:"omitempty,max=50"`
+	AggregateID   int    `query:"aggregate_id" validate:"omitempty,gt=0"`
+}
+
+// ReplayEventsRequest requeues the events created in [from, to)
+type ReplayEventsRequest struct {
+	From time.Time `json:"from" validate:"required"`
+	To   time.Time `json:"to" validate:"required,gtfield=From"`
+	Type string    `json:"type" validate:"omitempty,max=100"`
+}
+
+type ReplayEventsResponse struct {
+	Requeued int64 `json:"requeued"`
+}
+
+type OutboxEventResponse struct {
+	ID            int64           `json:"id"`
+	EventID       string          `json:"event_id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	Data          json.RawMessage `json:"data"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
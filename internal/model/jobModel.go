@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type JobResponse struct {
+	Name           string          `json:"name"`
+	Schedule       string          `json:"schedule"`
+	TimeoutSeconds int             `json:"timeout_seconds"`
+	MaxRetries     int             `json:"max_retries"`
+	NextRunAt      *time.Time      `json:"next_run_at,omitempty"`
+	LastRun        *JobRunResponse `json:"last_run,omitempty"`
+}
+
+type JobRunResponse struct {
+	ID          int        `json:"id"`
+	JobName     string     `json:"job_name"`
+	Trigger     string     `json:"trigger"`
+	TriggeredBy *int       `json:"triggered_by,omitempty"`
+	Owner       string     `json:"owner"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	DurationMs  int64      `json:"duration_ms"`
+}
+
+// ListJobRunsRequest adalah query param untuk GET /api/admin/jobs/:name/runs
+type ListJobRunsRequest struct {
+	Page   int    `query:"page"`
+	Size   int    `query:"size" validate:"omitempty,min=1,max=100"`
+	Status string `query:"status" validate:"omitempty,oneof=RUNNING SUCCEEDED FAILED"`
+}
@@ -6,6 +6,8 @@ type WebResponse[T any] struct {
 	Size       int               `json:"size,omitempty"`
 	TotalItems int64             `json:"total_items,omitempty"`
 	TotalPages int64             `json:"total_pages,omitempty"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
 	Data       T                 `json:"data,omitempty"`
 	Errors     map[string]string `json:"errors,omitempty"`
 	Message    string            `json:"message,omitempty"`
@@ -1,39 +1,121 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
 
 type CreateOrderRequest struct {
-	Items []OrderItemInput `json:"items" validate:"required,dive"`
+	Items      []OrderItemInput `json:"items" validate:"required,dive"`
+	CouponCode string           `json:"coupon_code" validate:"omitempty,max=50"` // satu kupon per order
 }
 
 // OrderItemInput adalah item buku yang diorder
 type OrderItemInput struct {
 	BookID   int `json:"book_id" validate:"required"`
-	Quantity int `json:"quantity" validate:"required,min=1,max=5"` // max per item
+	Quantity int `json:"quantity" validate:"required,min=1"` // batas per item dari OrderRules
 }
 
 // Response
 type OrderResponse struct {
-	ID         int                 `json:"id"`
-	UserID     int                 `json:"user_id"`
-	TotalPrice float64             `json:"total_price"`
-	Status     string              `json:"status"`
-	CreatedAt  time.Time           `json:"created_at"`
-	Items      []OrderItemResponse `json:"items"`
+	ID             int                 `json:"id"`
+	UserID         int                 `json:"user_id"`
+	Subtotal       money.Amount        `json:"subtotal"`
+	DiscountAmount money.Amount        `json:"discount_amount"`
+	CouponCode     string              `json:"coupon_code,omitempty"`
+	TotalPrice     money.Amount        `json:"total_price"`
+	Currency       string              `json:"currency"`
+	Status         string              `json:"status"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Items          []OrderItemResponse `json:"items"`
 }
 
 type OrderItemResponse struct {
-	BookID   int     `json:"book_id"`
-	Title    string  `json:"title"`
-	Price    float64 `json:"price"`
-	Quantity int     `json:"quantity"`
-	SubTotal float64 `json:"sub_total"`
+	BookID   int          `json:"book_id"`
+	Title    string       `json:"title"`
+	Author   string       `json:"author"`
+	Price    money.Amount `json:"price"` // harga satuan saat order dibuat
+	Quantity int          `json:"quantity"`
+	SubTotal money.Amount `json:"sub_total"`
 }
 
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" validate:"required,oneof=PENDING PAID CANCELLED"`
+	Status string `json:"status" validate:"required,oneof=PENDING PAID PROCESSING SHIPPED DELIVERED CANCELLED REFUND_REQUESTED REFUNDED"`
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type OrderStatusHistoryResponse struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  *int      `json:"changed_by"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ListOrdersRequest adalah query param untuk GET /api/orders
+type ListOrdersRequest struct {
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Size     int    `query:"size" validate:"omitempty,min=1,max=100"`
+	Cursor   string `query:"cursor"` // mode keyset, dipakai bersama limit
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Status   string `query:"status" validate:"omitempty,oneof=PENDING PAID PROCESSING SHIPPED DELIVERED CANCELLED REFUND_REQUESTED REFUNDED"`
+	DateFrom string `query:"date_from" validate:"omitempty,datetime=2006-01-02"` // tanggal order dibuat, inklusif
+	DateTo   string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`   // inklusif
 }
 
 type OrderListResponse struct {
 	Orders []OrderResponse `json:"orders"`
 }
+
+// AdminListOrdersRequest adalah query param untuk GET /api/admin/orders dan export CSV
+type AdminListOrdersRequest struct {
+	Page     int          `query:"page" validate:"omitempty,min=1"`
+	Size     int          `query:"size" validate:"omitempty,min=1,max=100"`
+	Status   string       `query:"status" validate:"omitempty,oneof=PENDING PAID PROCESSING SHIPPED DELIVERED CANCELLED REFUND_REQUESTED REFUNDED"`
+	UserID   int          `query:"user_id" validate:"omitempty,min=1"`
+	DateFrom string       `query:"date_from" validate:"omitempty,datetime=2006-01-02"` // inklusif
+	DateTo   string       `query:"date_to" validate:"omitempty,datetime=2006-01-02"`   // inklusif
+	MinTotal money.Amount `query:"min_total" validate:"omitempty,gte=0"`
+	BookID   int          `query:"book_id" validate:"omitempty,min=1"` // order yang berisi buku ini
+}
+
+// AdminOrderResponse adalah detail order untuk staf, termasuk data customer dan catatan internal
+type AdminOrderResponse struct {
+	OrderResponse
+	Customer OrderCustomerResponse `json:"customer"`
+	Notes    []OrderNoteResponse   `json:"notes"`
+}
+
+type OrderCustomerResponse struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type CreateOrderNoteRequest struct {
+	Note string `json:"note" validate:"required,max=2000"`
+}
+
+type OrderNoteResponse struct {
+	ID         int       `json:"id"`
+	AuthorID   int       `json:"author_id"`
+	AuthorName string    `json:"author_name"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderStatsResponse menghitung order per status, Revenue adalah total order yang sudah dibayar
+// dan tidak di-refund
+type OrderStatsResponse struct {
+	TotalOrders int64                      `json:"total_orders"`
+	Statuses    []OrderStatusCountResponse `json:"statuses"`
+	Revenue     money.Amount               `json:"revenue"`
+	Currency    string                     `json:"currency"`
+}
+
+type OrderStatusCountResponse struct {
+	Status     string       `json:"status"`
+	Count      int64        `json:"count"`
+	TotalPrice money.Amount `json:"total_price"`
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+type PaymentResponse struct {
+	ID           int          `json:"id"`
+	OrderID      int          `json:"order_id"`
+	Provider     string       `json:"provider"`
+	ProviderRef  string       `json:"provider_ref"`
+	ClientSecret string       `json:"client_secret,omitempty"` // dipakai client untuk menyelesaikan pembayaran di provider
+	Amount       money.Amount `json:"amount"`
+	Currency     string       `json:"currency"`
+	Status       string       `json:"status"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
@@ -4,9 +4,12 @@ import "time"
 
 // User Response
 type UserResponse struct {
-	ID        int       `json:"id,omitempty"`
-	Name      string    `json:"name,omitempty"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	ID         int        `json:"id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
 }
 
 // Register User
@@ -23,12 +26,19 @@ type LoginUserRequest struct {
 }
 
 type AuthResponse struct {
-	Token     string       `json:"token"`
-	User      UserResponse `json:"user"`
-	ExpiresAt time.Time    `json:"expires_at"`
+	Token            string       `json:"token"`
+	RefreshToken     string       `json:"refresh_token"`
+	User             UserResponse `json:"user"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+}
+
+// Refresh Token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 // Logout User
 type LogoutUserRequest struct {
-	ID int `json:"id" validate:"required"`
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,oneof=order.created order.paid order.cancelled book.updated stock.low"`
+	Description string   `json:"description" validate:"max=255"`
+}
+
+// UpdateWebhookRequest mengganti seluruh field, active=true mengaktifkan lagi subscription yang
+// dinonaktifkan otomatis
+type UpdateWebhookRequest struct {
+	URL          string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes   []string `json:"event_types" validate:"required,min=1,dive,oneof=order.created order.paid order.cancelled book.updated stock.low"`
+	Description  string   `json:"description" validate:"max=255"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+type WebhookResponse struct {
+	ID                  int        `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Description         string     `json:"description"`
+	Active              bool       `json:"active"`
+	Secret              string     `json:"secret,omitempty"` // hanya dikirim saat dibuat atau di-rotate
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// ListWebhookDeliveriesRequest adalah query param untuk GET /api/admin/webhooks/:id/deliveries
+type ListWebhookDeliveriesRequest struct {
+	Page   int    `query:"page"`
+	Size   int    `query:"size" validate:"omitempty,min=1,max=100"`
+	Status string `query:"status" validate:"omitempty,oneof=PENDING SUCCEEDED FAILED"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int                      `json:"id"`
+	SubscriptionID int                      `json:"subscription_id"`
+	EventID        string                   `json:"event_id"`
+	EventType      string                   `json:"event_type"`
+	Status         string                   `json:"status"`
+	Attempts       int                      `json:"attempts"`
+	ResponseCode   *int                     `json:"response_code,omitempty"`
+	LastError      string                   `json:"last_error,omitempty"`
+	NextAttemptAt  time.Time                `json:"next_attempt_at"`
+	DeliveredAt    *time.Time               `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	Payload        json.RawMessage          `json:"payload,omitempty"`     // hanya di detail
+	AttemptLog     []WebhookAttemptResponse `json:"attempt_log,omitempty"` // hanya di detail
+}
+
+type WebhookAttemptResponse struct {
+	ID           int       `json:"id"`
+	ResponseCode *int      `json:"response_code,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	Manual       bool      `json:"manual"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,264 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale adalah jumlah digit desimal, sama dengan kolom decimal(10,2) di database
+const Scale = 2
+
+const unit = 100 // 10^Scale
+
+// DefaultCurrency dipakai jika CURRENCY tidak diset
+const DefaultCurrency = "IDR"
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+var currency = DefaultCurrency
+
+// SetCurrency sets the ISO 4217 code attached to every price, total and payment
+func SetCurrency(code string) error {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("invalid currency code %q", code)
+	}
+	currency = code
+	return nil
+}
+
+// Currency returns the store currency code
+func Currency() string {
+	return currency
+}
+
+// Amount adalah nilai uang dalam satuan terkecil (minor unit, 1/100), jadi penjumlahan dan
+// perkalian selalu eksak. Pembulatan hanya terjadi saat parsing lebih dari 2 desimal,
+// konversi dari float dan perhitungan persen, semuanya half away from zero.
+type Amount int64
+
+// FromMinor builds an Amount from minor units, e.g. FromMinor(1999) is 19.99
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat converts a float, rounding half away from zero to 2 decimals
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * unit))
+}
+
+// Parse reads a decimal string such as "19.99", "-5" or "3.145" without going through float64
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+
+	var units int64
+	if whole != "" {
+		n, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || n > math.MaxInt64/unit-1 {
+			return 0, ErrInvalidAmount
+		}
+		units = n * unit
+	}
+
+	// Digit ke-3 dan seterusnya dibulatkan half away from zero
+	for i := 0; i < Scale; i++ {
+		digit := int64(0)
+		if i < len(frac) {
+			digit = int64(frac[i] - '0')
+		}
+		units += digit * pow10(Scale-1-i)
+	}
+	if len(frac) > Scale && frac[Scale] >= '5' {
+		units++
+	}
+
+	if negative {
+		units = -units
+	}
+	return Amount(units), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 is only meant for display or for APIs that require a float
+func (a Amount) Float64() float64 {
+	return float64(a) / unit
+}
+
+// Mul multiplies by a quantity, e.g. unit price × quantity
+func (a Amount) Mul(quantity int) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns pct percent of a, pct itself is an Amount so 12.5% is Parse("12.5").
+// The result is rounded half away from zero to the minor unit.
+func (a Amount) Percent(pct Amount) Amount {
+	return Amount(divRound(int64(a)*int64(pct), 100*unit))
+}
+
+// Div splits a into n parts rounded half away from zero, used for averages
+func (a Amount) Div(n int64) Amount {
+	if n == 0 {
+		return 0
+	}
+	return Amount(divRound(int64(a), n))
+}
+
+func divRound(num, den int64) int64 {
+	q, r := num/den, num%den
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= abs(den) {
+		if (num < 0) != (den < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Min returns the smaller amount
+func Min(a, b Amount) Amount {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// String formats the amount with exactly 2 decimals, e.g. "59.97"
+func (a Amount) String() string {
+	sign := ""
+	n := int64(a)
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, n/unit, n%unit)
+}
+
+// MarshalJSON menulis angka JSON dengan 2 desimal supaya client lama yang membaca number tetap jalan
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	// Angka dengan eksponen (1e3) jarang dikirim, diparse lewat float lalu dibulatkan
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return ErrInvalidAmount
+		}
+		*a = FromFloat(f)
+		return nil
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// UnmarshalText dipakai oleh query parser dan form value
+func (a *Amount) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Scan reads decimal columns and aggregates such as AVG(price)
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case int64:
+		*a = Amount(v * unit)
+		return nil
+	case float64:
+		*a = FromFloat(v)
+		return nil
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+func (a *Amount) scanString(s string) error {
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*a = FromFloat(f)
+		return nil
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Value ditulis sebagai string desimal supaya kolom decimal menerima nilai eksak
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
@@ -0,0 +1,141 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFromMinor(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{0, "0.00"},
+		{1, "0.01"},
+		{499, "4.99"},
+		{1999, "19.99"},
+		{100000, "1000.00"},
+		{-5, "-0.05"},
+		{-1250, "-12.50"},
+	}
+	for _, tt := range tests {
+		got := FromMinor(tt.minor)
+		if got.Minor() != tt.minor || got.String() != tt.want {
+			t.Errorf("FromMinor(%d) = %d %q, want %d %q", tt.minor, got.Minor(), got.String(), tt.minor, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{"19.99", 1999, false},
+		{"4.99", 499, false},
+		{"-5", -500, false},
+		{"+1.00", 100, false},
+		{" 2.5 ", 250, false},
+		{".5", 50, false},
+		{"5.", 500, false},
+		{"0.1", 10, false},
+		// Digit ke-3 dibulatkan half away from zero
+		{"3.145", 315, false},
+		{"3.144", 314, false},
+		{"-3.145", -315, false},
+		{"0.005", 1, false},
+		{"0.0049", 0, false},
+		{"", 0, true},
+		{".", 0, true},
+		{"-", 0, true},
+		{"abc", 0, true},
+		{"1.2.3", 0, true},
+		{"1,5", 0, true},
+		{"1e3", 0, true},
+		{"99999999999999999999", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Parse(%q) error = %v, want ErrInvalidAmount", tt.in, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got.Minor() != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got.Minor(), tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{0, "0.00"},
+		{7, "0.07"},
+		{70, "0.70"},
+		{1497, "14.97"},
+		{-1, "-0.01"},
+		{-1497, "-14.97"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", tt.amount, got, tt.want)
+		}
+		// String harus bisa diparse kembali ke nilai yang sama
+		back, err := Parse(tt.amount.String())
+		if err != nil || back != tt.amount {
+			t.Errorf("Parse(%q) = %d, %v, want %d", tt.amount.String(), back, err, tt.amount)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		amount   Amount
+		quantity int
+		want     string
+	}{
+		{FromMinor(499), 3, "14.97"},
+		{FromMinor(1999), 3, "59.97"},
+		{FromMinor(10), 10, "1.00"},
+		{FromMinor(333), 0, "0.00"},
+		{FromMinor(-250), 2, "-5.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Mul(tt.quantity).String(); got != tt.want {
+			t.Errorf("%s.Mul(%d) = %s, want %s", tt.amount, tt.quantity, got, tt.want)
+		}
+	}
+}
+
+func TestPercentRoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		pct    string
+		want   string
+	}{
+		{FromMinor(1497), "15", "2.25"}, // 2.2455
+		{FromMinor(1000), "12.5", "1.25"},
+		{FromMinor(10), "5", "0.01"},   // 0.005
+		{FromMinor(9), "5", "0.00"},    // 0.0045
+		{FromMinor(-10), "5", "-0.01"}, // -0.005
+		{FromMinor(5997), "100", "59.97"},
+	}
+	for _, tt := range tests {
+		pct, err := Parse(tt.pct)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.pct, err)
+		}
+		if got := tt.amount.Percent(pct).String(); got != tt.want {
+			t.Errorf("%s.Percent(%s) = %s, want %s", tt.amount, tt.pct, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Publisher is the adapter to a message broker (Kafka, RabbitMQ, NATS, ...). key is the
+// aggregate, brokers that partition by key then keep the per aggregate order.
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, body []byte) error
+}
+
+// BrokerSink publishes every event to the topic TopicPrefix + event type
+type BrokerSink struct {
+	Publisher   Publisher
+	TopicPrefix string
+}
+
+func NewBrokerSink(publisher Publisher, topicPrefix string) *BrokerSink {
+	return &BrokerSink{
+		Publisher:   publisher,
+		TopicPrefix: topicPrefix,
+	}
+}
+
+func (s *BrokerSink) Name() string {
+	return "broker"
+}
+
+func (s *BrokerSink) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	key := event.AggregateType + ":" + strconv.Itoa(event.AggregateID)
+	return s.Publisher.Publish(ctx, s.TopicPrefix+event.Type, key, body)
+}
+
+// LogPublisher menulis event ke log, dipakai saat development tanpa broker
+type LogPublisher struct {
+	Log *logrus.Logger
+}
+
+func (p *LogPublisher) Publish(_ context.Context, topic, key string, body []byte) error {
+	p.Log.WithFields(logrus.Fields{"topic": topic, "key": key}).Info(string(body))
+	return nil
+}
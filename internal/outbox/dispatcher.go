@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Options mengatur dispatcher, dibaca dari env di config.NewOutboxOptions
+type Options struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int           // setelah ini event ditandai DEAD
+	Backoff      time.Duration // jeda retry pertama, dikali dua setiap gagal
+	MaxBackoff   time.Duration
+}
+
+// Dispatcher delivers pending outbox events to its sinks in insert order. When an event fails,
+// later events of the same aggregate are held back until it is delivered or marked DEAD.
+// Only one dispatcher may run at a time, it is run as a job so the job lease guarantees that.
+type Dispatcher struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	OutboxRepository *repository.OutboxRepository
+	Sinks            []Sink
+	Options          Options
+}
+
+func NewDispatcher(db *gorm.DB, log *logrus.Logger, outboxRepository *repository.OutboxRepository, sinks []Sink, options Options) *Dispatcher {
+	return &Dispatcher{
+		DB:               db,
+		Log:              log,
+		OutboxRepository: outboxRepository,
+		Sinks:            sinks,
+		Options:          options,
+	}
+}
+
+// Run polls the outbox every PollInterval until duration has passed or ctx is done
+func (d *Dispatcher) Run(ctx context.Context, duration time.Duration) error {
+	deadline := time.Now().Add(duration)
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			return err
+		}
+		if time.Now().Add(d.Options.PollInterval).After(deadline) {
+			return nil
+		}
+		select {
+		case <-time.After(d.Options.PollInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Dispatch delivers the events that are due, batch by batch, until the outbox has no due event left.
+// Every event of a batch is either delivered, rescheduled or held back behind a rescheduled event,
+// so none of them is returned by the next FindPending and the loop always ends.
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	db := d.DB.WithContext(ctx)
+	for {
+		records, err := d.OutboxRepository.FindPending(db, time.Now(), d.Options.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch outbox events: %w", err)
+		}
+
+		// Event yang gagal menahan event berikutnya dari aggregate yang sama di batch ini,
+		// batch berikutnya sudah disaring oleh FindPending
+		blocked := make(map[string]bool)
+		for i := range records {
+			record := &records[i]
+			aggregate := record.AggregateType + ":" + strconv.Itoa(record.AggregateID)
+			if blocked[aggregate] {
+				continue
+			}
+
+			if err := d.deliver(ctx, record); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				retry, err := d.fail(db, record, err)
+				if err != nil {
+					return fmt.Errorf("failed to record outbox event %d failure: %w", record.ID, err)
+				}
+				if retry {
+					blocked[aggregate] = true
+				}
+				continue
+			}
+			if err := d.OutboxRepository.MarkPublished(db, record.ID); err != nil {
+				return fmt.Errorf("failed to mark outbox event %d published: %w", record.ID, err)
+			}
+		}
+
+		if len(records) < d.Options.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, record *entity.OutboxEvent) error {
+	event := fromEntity(record)
+	for _, sink := range d.Sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// fail schedules a retry with exponential backoff, it returns false when the event is given up
+func (d *Dispatcher) fail(db *gorm.DB, record *entity.OutboxEvent, cause error) (bool, error) {
+	record.Attempts++
+	record.LastError = cause.Error()
+
+	retry := record.Attempts < d.Options.MaxAttempts
+	if retry {
+		backoff := d.Options.Backoff << (record.Attempts - 1)
+		if backoff > d.Options.MaxBackoff || backoff <= 0 {
+			backoff = d.Options.MaxBackoff
+		}
+		record.NextAttemptAt = time.Now().Add(backoff)
+		d.Log.Warnf("outbox event %s (%s) failed, attempt %d, retrying in %s: %v",
+			record.EventID, record.EventType, record.Attempts, backoff, cause)
+	} else {
+		// Event DEAD tidak lagi menahan event berikutnya, bisa dikirim ulang lewat replay
+		record.Status = enum.OutboxDead
+		d.Log.Errorf("outbox event %s (%s) given up after %d attempts: %v",
+			record.EventID, record.EventType, record.Attempts, cause)
+	}
+
+	// Gagal menyimpan berarti event akan diambil lagi oleh batch berikutnya, jadi Dispatch berhenti
+	if err := d.OutboxRepository.MarkFailed(db, record); err != nil {
+		return false, err
+	}
+	return retry, nil
+}
@@ -0,0 +1,103 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+)
+
+// recordingSink fails every event of the aggregates in failing and records the others in order
+type recordingSink struct {
+	failing   map[int]bool
+	delivered []string
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Deliver(ctx context.Context, event Event) error {
+	if s.failing[event.AggregateID] {
+		return errors.New("sink unavailable")
+	}
+	s.delivered = append(s.delivered, event.Type)
+	return nil
+}
+
+func TestDispatchHoldsBackOnlyTheFailingAggregate(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	log := testutil.NewLogger()
+	outboxRepository := repository.NewOutboxRepository(db, log)
+
+	// Order 1 gagal di event pertama, order 2 tidak boleh ikut tertahan
+	for _, event := range []struct {
+		orderID   int
+		eventType string
+	}{
+		{1, "order.created"},
+		{1, "order.paid"},
+		{2, "order.created"},
+		{2, "order.paid"},
+	} {
+		if err := outboxRepository.Append(db, enum.AggregateOrder, event.orderID, event.eventType, map[string]int{"id": event.orderID}); err != nil {
+			t.Fatalf("failed to append event: %v", err)
+		}
+	}
+
+	sink := &recordingSink{failing: map[int]bool{1: true}}
+	// Batch berisi satu event supaya event yang gagal selalu menjadi satu-satunya isi batch
+	dispatcher := NewDispatcher(db, log, outboxRepository, []Sink{sink}, Options{
+		BatchSize: 1, PollInterval: time.Second, MaxAttempts: 5, Backoff: time.Minute, MaxBackoff: time.Hour,
+	})
+	ctx := context.Background()
+
+	if err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if want := []string{"order.created", "order.paid"}; !reflect.DeepEqual(sink.delivered, want) {
+		t.Fatalf("delivered = %v, want order 2 events %v", sink.delivered, want)
+	}
+
+	var head entity.OutboxEvent
+	if err := db.Where("aggregate_id = ? AND event_type = ?", 1, "order.created").First(&head).Error; err != nil {
+		t.Fatalf("failed to load event: %v", err)
+	}
+	if head.Status != enum.OutboxPending || head.Attempts != 1 || !head.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("failed event = %s, %d attempts, next at %s, want a scheduled retry", head.Status, head.Attempts, head.NextAttemptAt)
+	}
+
+	// Selama retry belum jatuh tempo, event berikutnya dari order 1 tetap ditahan
+	pending, err := outboxRepository.FindPending(db, time.Now(), 10)
+	if err != nil {
+		t.Fatalf("FindPending() error = %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("FindPending() = %d events, want none before the retry is due", len(pending))
+	}
+
+	// Setelah jatuh tempo, order 1 dikirim dengan urutan aslinya
+	sink.failing = nil
+	sink.delivered = nil
+	if err := db.Model(&head).Update("next_attempt_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatalf("failed to reschedule event: %v", err)
+	}
+	if err := dispatcher.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if want := []string{"order.created", "order.paid"}; !reflect.DeepEqual(sink.delivered, want) {
+		t.Fatalf("delivered = %v, want order 1 events %v", sink.delivered, want)
+	}
+
+	var pendingCount int64
+	if err := db.Model(&entity.OutboxEvent{}).Where("status = ?", enum.OutboxPending).Count(&pendingCount).Error; err != nil {
+		t.Fatalf("failed to count events: %v", err)
+	}
+	if pendingCount != 0 {
+		t.Errorf("%d events still pending, want 0", pendingCount)
+	}
+}
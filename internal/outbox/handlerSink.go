@@ -0,0 +1,35 @@
+package outbox
+
+import "context"
+
+// HandlerFunc handles an event in-process
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// HandlerSink calls the handlers subscribed to an event type, "*" subscribes to every event
+type HandlerSink struct {
+	handlers map[string][]HandlerFunc
+}
+
+func NewHandlerSink() *HandlerSink {
+	return &HandlerSink{handlers: make(map[string][]HandlerFunc)}
+}
+
+// Subscribe must be called before the dispatcher starts
+func (s *HandlerSink) Subscribe(eventType string, handler HandlerFunc) {
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+func (s *HandlerSink) Name() string {
+	return "handlers"
+}
+
+func (s *HandlerSink) Deliver(ctx context.Context, event Event) error {
+	for _, key := range []string{event.Type, "*"} {
+		for _, handler := range s.handlers[key] {
+			if err := handler(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+)
+
+// Event is the envelope delivered to every sink. ID is stable across retries and replays,
+// consumers use it to drop duplicates.
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   int             `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// Sink receives dispatched events. Delivery is at-least-once so Deliver must be idempotent,
+// an error makes the dispatcher retry the event later.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event Event) error
+}
+
+func fromEntity(record *entity.OutboxEvent) Event {
+	return Event{
+		ID:            record.EventID,
+		Type:          record.EventType,
+		AggregateType: record.AggregateType,
+		AggregateID:   record.AggregateID,
+		OccurredAt:    record.CreatedAt,
+		Data:          json.RawMessage(record.Payload),
+	}
+}
@@ -0,0 +1,93 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// FakeProvider is an in-process provider for local development and testing. Nothing is charged,
+// payments are confirmed by posting an event signed with HMAC-SHA256(secret, body) to the webhook.
+type FakeProvider struct {
+	Secret []byte
+	Log    *logrus.Logger
+
+	mu      sync.Mutex
+	refunds map[string]bool // idempotency key refund yang sudah diproses
+}
+
+func NewFakeProvider(secret string, log *logrus.Logger) *FakeProvider {
+	return &FakeProvider{
+		Secret:  []byte(secret),
+		Log:     log,
+		refunds: make(map[string]bool),
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) CreatePaymentIntent(ctx context.Context, orderID int, amount money.Amount, currency string) (*Intent, error) {
+	ref := "fake_pi_" + uuid.NewString()
+	return &Intent{
+		ProviderRef:  ref,
+		ClientSecret: ref + "_secret",
+	}, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.sign(payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("payment: invalid webhook payload: %w", err)
+	}
+	if event.ID == "" || event.ProviderRef == "" {
+		return nil, fmt.Errorf("payment: webhook payload requires id and payment_ref")
+	}
+	return &event, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, providerRef string, amount money.Amount, idempotencyKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Sama seperti provider sungguhan, key yang sama tidak me-refund dua kali
+	if p.refunds[idempotencyKey] {
+		p.Log.Infof("fake provider ignored repeated refund %s for %s", idempotencyKey, providerRef)
+		return nil
+	}
+	p.refunds[idempotencyKey] = true
+	p.Log.Infof("fake provider refunded %s for %s", amount, providerRef)
+	return nil
+}
+
+// Refunds returns how many distinct refunds were made
+func (p *FakeProvider) Refunds() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.refunds)
+}
+
+// Sign returns the X-Payment-Signature value of a webhook body
+func (p *FakeProvider) Sign(payload []byte) string {
+	return hex.EncodeToString(p.sign(payload))
+}
+
+func (p *FakeProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.Secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// Event types delivered by a provider webhook
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// ErrInvalidSignature is returned by VerifyWebhook when the callback is not signed by the provider
+var ErrInvalidSignature = errors.New("payment: invalid webhook signature")
+
+// Intent is a payment attempt created at the provider, the client completes it with ClientSecret
+type Intent struct {
+	ProviderRef  string
+	ClientSecret string
+}
+
+// Event is a verified webhook callback. ID is unique per delivery and is used for idempotency.
+type Event struct {
+	ID          string       `json:"id"`
+	Type        string       `json:"type"`
+	ProviderRef string       `json:"payment_ref"`
+	Amount      money.Amount `json:"amount"`
+	Currency    string       `json:"currency"` // optional, dicek jika dikirim
+}
+
+// Provider is a payment gateway
+type Provider interface {
+	// Name is stored on every payment so refunds go back to the same provider
+	Name() string
+	CreatePaymentIntent(ctx context.Context, orderID int, amount money.Amount, currency string) (*Intent, error)
+	// VerifyWebhook checks the signature of a raw webhook body and parses the event
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+	// Refund returns the payment. A retry with the same idempotencyKey must not refund twice.
+	Refund(ctx context.Context, providerRef string, amount money.Amount, idempotencyKey string) error
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookRepository struct {
@@ -76,3 +77,97 @@ func (r *BookRepository) GetPriceStats(ctx context.Context, db *gorm.DB) (*model
 
 	return &res, nil
 }
+
+// bookSortColumns adalah whitelist sort key untuk list buku
+var bookSortColumns = map[string]string{
+	"price":      "price",
+	"title":      "title",
+	"year":       "year",
+	"created_at": "created_at",
+}
+
+// SearchSpec builds the filter/sort spec for the book listing
+func (r *BookRepository) SearchSpec(req *model.ListBooksRequest) (QuerySpec, error) {
+	var spec QuerySpec
+
+	if req.Q != "" {
+		pattern := ContainsPattern(req.Q)
+		spec.Where("("+ContainsCondition(r.DB, "books.title")+" OR "+ContainsCondition(r.DB, "books.author")+")", pattern, pattern)
+	}
+	if req.CategoryID > 0 {
+		spec.Where("books.category_id = ?", req.CategoryID)
+	}
+	if req.Author != "" {
+		spec.Where("books.author = ?", req.Author)
+	}
+	if req.MinPrice > 0 {
+		spec.Where("books.price >= ?", req.MinPrice)
+	}
+	if req.MaxPrice > 0 {
+		spec.Where("books.price <= ?", req.MaxPrice)
+	}
+	if req.YearFrom > 0 {
+		spec.Where("books.year >= ?", req.YearFrom)
+	}
+	if req.YearTo > 0 {
+		spec.Where("books.year <= ?", req.YearTo)
+	}
+
+	sorts, err := ParseSort(req.Sort, bookSortColumns)
+	if err != nil {
+		return QuerySpec{}, err
+	}
+	spec.Sorts = sorts
+
+	return spec, nil
+}
+
+// FindByIDForUpdate mengunci row buku sampai transaksi selesai
+func (r *BookRepository) FindByIDForUpdate(tx *gorm.DB, id int) (*entity.Book, error) {
+	var book entity.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, id).Error; err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// DecrementStock mengurangi stok secara atomic, false jika stok tidak cukup
+func (r *BookRepository) DecrementStock(tx *gorm.DB, bookID, quantity int) (bool, error) {
+	result := tx.Model(&entity.Book{}).
+		Where("id = ? AND stock >= ?", bookID, quantity).
+		UpdateColumn("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *BookRepository) IncrementStock(tx *gorm.DB, bookID, quantity int) error {
+	result := tx.Model(&entity.Book{}).
+		Where("id = ?", bookID).
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetStock membaca stok terkini, dipakai untuk balance_after di ledger
+func (r *BookRepository) GetStock(tx *gorm.DB, bookID int) (int, error) {
+	var stock int
+	if err := tx.Model(&entity.Book{}).Where("id = ?", bookID).Select("stock").Scan(&stock).Error; err != nil {
+		return 0, err
+	}
+	return stock, nil
+}
+
+// FindInBatches walks every book in id order with its category, used for the catalog export
+func (r *BookRepository) FindInBatches(db *gorm.DB, batchSize int, fn func(books []entity.Book) error) error {
+	var batch []entity.Book
+	return db.Preload("Category").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepository struct {
+	CommonQuery[entity.Cart]
+	Log *logrus.Logger
+}
+
+func NewCartRepository(db *gorm.DB, log *logrus.Logger) *CartRepository {
+	return &CartRepository{
+		CommonQuery: CommonQuery[entity.Cart]{DB: db},
+		Log:         log,
+	}
+}
+
+// FindOrCreateByUserID returns the cart of a user, creating an empty one on first use
+func (r *CartRepository) FindOrCreateByUserID(tx *gorm.DB, userID int) (*entity.Cart, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.Cart{UserID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var cart entity.Cart
+	if err := tx.Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// FindByUserID returns gorm.ErrRecordNotFound when the user has no cart yet
+func (r *CartRepository) FindByUserID(tx *gorm.DB, userID int) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := tx.Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// FindByUserIDWithItems preload item beserta buku untuk harga terbaru
+func (r *CartRepository) FindByUserIDWithItems(tx *gorm.DB, userID int) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := tx.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("cart_items.created_at ASC").Order("cart_items.book_id ASC")
+	}).Preload("Items.Book").
+		Where("user_id = ?", userID).
+		First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// LockByUserID mengunci cart supaya checkout paralel tidak membuat dua order dari isi yang sama
+func (r *CartRepository) LockByUserID(tx *gorm.DB, userID int) (*entity.Cart, error) {
+	var cart entity.Cart
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&cart).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("cart_id = ?", cart.ID).
+		Order("created_at ASC").Order("book_id ASC").
+		Find(&cart.Items).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// SaveItem menyimpan quantity total sebuah buku di cart. Quantity dihitung dan divalidasi oleh
+// pemanggil saat cart terkunci dengan LockByUserID.
+func (r *CartRepository) SaveItem(tx *gorm.DB, cartID, bookID, quantity int) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "book_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+	}).Create(&entity.CartItem{
+		CartID:   cartID,
+		BookID:   bookID,
+		Quantity: quantity,
+	}).Error
+}
+
+// UpdateItemQuantity returns false when the book is not in the cart
+func (r *CartRepository) UpdateItemQuantity(tx *gorm.DB, cartID, bookID, quantity int) (bool, error) {
+	result := tx.Model(&entity.CartItem{}).
+		Where("cart_id = ? AND book_id = ?", cartID, bookID).
+		Update("quantity", quantity)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RemoveItem returns false when the book is not in the cart
+func (r *CartRepository) RemoveItem(tx *gorm.DB, cartID, bookID int) (bool, error) {
+	result := tx.Where("cart_id = ? AND book_id = ?", cartID, bookID).Delete(&entity.CartItem{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *CartRepository) ClearItems(tx *gorm.DB, cartID int) error {
+	return tx.Where("cart_id = ?", cartID).Delete(&entity.CartItem{}).Error
+}
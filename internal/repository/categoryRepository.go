@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -31,3 +32,25 @@ func (r *CategoryRepository) FindByName(ctx context.Context, name string) (*enti
 	}
 	return &category, nil
 }
+
+var categorySortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+}
+
+// SearchSpec builds the filter/sort spec for the category listing
+func (r *CategoryRepository) SearchSpec(req *model.ListCategoriesRequest) (QuerySpec, error) {
+	var spec QuerySpec
+
+	if req.Q != "" {
+		spec.Where(ContainsCondition(r.DB, "categories.name"), ContainsPattern(req.Q))
+	}
+
+	sorts, err := ParseSort(req.Sort, categorySortColumns)
+	if err != nil {
+		return QuerySpec{}, err
+	}
+	spec.Sorts = sorts
+
+	return spec, nil
+}
@@ -2,15 +2,101 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrInvalidSort is returned by ParseSort for a sort key that is not whitelisted
+var ErrInvalidSort = errors.New("invalid sort")
+
 type CommonQuery[T any] struct {
 	DB *gorm.DB
 }
 
+// Filter is a single WHERE condition using ? placeholders
+type Filter struct {
+	Query string
+	Args  []any
+}
+
+// Sort is an ORDER BY column. Column must come from a whitelist, never straight from user input.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// QuerySpec groups filters and sorts so they are applied the same way to the count and the page query
+type QuerySpec struct {
+	Filters []Filter
+	Sorts   []Sort
+}
+
+// Where menambahkan filter ke spec
+func (s *QuerySpec) Where(query string, args ...any) *QuerySpec {
+	s.Filters = append(s.Filters, Filter{Query: query, Args: args})
+	return s
+}
+
+func (s QuerySpec) applyFilters(db *gorm.DB) *gorm.DB {
+	for _, f := range s.Filters {
+		db = db.Where(f.Query, f.Args...)
+	}
+	return db
+}
+
+// applySorts selalu menambahkan primary key sebagai tie-breaker supaya urutan halaman stabil
+func (s QuerySpec) applySorts(db *gorm.DB) *gorm.DB {
+	for _, sort := range s.Sorts {
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: sort.Column},
+			Desc:   sort.Desc,
+		})
+	}
+	return db.Order(clause.OrderByColumn{
+		Column: clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey},
+	})
+}
+
+// ParseSort parses a comma separated sort param like "price,-year" against a whitelist
+// mapping public sort keys to columns. A leading "-" means descending.
+func ParseSort(raw string, allowed map[string]string) ([]Sort, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var sorts []Sort
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		desc := strings.HasPrefix(key, "-")
+		column, ok := allowed[strings.TrimPrefix(key, "-")]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSort, key)
+		}
+		sorts = append(sorts, Sort{Column: column, Desc: desc})
+	}
+	return sorts, nil
+}
+
+// ContainsPattern builds a LIKE pattern matching value anywhere, use it with ContainsCondition
+func ContainsPattern(value string) string {
+	replacer := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return "%" + replacer.Replace(value) + "%"
+}
+
+// ContainsCondition returns a case-insensitive `column LIKE ? ESCAPE '!'` for the driver of db.
+// PostgreSQL needs ILIKE, LIKE on MySQL and SQLite already ignores case.
+func ContainsCondition(db *gorm.DB, column string) string {
+	operator := "LIKE"
+	if db.Dialector.Name() == "postgres" {
+		operator = "ILIKE"
+	}
+	return column + " " + operator + " ? ESCAPE '!'"
+}
+
 func (r *CommonQuery[T]) Create(db *gorm.DB, entity *T) error {
 	return db.Create(entity).Error
 }
@@ -28,6 +114,11 @@ func (r *CommonQuery[T]) FindById(db *gorm.DB, entity *T, id any) error {
 }
 
 func (r *CommonQuery[T]) Paginate(ctx context.Context, db *gorm.DB, page, size int, entities *[]T) (total int64, err error) {
+	return r.PaginateWithSpec(ctx, db, QuerySpec{}, page, size, entities)
+}
+
+// PaginateWithSpec is Paginate with filters and sorts applied to both the count and the page query
+func (r *CommonQuery[T]) PaginateWithSpec(ctx context.Context, db *gorm.DB, spec QuerySpec, page, size int, entities *[]T) (total int64, err error) {
 	// Validasi page & size
 	if page < 1 {
 		page = 1
@@ -38,12 +129,13 @@ func (r *CommonQuery[T]) Paginate(ctx context.Context, db *gorm.DB, page, size i
 	offset := (page - 1) * size
 
 	// Hitung total data
-	if err = db.WithContext(ctx).Model(new(T)).Count(&total).Error; err != nil {
+	if err = spec.applyFilters(db.WithContext(ctx).Model(new(T))).Count(&total).Error; err != nil {
 		return 0, fmt.Errorf("failed to count total: %w", err)
 	}
 
 	// Ambil data dengan limit & offset
-	if err = db.WithContext(ctx).Limit(size).Offset(offset).Find(entities).Error; err != nil {
+	query := spec.applySorts(spec.applyFilters(db.WithContext(ctx)))
+	if err = query.Limit(size).Offset(offset).Find(entities).Error; err != nil {
 		return 0, fmt.Errorf("failed to fetch data: %w", err)
 	}
 
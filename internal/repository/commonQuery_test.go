@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestContainsConditionPerDialect(t *testing.T) {
+	sqliteDB := testutil.NewDatabase(t)
+
+	// DryRun tanpa ping, query hanya dibangun dan tidak pernah dikirim ke server.
+	// Config tidak boleh dipakai bersama, gorm menyimpan dialector di dalamnya.
+	dryRun := func() *gorm.Config { return &gorm.Config{DryRun: true, DisableAutomaticPing: true} }
+	mysqlDB, err := gorm.Open(mysql.New(mysql.Config{DSN: "user:pass@tcp(localhost:3306)/db", SkipInitializeWithVersion: true}), dryRun())
+	if err != nil {
+		t.Fatalf("failed to open mysql dialector: %v", err)
+	}
+	postgresDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost user=user dbname=db"}), dryRun())
+	if err != nil {
+		t.Fatalf("failed to open postgres dialector: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		db       *gorm.DB
+		operator string
+	}{
+		{"mysql", mysqlDB, " LIKE "},
+		{"postgres", postgresDB, " ILIKE "},
+		{"sqlite", sqliteDB, " LIKE "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition := ContainsCondition(tt.db, "categories.name")
+			if !strings.Contains(condition, tt.operator) || !strings.HasSuffix(condition, "ESCAPE '!'") {
+				t.Fatalf("ContainsCondition() = %q, want operator %q with ESCAPE", condition, strings.TrimSpace(tt.operator))
+			}
+
+			var categories []entity.Category
+			sql := tt.db.Session(&gorm.Session{DryRun: true}).
+				Where(condition, ContainsPattern("x")).
+				Find(&categories).Statement.SQL.String()
+			if !strings.Contains(sql, tt.operator) {
+				t.Fatalf("generated SQL %q does not use %q", sql, strings.TrimSpace(tt.operator))
+			}
+		})
+	}
+}
+
+func TestCategorySearchIsCaseInsensitiveAndEscaped(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	repo := NewCategoryRepository(db, testutil.NewLogger())
+
+	for _, name := range []string{"Science Fiction", "Popular science", "Poetry", "100% Cotton", "snake_case"} {
+		if err := db.Create(&entity.Category{Name: name}).Error; err != nil {
+			t.Fatalf("failed to create category: %v", err)
+		}
+	}
+
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"SCIENCE", []string{"Science Fiction", "Popular science"}},
+		{"poe", []string{"Poetry"}},
+		{"100%", []string{"100% Cotton"}},
+		{"%", []string{"100% Cotton"}},
+		{"e_c", []string{"snake_case"}},
+		{"_", []string{"snake_case"}},
+		{"!", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			spec, err := repo.SearchSpec(&model.ListCategoriesRequest{Q: tt.q})
+			if err != nil {
+				t.Fatalf("SearchSpec() error = %v", err)
+			}
+			var categories []entity.Category
+			total, err := repo.PaginateWithSpec(context.Background(), db, spec, 1, 10, &categories)
+			if err != nil {
+				t.Fatalf("PaginateWithSpec() error = %v", err)
+			}
+
+			var got []string
+			for _, category := range categories {
+				got = append(got, category.Name)
+			}
+			if total != int64(len(tt.want)) || strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("search %q = %v (total %d), want %v", tt.q, got, total, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponRepository struct {
+	CommonQuery[entity.Coupon]
+	Log *logrus.Logger
+}
+
+func NewCouponRepository(db *gorm.DB, log *logrus.Logger) *CouponRepository {
+	return &CouponRepository{
+		CommonQuery: CommonQuery[entity.Coupon]{DB: db},
+		Log:         log,
+	}
+}
+
+func (r *CouponRepository) FindByCode(tx *gorm.DB, code string) (*entity.Coupon, error) {
+	var coupon entity.Coupon
+	if err := tx.Where("code = ?", code).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// FindByCodeForUpdate mengunci kupon sampai transaksi checkout selesai,
+// sehingga pengecekan limit per user tidak balapan dengan checkout lain
+func (r *CouponRepository) FindByCodeForUpdate(tx *gorm.DB, code string) (*entity.Coupon, error) {
+	return r.FindByCode(tx.Clauses(clause.Locking{Strength: "UPDATE"}), code)
+}
+
+// IncrementUsage menaikkan used_count hanya jika usage_limit belum tercapai
+func (r *CouponRepository) IncrementUsage(tx *gorm.DB, couponID int) (bool, error) {
+	result := tx.Model(&entity.Coupon{}).
+		Where("id = ? AND (usage_limit IS NULL OR used_count < usage_limit)", couponID).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *CouponRepository) DecrementUsage(tx *gorm.DB, couponID int) error {
+	return tx.Model(&entity.Coupon{}).
+		Where("id = ? AND used_count > 0", couponID).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
+
+func (r *CouponRepository) CountUserRedemptions(tx *gorm.DB, couponID, userID int) (int64, error) {
+	var total int64
+	if err := tx.Model(&entity.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ?", couponID, userID).
+		Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *CouponRepository) CreateRedemption(tx *gorm.DB, redemption *entity.CouponRedemption) error {
+	return tx.Create(redemption).Error
+}
+
+// DeleteRedemptionByOrderID returns the removed redemption, nil when the order used no coupon
+func (r *CouponRepository) DeleteRedemptionByOrderID(tx *gorm.DB, orderID int) (*entity.CouponRedemption, error) {
+	var redemption entity.CouponRedemption
+	if err := tx.Where("order_id = ?", orderID).First(&redemption).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := tx.Delete(&redemption).Error; err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+var couponSortColumns = map[string]string{
+	"code":       "code",
+	"created_at": "created_at",
+	"ends_at":    "ends_at",
+}
+
+// SearchSpec builds the filter/sort spec for the admin coupon listing
+func (r *CouponRepository) SearchSpec(req *model.ListCouponsRequest) (QuerySpec, error) {
+	var spec QuerySpec
+
+	if req.Q != "" {
+		spec.Where(ContainsCondition(r.DB, "coupons.code"), ContainsPattern(req.Q))
+	}
+	if req.Active != nil {
+		spec.Where("coupons.active = ?", *req.Active)
+	}
+
+	sorts, err := ParseSort(req.Sort, couponSortColumns)
+	if err != nil {
+		return QuerySpec{}, err
+	}
+	spec.Sorts = sorts
+
+	return spec, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded or does not match the sort
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorPayload adalah isi cursor sebelum di-encode base64, Values berisi nilai sort key
+// (kolom sort lalu primary key) dari baris batas halaman.
+type cursorPayload struct {
+	Values   []any `json:"v"`
+	Backward bool  `json:"b,omitempty"`
+}
+
+// PaginateByCursor implements keyset pagination. Rows are ordered by the first sort of spec
+// (or the primary key when there is none) with the primary key as tie-breaker, and the page
+// starts right after the row encoded in cursor, so inserts between requests never shift pages.
+// The returned cursors are opaque and empty when there is no next/previous page.
+func (r *CommonQuery[T]) PaginateByCursor(ctx context.Context, db *gorm.DB, spec QuerySpec, cursor string, limit int, entities *[]T) (nextCursor, prevCursor string, err error) {
+	if limit < 1 {
+		limit = 10
+	}
+	if len(spec.Sorts) > 1 {
+		return "", "", fmt.Errorf("%w: cursor pagination supports a single sort key", ErrInvalidSort)
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return "", "", fmt.Errorf("failed to parse schema: %w", err)
+	}
+	primary := stmt.Schema.PrioritizedPrimaryField
+	if primary == nil {
+		return "", "", fmt.Errorf("cursor pagination requires a primary key")
+	}
+
+	// Tentukan sort key: kolom sort (jika ada) + primary key, semua dengan arah yang sama
+	keys := []*schema.Field{primary}
+	desc := false
+	if len(spec.Sorts) == 1 {
+		field := stmt.Schema.LookUpField(spec.Sorts[0].Column)
+		if field == nil {
+			return "", "", fmt.Errorf("%w: %s", ErrInvalidSort, spec.Sorts[0].Column)
+		}
+		desc = spec.Sorts[0].Desc
+		if field != primary {
+			keys = []*schema.Field{field, primary}
+		}
+	}
+
+	var after *cursorPayload
+	if cursor != "" {
+		after, err = decodeCursor(cursor, keys)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	backward := after != nil && after.Backward
+
+	query := spec.applyFilters(db.WithContext(ctx))
+
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = stmt.Quote(clause.Column{Table: stmt.Schema.Table, Name: key.DBName})
+	}
+
+	// Halaman mundur (prev) dibaca dengan arah terbalik lalu hasilnya dibalik lagi
+	scanDesc := desc != backward
+	if after != nil {
+		op := ">"
+		if scanDesc {
+			op = "<"
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		if len(keys) == 1 {
+			query = query.Where(fmt.Sprintf("%s %s ?", columns[0], op), after.Values[0])
+		} else {
+			query = query.Where(fmt.Sprintf("(%s) %s (%s)", strings.Join(columns, ", "), op, placeholders), after.Values...)
+		}
+	}
+	for _, key := range keys {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: key.DBName},
+			Desc:   scanDesc,
+		})
+	}
+
+	if err = query.Limit(limit + 1).Find(entities).Error; err != nil {
+		return "", "", fmt.Errorf("failed to fetch data: %w", err)
+	}
+
+	rows := *entities
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	*entities = rows
+
+	if len(rows) == 0 {
+		return "", "", nil
+	}
+
+	if backward || hasMore {
+		if nextCursor, err = encodeCursor(ctx, keys, &rows[len(rows)-1], false); err != nil {
+			return "", "", err
+		}
+	}
+	if (!backward && after != nil) || (backward && hasMore) {
+		if prevCursor, err = encodeCursor(ctx, keys, &rows[0], true); err != nil {
+			return "", "", err
+		}
+	}
+
+	return nextCursor, prevCursor, nil
+}
+
+func encodeCursor[T any](ctx context.Context, keys []*schema.Field, row *T, backward bool) (string, error) {
+	rowValue := reflect.ValueOf(row).Elem()
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		value, _ := key.ValueOf(ctx, rowValue)
+		v := reflect.ValueOf(value)
+		switch {
+		case v.Type() == reflect.TypeOf(time.Time{}):
+			values[i] = value.(time.Time).Format(time.RFC3339Nano)
+		case v.CanInt():
+			values[i] = v.Int()
+		case v.CanUint():
+			values[i] = v.Uint()
+		case v.CanFloat():
+			values[i] = v.Float()
+		case v.Kind() == reflect.String:
+			values[i] = v.String()
+		default:
+			return "", fmt.Errorf("unsupported cursor column type %s", v.Type())
+		}
+	}
+
+	raw, err := json.Marshal(cursorPayload{Values: values, Backward: backward})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor mengembalikan nilai cursor ke tipe field aslinya supaya bisa dipakai sebagai argumen query
+func decodeCursor(cursor string, keys []*schema.Field) (*cursorPayload, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil || len(payload.Values) != len(keys) {
+		return nil, ErrInvalidCursor
+	}
+
+	for i, key := range keys {
+		value, err := cursorValue(payload.Values[i], key.FieldType)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		payload.Values[i] = value
+	}
+	return &payload, nil
+}
+
+func cursorValue(raw any, fieldType reflect.Type) (any, error) {
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		s, ok := raw.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	}
+
+	var v reflect.Value
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, err
+		}
+		v = reflect.ValueOf(i)
+	case reflect.Float32, reflect.Float64:
+		n, ok := raw.(json.Number)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return nil, err
+		}
+		v = reflect.ValueOf(f)
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, ErrInvalidCursor
+		}
+		v = reflect.ValueOf(s)
+	default:
+		return nil, ErrInvalidCursor
+	}
+
+	// Convert ke tipe field (mis. tipe custom) supaya driver.Valuer-nya ikut terpakai
+	return v.Convert(fieldType).Interface(), nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository struct {
+	CommonQuery[entity.IdempotencyKey]
+	Log *logrus.Logger
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB, log *logrus.Logger) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		CommonQuery: CommonQuery[entity.IdempotencyKey]{DB: db},
+		Log:         log,
+	}
+}
+
+// Reserve implements middleware.IdempotencyStore. It inserts an IN_PROGRESS record for the key,
+// when the key already exists the stored record is returned with created false.
+func (r *IdempotencyKeyRepository) Reserve(ctx context.Context, userID int, key, requestHash string, ttl time.Duration) (*entity.IdempotencyKey, bool, error) {
+	db := r.DB.WithContext(ctx)
+
+	// Dua kali percobaan: key yang sudah expired dihapus lalu di-insert ulang
+	for attempt := 0; attempt < 2; attempt++ {
+		record := &entity.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			Status:      enum.IdempotencyInProgress,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil {
+			return nil, false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return record, true, nil
+		}
+
+		var existing entity.IdempotencyKey
+		if err := db.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&existing).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				continue // dihapus di antara insert dan select
+			}
+			return nil, false, err
+		}
+		if existing.ExpiresAt.After(time.Now()) {
+			return &existing, false, nil
+		}
+		if err := db.Where("id = ? AND expires_at <= ?", existing.ID, time.Now()).Delete(&entity.IdempotencyKey{}).Error; err != nil {
+			return nil, false, err
+		}
+	}
+	return nil, false, gorm.ErrDuplicatedKey
+}
+
+// Complete stores the response of the first request
+func (r *IdempotencyKeyRepository) Complete(ctx context.Context, id int, code int, body []byte) error {
+	return r.DB.WithContext(ctx).Model(&entity.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":        enum.IdempotencyCompleted,
+			"response_code": code,
+			"response_body": body,
+		}).Error
+}
+
+// Release menghapus key supaya client bisa retry, dipakai saat request gagal dengan 5xx
+func (r *IdempotencyKeyRepository) Release(ctx context.Context, id int) error {
+	return r.DB.WithContext(ctx).Delete(&entity.IdempotencyKey{}, id).Error
+}
+
+func (r *IdempotencyKeyRepository) DeleteExpired(tx *gorm.DB) (int64, error) {
+	result := tx.Where("expires_at <= ?", time.Now()).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
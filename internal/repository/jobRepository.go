@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	CommonQuery[entity.JobRun]
+	Log *logrus.Logger
+}
+
+func NewJobRepository(db *gorm.DB, log *logrus.Logger) *JobRepository {
+	return &JobRepository{
+		CommonQuery: CommonQuery[entity.JobRun]{DB: db},
+		Log:         log,
+	}
+}
+
+// AcquireLease takes the lock of a job for ttl. It fails when another owner holds a lease
+// that has not expired, including a previous run of the same process that is still going.
+func (r *JobRepository) AcquireLease(tx *gorm.DB, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lease := &entity.JobLease{Name: name, Owner: owner, ExpiresAt: now.Add(ttl)}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(lease)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// Lease milik replica yang mati diambil alih setelah expired
+	result = tx.Model(&entity.JobLease{}).
+		Where("name = ? AND expires_at < ?", name, now).
+		Updates(map[string]any{"owner": owner, "expires_at": now.Add(ttl)})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseLease only deletes the lease when it is still held by owner
+func (r *JobRepository) ReleaseLease(tx *gorm.DB, name, owner string) error {
+	return tx.Where("name = ? AND owner = ?", name, owner).Delete(&entity.JobLease{}).Error
+}
+
+// MarkInterrupted fails RUNNING runs of a job, only called while holding its lease
+// so any run still RUNNING belongs to a process that died mid-run
+func (r *JobRepository) MarkInterrupted(tx *gorm.DB, name string) error {
+	return tx.Model(&entity.JobRun{}).
+		Where("job_name = ? AND status = ?", name, enum.JobRunning).
+		Updates(map[string]any{
+			"status":      enum.JobFailed,
+			"error":       "interrupted",
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// FindLastRun returns the most recent run of a job, nil when it never ran
+func (r *JobRepository) FindLastRun(tx *gorm.DB, name string) (*entity.JobRun, error) {
+	var run entity.JobRun
+	if err := tx.Where("job_name = ?", name).Order("id DESC").First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+// DeleteFinishedBefore removes finished runs that started before before
+func (r *JobRepository) DeleteFinishedBefore(tx *gorm.DB, before time.Time) (int64, error) {
+	result := tx.Where("status <> ? AND started_at < ?", enum.JobRunning, before).Delete(&entity.JobRun{})
+	return result.RowsAffected, result.Error
+}
+
+// RunsSpec builds the filters for GET /admin/jobs/:name/runs, newest first
+func (r *JobRepository) RunsSpec(name, status string) QuerySpec {
+	var spec QuerySpec
+	spec.Where("job_runs.job_name = ?", name)
+	if status != "" {
+		spec.Where("job_runs.status = ?", status)
+	}
+	spec.Sorts = []Sort{{Column: "started_at", Desc: true}}
+	return spec
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type OrderNoteRepository struct {
+	CommonQuery[entity.OrderNote]
+	Log *logrus.Logger
+}
+
+func NewOrderNoteRepository(db *gorm.DB, log *logrus.Logger) *OrderNoteRepository {
+	return &OrderNoteRepository{
+		CommonQuery: CommonQuery[entity.OrderNote]{DB: db},
+		Log:         log,
+	}
+}
+
+// FindByOrderID returns the notes of an order oldest first with the author loaded
+func (r *OrderNoteRepository) FindByOrderID(tx *gorm.DB, orderID int) ([]entity.OrderNote, error) {
+	var notes []entity.OrderNote
+	if err := tx.Preload("Author").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
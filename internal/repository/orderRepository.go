@@ -5,8 +5,10 @@ import (
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository struct {
@@ -26,51 +28,98 @@ func (r *OrderRepository) Create(tx *gorm.DB, order *entity.Order) error {
 	return tx.Create(order).Error
 }
 
-// FindByID preload BookOrders dan Book
+// FindByID preload BookOrders, data buku diambil dari snapshot di baris order
 func (r *OrderRepository) FindByID(tx *gorm.DB, orderID int) (*entity.Order, error) {
 	var order entity.Order
-	if err := tx.Preload("BookOrders.Book").First(&order, orderID).Error; err != nil {
+	if err := tx.Preload("BookOrders").First(&order, orderID).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
-// FindByUserID preload BookOrders dan Book untuk semua order user
-func (r *OrderRepository) FindByUserID(tx *gorm.DB, userID int) ([]entity.Order, error) {
-	var orders []entity.Order
-	if err := tx.Preload("BookOrders.Book").
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
-		Find(&orders).Error; err != nil {
-		return nil, err
+// UserOrdersSpec lists a user's orders newest first, filtered by status and creation date.
+// DateFrom and DateTo must already be validated as YYYY-MM-DD.
+func (r *OrderRepository) UserOrdersSpec(userID int, req *model.ListOrdersRequest) QuerySpec {
+	var spec QuerySpec
+	spec.Where("orders.user_id = ?", userID)
+	whereOrderStatusAndDate(&spec, req.Status, req.DateFrom, req.DateTo)
+	spec.Sorts = []Sort{{Column: "created_at", Desc: true}}
+	return spec
+}
+
+// AdminOrdersSpec lists every order newest first with the back-office filters
+func (r *OrderRepository) AdminOrdersSpec(req *model.AdminListOrdersRequest) QuerySpec {
+	var spec QuerySpec
+	if req.UserID > 0 {
+		spec.Where("orders.user_id = ?", req.UserID)
+	}
+	whereOrderStatusAndDate(&spec, req.Status, req.DateFrom, req.DateTo)
+	if req.MinTotal > 0 {
+		spec.Where("orders.total_price >= ?", req.MinTotal)
+	}
+	if req.BookID > 0 {
+		spec.Where("EXISTS (SELECT 1 FROM book_orders WHERE book_orders.order_id = orders.id AND book_orders.book_id = ?)", req.BookID)
 	}
-	return orders, nil
+	spec.Sorts = []Sort{{Column: "created_at", Desc: true}}
+	return spec
 }
 
-func (r *OrderRepository) UpdateStatus(tx *gorm.DB, orderID int, status string) error {
-	result := tx.Model(&entity.Order{}).Where("id = ?", orderID).Update("status", status)
-	if result.Error != nil {
-		return result.Error
+func whereOrderStatusAndDate(spec *QuerySpec, status, dateFrom, dateTo string) {
+	if status != "" {
+		spec.Where("orders.status = ?", status)
+	}
+	if from, err := time.ParseInLocation(time.DateOnly, dateFrom, time.Local); err == nil {
+		spec.Where("orders.created_at >= ?", from)
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	// date_to inklusif, jadi dibandingkan dengan awal hari berikutnya
+	if to, err := time.ParseInLocation(time.DateOnly, dateTo, time.Local); err == nil {
+		spec.Where("orders.created_at < ?", to.AddDate(0, 0, 1))
 	}
-	return nil
 }
 
-func (r *OrderRepository) CancelExpiredOrders(tx *gorm.DB) error {
-	cutoff := time.Now().Add(-15 * time.Minute) // waktu 15 menit lalu
+// FindInBatchesWithSpec walks every order matching the spec filters in id order, used for exports.
+// Sorts in the spec are ignored because batching pages by primary key.
+func (r *OrderRepository) FindInBatchesWithSpec(db *gorm.DB, spec QuerySpec, batchSize int, fn func(orders []entity.Order) error) error {
+	var batch []entity.Order
+	return spec.applyFilters(db).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
 
+// TransitionStatus mengubah status hanya jika status di database masih sama dengan from,
+// false berarti order sudah diubah oleh request lain
+func (r *OrderRepository) TransitionStatus(tx *gorm.DB, orderID int, from, to string) (bool, error) {
 	result := tx.Model(&entity.Order{}).
-		Where("status = ?", enum.Pending).
-		Where("created_at <= ?", cutoff).
-		Update("status", enum.Cancelled)
-
+		Where("id = ? AND status = ?", orderID, from).
+		Update("status", to)
 	if result.Error != nil {
-		return result.Error
+		return false, result.Error
 	}
-	if result.RowsAffected == 0 {
-		return gorm.ErrRecordNotFound
+	return result.RowsAffected == 1, nil
+}
+
+// FindExpiredOrders mengunci order PENDING yang melewati paymentWindow belum dibayar beserta BookOrders-nya
+func (r *OrderRepository) FindExpiredOrders(tx *gorm.DB, paymentWindow time.Duration) ([]entity.Order, error) {
+	cutoff := time.Now().Add(-paymentWindow)
+
+	var expired []entity.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("BookOrders").
+		Where("status = ?", enum.Pending).
+		Where("created_at <= ?", cutoff).
+		Find(&expired).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return expired, nil
+}
+
+// CountByStatus menghitung jumlah dan total harga order per status
+func (r *OrderRepository) CountByStatus(db *gorm.DB) ([]model.OrderStatusCountResponse, error) {
+	var counts []model.OrderStatusCountResponse
+	err := db.Model(&entity.Order{}).
+		Select("status, COUNT(*) AS count, COALESCE(SUM(total_price), 0) AS total_price").
+		Group("status").
+		Order("status").
+		Scan(&counts).Error
+	return counts, err
 }
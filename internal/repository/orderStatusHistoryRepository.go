@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type OrderStatusHistoryRepository struct {
+	CommonQuery[entity.OrderStatusHistory]
+	Log *logrus.Logger
+}
+
+func NewOrderStatusHistoryRepository(db *gorm.DB, log *logrus.Logger) *OrderStatusHistoryRepository {
+	return &OrderStatusHistoryRepository{
+		CommonQuery: CommonQuery[entity.OrderStatusHistory]{DB: db},
+		Log:         log,
+	}
+}
+
+// FindByOrderID returns the status history of an order oldest first
+func (r *OrderStatusHistoryRepository) FindByOrderID(tx *gorm.DB, orderID int) ([]entity.OrderStatusHistory, error) {
+	var histories []entity.OrderStatusHistory
+	if err := tx.Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
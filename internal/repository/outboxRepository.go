@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository struct {
+	CommonQuery[entity.OutboxEvent]
+	Log *logrus.Logger
+}
+
+func NewOutboxRepository(db *gorm.DB, log *logrus.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		CommonQuery: CommonQuery[entity.OutboxEvent]{DB: db},
+		Log:         log,
+	}
+}
+
+// Append writes an event with payload encoded as JSON. tx must be the transaction of the state
+// change so the event is stored if and only if the change is committed.
+func (r *OutboxRepository) Append(tx *gorm.DB, aggregateType string, aggregateID int, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&entity.OutboxEvent{
+		EventID:       uuid.NewString(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		Status:        enum.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// FindPending returns the oldest events that are due at now in insert order. Events of an aggregate
+// that still has an earlier event waiting for a retry are skipped, so one failing event only holds
+// back its own aggregate and never the whole outbox.
+func (r *OutboxRepository) FindPending(tx *gorm.DB, now time.Time, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	if err := tx.Where("outbox_events.status = ? AND outbox_events.next_attempt_at <= ?", enum.OutboxPending, now).
+		Where(`NOT EXISTS (SELECT 1 FROM outbox_events earlier
+			WHERE earlier.aggregate_type = outbox_events.aggregate_type
+			AND earlier.aggregate_id = outbox_events.aggregate_id
+			AND earlier.id < outbox_events.id
+			AND earlier.status = ? AND earlier.next_attempt_at > ?)`, enum.OutboxPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkPublished(tx *gorm.DB, id int64) error {
+	return tx.Model(&entity.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status":       enum.OutboxPublished,
+			"published_at": time.Now(),
+			"last_error":   "",
+		}).Error
+}
+
+// MarkFailed records a failed delivery, status is PENDING with a later next_attempt_at or DEAD
+func (r *OutboxRepository) MarkFailed(tx *gorm.DB, event *entity.OutboxEvent) error {
+	return tx.Model(&entity.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		Updates(map[string]any{
+			"status":          event.Status,
+			"attempts":        event.Attempts,
+			"last_error":      event.LastError,
+			"next_attempt_at": event.NextAttemptAt,
+		}).Error
+}
+
+// Requeue marks every event created in [from, to) as pending again so the dispatcher delivers
+// them once more in their original order. eventType is optional.
+func (r *OutboxRepository) Requeue(tx *gorm.DB, from, to time.Time, eventType string) (int64, error) {
+	query := tx.Model(&entity.OutboxEvent{}).Where("created_at >= ? AND created_at < ?", from, to)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	result := query.Updates(map[string]any{
+		"status":          enum.OutboxPending,
+		"attempts":        0,
+		"last_error":      "",
+		"next_attempt_at": time.Now(),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// DeletePublishedBefore removes delivered events older than before
+func (r *OutboxRepository) DeletePublishedBefore(tx *gorm.DB, before time.Time) (int64, error) {
+	result := tx.Where("status = ? AND created_at < ?", enum.OutboxPublished, before).Delete(&entity.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// SearchSpec builds the filters for GET /admin/events, newest first
+func (r *OutboxRepository) SearchSpec(req *model.ListEventsRequest) QuerySpec {
+	var spec QuerySpec
+	if req.Status != "" {
+		spec.Where("outbox_events.status = ?", req.Status)
+	}
+	if req.Type != "" {
+		spec.Where("outbox_events.event_type = ?", req.Type)
+	}
+	if req.AggregateType != "" {
+		spec.Where("outbox_events.aggregate_type = ?", req.AggregateType)
+	}
+	if req.AggregateID > 0 {
+		spec.Where("outbox_events.aggregate_id = ?", req.AggregateID)
+	}
+	spec.Sorts = []Sort{{Column: "id", Desc: true}}
+	return spec
+}
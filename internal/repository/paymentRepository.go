@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	CommonQuery[entity.Payment]
+	Log *logrus.Logger
+}
+
+func NewPaymentRepository(db *gorm.DB, log *logrus.Logger) *PaymentRepository {
+	return &PaymentRepository{
+		CommonQuery: CommonQuery[entity.Payment]{DB: db},
+		Log:         log,
+	}
+}
+
+// FindByProviderRefForUpdate mengunci payment supaya webhook paralel diproses berurutan
+func (r *PaymentRepository) FindByProviderRefForUpdate(tx *gorm.DB, providerRef string) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("provider_ref = ?", providerRef).
+		First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// FindRefundableByOrderID returns the payment of an order that was settled or whose refund was
+// started but not confirmed, locked for the refund
+func (r *PaymentRepository) FindRefundableByOrderID(tx *gorm.DB, orderID int) (*entity.Payment, error) {
+	var payment entity.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status IN ?", orderID, []string{enum.PaymentSucceeded, enum.PaymentRefunding}).
+		Order("id DESC").
+		First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepository) UpdateStatus(tx *gorm.DB, paymentID int, status string) error {
+	return tx.Model(&entity.Payment{}).Where("id = ?", paymentID).Update("status", status).Error
+}
+
+// CreateEvent mencatat webhook event, false berarti event ini sudah pernah diproses
+func (r *PaymentRepository) CreateEvent(tx *gorm.DB, event *entity.PaymentEvent) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
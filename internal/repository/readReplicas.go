@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// ReadReplicas spreads read-only queries over the healthy replicas round robin. Replicas lag
+// behind the primary, so only reads that tolerate stale data should use Reader.
+type ReadReplicas struct {
+	Primary *gorm.DB
+	Log     *logrus.Logger
+
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+func NewReadReplicas(primary *gorm.DB, log *logrus.Logger, replicas ...*gorm.DB) *ReadReplicas {
+	r := &ReadReplicas{Primary: primary, Log: log}
+	for _, db := range replicas {
+		rep := &replica{db: db}
+		rep.healthy.Store(true)
+		r.replicas = append(r.replicas, rep)
+	}
+	return r
+}
+
+// Reader returns the next healthy replica, or the primary when none is healthy
+func (r *ReadReplicas) Reader() *gorm.DB {
+	n := len(r.replicas)
+	start := r.next.Add(1)
+	for i := 0; i < n; i++ {
+		rep := r.replicas[(start+uint64(i))%uint64(n)]
+		if rep.healthy.Load() {
+			return rep.db
+		}
+	}
+	return r.Primary
+}
+
+// CheckHealth pings every replica and marks it healthy or not
+func (r *ReadReplicas) CheckHealth(ctx context.Context) {
+	for i, rep := range r.replicas {
+		err := ping(ctx, rep.db)
+		if was := rep.healthy.Swap(err == nil); was && err != nil {
+			r.Log.Warnf("read replica %d is unhealthy, reads fall back: %v", i, err)
+		} else if !was && err == nil {
+			r.Log.Infof("read replica %d is healthy again", i)
+		}
+	}
+}
+
+// Monitor runs CheckHealth every interval until ctx is cancelled
+func (r *ReadReplicas) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.CheckHealth(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openReplica opens a separate in-memory database standing in for a replica. A closed replica
+// fails every ping, the same as a replica that went down.
+func openReplica(t *testing.T, name string, closed bool) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open replica: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get replica pool: %v", err)
+	}
+	if closed {
+		sqlDB.Close()
+	} else {
+		t.Cleanup(func() { sqlDB.Close() })
+	}
+	return db
+}
+
+func TestReaderSkipsUnhealthyReplicas(t *testing.T) {
+	primary := testutil.NewDatabase(t)
+	down := openReplica(t, "replica_down", true)
+	up := openReplica(t, "replica_up", false)
+
+	replicas := NewReadReplicas(primary, testutil.NewLogger(), down, up)
+	replicas.CheckHealth(context.Background())
+
+	for i := 0; i < 4; i++ {
+		if reader := replicas.Reader(); reader != up {
+			t.Fatalf("Reader() call %d did not return the healthy replica", i+1)
+		}
+	}
+}
+
+func TestReaderFallsBackToPrimary(t *testing.T) {
+	primary := testutil.NewDatabase(t)
+	down := openReplica(t, "replica_down_only", true)
+
+	replicas := NewReadReplicas(primary, testutil.NewLogger(), down)
+	// Replica dianggap sehat sampai health check pertama
+	if replicas.Reader() != down {
+		t.Fatalf("Reader() before the first health check should return the replica")
+	}
+
+	replicas.CheckHealth(context.Background())
+	if replicas.Reader() != primary {
+		t.Fatalf("Reader() with every replica unhealthy should return the primary")
+	}
+
+	// Tanpa replica sama sekali semua read ke primary
+	if NewReadReplicas(primary, testutil.NewLogger()).Reader() != primary {
+		t.Fatalf("Reader() without replicas should return the primary")
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository struct {
+	CommonQuery[entity.RefreshToken]
+	Log *logrus.Logger
+}
+
+func NewRefreshTokenRepository(db *gorm.DB, log *logrus.Logger) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		CommonQuery: CommonQuery[entity.RefreshToken]{DB: db},
+		Log:         log,
+	}
+}
+
+// FindByHash mengunci row refresh token supaya rotasi paralel tidak bisa memakai token yang sama
+func (r *RefreshTokenRepository) FindByHash(tx *gorm.DB, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) Revoke(tx *gorm.DB, id int) error {
+	return tx.Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeFamily mencabut semua refresh token dalam satu family (satu sesi login)
+func (r *RefreshTokenRepository) RevokeFamily(tx *gorm.DB, familyID string) error {
+	return tx.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllByUser mencabut semua sesi login user
+func (r *RefreshTokenRepository) RevokeAllByUser(tx *gorm.DB, userID int) error {
+	return tx.Model(&entity.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *RefreshTokenRepository) DeleteExpired(tx *gorm.DB) (int64, error) {
+	result := tx.Where("expires_at <= ?", time.Now()).Delete(&entity.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
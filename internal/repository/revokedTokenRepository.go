@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedTokenRepository struct {
+	CommonQuery[entity.RevokedToken]
+	Log *logrus.Logger
+}
+
+func NewRevokedTokenRepository(db *gorm.DB, log *logrus.Logger) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		CommonQuery: CommonQuery[entity.RevokedToken]{DB: db},
+		Log:         log,
+	}
+}
+
+// Create menambahkan jti ke denylist, logout berulang dengan token yang sama diabaikan
+func (r *RevokedTokenRepository) Create(tx *gorm.DB, token *entity.RevokedToken) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// IsRevoked implements auth.TokenDenylist
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var total int64
+	if err := r.DB.WithContext(ctx).Model(&entity.RevokedToken{}).
+		Where("jti = ?", jti).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+func (r *RevokedTokenRepository) DeleteExpired(tx *gorm.DB) (int64, error) {
+	result := tx.Where("expires_at <= ?", time.Now()).Delete(&entity.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type StockMovementRepository struct {
+	CommonQuery[entity.StockMovement]
+	Log *logrus.Logger
+}
+
+func NewStockMovementRepository(db *gorm.DB, log *logrus.Logger) *StockMovementRepository {
+	return &StockMovementRepository{
+		CommonQuery: CommonQuery[entity.StockMovement]{DB: db},
+		Log:         log,
+	}
+}
+
+// BookMovementsSpec lists the movements of a book newest first
+func (r *StockMovementRepository) BookMovementsSpec(bookID int) QuerySpec {
+	var spec QuerySpec
+	spec.Where("stock_movements.book_id = ?", bookID)
+	spec.Sorts = []Sort{{Column: "created_at", Desc: true}}
+	return spec
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -31,3 +33,55 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	var total int64
+	if err := r.DB.WithContext(ctx).Model(&entity.User{}).Where("role = ?", role).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// LockActiveByRole mengunci user aktif dengan role tertentu dan mengembalikan ID-nya. Postgres
+// tidak menerima FOR UPDATE bersama COUNT, jadi baris dikunci lalu dihitung oleh pemanggil.
+func (r *UserRepository) LockActiveByRole(tx *gorm.DB, role string) ([]int, error) {
+	var ids []int
+	if err := tx.Model(&entity.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND disabled_at IS NULL", role).
+		Order("id").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IsDisabled implements auth.UserStatus. User yang sudah dihapus dianggap nonaktif.
+func (r *UserRepository) IsDisabled(ctx context.Context, userID int) (bool, error) {
+	var total int64
+	if err := r.DB.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND disabled_at IS NULL", userID).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total == 0, nil
+}
+
+func (r *UserRepository) UpdateRole(tx *gorm.DB, userID int, role string) error {
+	result := tx.Model(&entity.User{}).Where("id = ?", userID).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateDisabledAt menonaktifkan user, nil mengaktifkan kembali
+func (r *UserRepository) UpdateDisabledAt(tx *gorm.DB, userID int, disabledAt *time.Time) error {
+	return tx.Model(&entity.User{}).Where("id = ?", userID).Update("disabled_at", disabledAt).Error
+}
+
+func (r *UserRepository) UpdatePassword(tx *gorm.DB, userID int, hashedPassword string) error {
+	return tx.Model(&entity.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+}
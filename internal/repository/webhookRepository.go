@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	CommonQuery[entity.WebhookSubscription]
+	Log *logrus.Logger
+}
+
+func NewWebhookRepository(db *gorm.DB, log *logrus.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		CommonQuery: CommonQuery[entity.WebhookSubscription]{DB: db},
+		Log:         log,
+	}
+}
+
+func (r *WebhookRepository) FindAll(tx *gorm.DB) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	if err := tx.Order("id ASC").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *WebhookRepository) FindActive(tx *gorm.DB) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	if err := tx.Where("active = ?", true).Order("id ASC").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteWithDeliveries removes a subscription together with its delivery log
+func (r *WebhookRepository) DeleteWithDeliveries(tx *gorm.DB, subscription *entity.WebhookSubscription) error {
+	deliveryIDs := tx.Model(&entity.WebhookDelivery{}).Select("id").Where("subscription_id = ?", subscription.ID)
+	if err := tx.Where("delivery_id IN (?)", deliveryIDs).Delete(&entity.WebhookDeliveryAttempt{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("subscription_id = ?", subscription.ID).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(subscription).Error
+}
+
+// RecordSuccess resets the failure counter of a subscription
+func (r *WebhookRepository) RecordSuccess(tx *gorm.DB, subscriptionID int) error {
+	return tx.Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND consecutive_failures > 0", subscriptionID).
+		UpdateColumn("consecutive_failures", 0).Error
+}
+
+// RecordFailure increments the failure counter and disables the subscription once it reaches
+// disableAfter. It returns true only for the call that disabled it.
+func (r *WebhookRepository) RecordFailure(tx *gorm.DB, subscriptionID, disableAfter int) (bool, error) {
+	if err := tx.Model(&entity.WebhookSubscription{}).
+		Where("id = ?", subscriptionID).
+		UpdateColumn("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+		return false, err
+	}
+
+	result := tx.Model(&entity.WebhookSubscription{}).
+		Where("id = ? AND active = ? AND consecutive_failures >= ?", subscriptionID, true, disableAfter).
+		Updates(map[string]any{
+			"active":          false,
+			"disabled_at":     time.Now(),
+			"disabled_reason": "too many consecutive delivery failures",
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CreateDeliveries queues deliveries, an event already queued for a subscription is skipped
+// because the outbox may deliver the same event more than once
+func (r *WebhookRepository) CreateDeliveries(tx *gorm.DB, deliveries []entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// FindDueDeliveries returns pending deliveries of active subscriptions whose next attempt is due
+func (r *WebhookRepository) FindDueDeliveries(tx *gorm.DB, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	if err := tx.Joins("Subscription").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", enum.WebhookDeliveryPending, time.Now()).
+		// Alias join ditulis lewat clause supaya di-quote, Postgres membaca Subscription tanpa quote sebagai subscription
+		Where(clause.Eq{Column: clause.Column{Table: "Subscription", Name: "active"}, Value: true}).
+		Order("webhook_deliveries.id ASC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FindDelivery returns a delivery of a subscription with the subscription loaded
+func (r *WebhookRepository) FindDelivery(tx *gorm.DB, subscriptionID, deliveryID int) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	if err := tx.Joins("Subscription").
+		Where("webhook_deliveries.id = ? AND webhook_deliveries.subscription_id = ?", deliveryID, subscriptionID).
+		Take(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery stores the state of a delivery after an attempt
+func (r *WebhookRepository) UpdateDelivery(tx *gorm.DB, delivery *entity.WebhookDelivery) error {
+	return tx.Model(&entity.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
+
+func (r *WebhookRepository) CreateAttempt(tx *gorm.DB, attempt *entity.WebhookDeliveryAttempt) error {
+	return tx.Create(attempt).Error
+}
+
+// FindAttempts returns the attempts of a delivery oldest first
+func (r *WebhookRepository) FindAttempts(tx *gorm.DB, deliveryID int) ([]entity.WebhookDeliveryAttempt, error) {
+	var attempts []entity.WebhookDeliveryAttempt
+	if err := tx.Where("delivery_id = ?", deliveryID).Order("id ASC").Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// PaginateDeliveries lists the deliveries of a subscription newest first
+func (r *WebhookRepository) PaginateDeliveries(tx *gorm.DB, subscriptionID int, status string, page, size int) ([]entity.WebhookDelivery, int64, error) {
+	query := func() *gorm.DB {
+		q := tx.Model(&entity.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+		if status != "" {
+			q = q.Where("status = ?", status)
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []entity.WebhookDelivery
+	if err := query().Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "minio-access"
+	testSecretKey = "minio-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "covers"
+)
+
+// fakeS3 is a MinIO-style stand-in that keeps objects in memory and verifies every request's
+// SigV4 signature the way the server would, from the request it actually received
+type fakeS3 struct {
+	t *testing.T
+
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	paths   []string
+}
+
+type fakeObject struct {
+	body        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if err := verifySignature(r, body); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL.EscapedPath(), err)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, r.URL.EscapedPath())
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = fakeObject{body: body, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature rebuilds the canonical request from what arrived on the wire
+func verifySignature(r *http.Request, body []byte) error {
+	authorization := r.Header.Get("Authorization")
+	credential, signedHeaders, signature, ok := parseAuthorization(authorization)
+	if !ok {
+		return errors.New("malformed Authorization header: " + authorization)
+	}
+
+	amzDate := r.Header.Get("x-amz-date")
+	signedAt, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("invalid x-amz-date: " + amzDate)
+	}
+	if time.Since(signedAt) > time.Minute {
+		return errors.New("x-amz-date is too old: " + amzDate)
+	}
+	payloadHash := r.Header.Get("x-amz-content-sha256")
+	if payloadHash != sha256Hex(body) {
+		return errors.New("x-amz-content-sha256 does not match the body")
+	}
+
+	date := amzDate[:8]
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	if credential != testAccessKey+"/"+scope {
+		return errors.New("unexpected credential: " + credential)
+	}
+
+	names := strings.Split(signedHeaders, ";")
+	if !sort.StringsAreSorted(names) {
+		return errors.New("signed headers are not sorted: " + signedHeaders)
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	for _, required := range []string{"host", "x-amz-content-sha256", "x-amz-date"} {
+		if !strings.Contains(";"+signedHeaders+";", ";"+required+";") {
+			return errors.New(required + " is not signed")
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+testSecretKey), date)
+	for _, part := range []string{testRegion, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	expected := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func parseAuthorization(header string) (credential, signedHeaders, signature string, ok bool) {
+	fields, ok := strings.CutPrefix(header, "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return "", "", "", false
+	}
+	for _, field := range strings.Split(fields, ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			signedHeaders = value
+		case "Signature":
+			signature = value
+		}
+	}
+	return credential, signedHeaders, signature, credential != "" && signedHeaders != "" && signature != ""
+}
+
+func newTestS3(t *testing.T) (*S3ImageStore, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{t: t, objects: map[string]fakeObject{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	store, err := NewS3ImageStore(server.URL+"/", testRegion, testBucket, testAccessKey, testSecretKey, true)
+	if err != nil {
+		t.Fatalf("NewS3ImageStore() error = %v", err)
+	}
+	return store, fake
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	store, fake := newTestS3(t)
+	ctx := context.Background()
+
+	// Karakter reserved di key ikut di-sign dan dikirim dengan encoding yang sama
+	const key = "books/42/cover (large)+v1:final@2x!.jpg"
+	content := []byte("\xff\xd8\xff fake jpeg")
+
+	if err := store.Put(ctx, key, strings.NewReader(string(content)), int64(len(content)), "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	object, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got, _ := io.ReadAll(object.Body)
+	object.Body.Close()
+	if string(got) != string(content) || object.ContentType != "image/jpeg" || object.Size != int64(len(content)) {
+		t.Errorf("Get() = %q %s %d bytes, want the stored jpeg", got, object.ContentType, object.Size)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrObjectNotFound", err)
+	}
+	// Menghapus key yang sudah tidak ada bukan error
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("second Delete() error = %v", err)
+	}
+
+	wantPath := "/covers/books/42/cover%20%28large%29%2Bv1%3Afinal%402x%21.jpg"
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, path := range fake.paths {
+		if path != wantPath {
+			t.Errorf("request path = %s, want %s", path, wantPath)
+		}
+	}
+}
+
+func TestS3ReportsServerErrors(t *testing.T) {
+	store, _ := newTestS3(t)
+	store.Bucket = "missing"
+
+	err := store.Put(context.Background(), "a.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "status 404") || !strings.Contains(err.Error(), "NoSuchBucket") {
+		t.Errorf("Put() error = %v, want the 404 response", err)
+	}
+}
+
+func TestEncodePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/covers/a.jpg", "/covers/a.jpg"},
+		{"/covers/a-b_c~d.jpg", "/covers/a-b_c~d.jpg"},
+		{"/covers/a b+c", "/covers/a%20b%2Bc"},
+		{"/covers/:@!$&'()*,;=", "/covers/%3A%40%21%24%26%27%28%29%2A%2C%3B%3D"},
+		{"/covers/café", "/covers/caf%C3%A9"},
+	}
+	for _, tt := range tests {
+		if got := encodePath(tt.path); got != tt.want {
+			t.Errorf("encodePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectNotFound is returned by ImageStore.Get when the key does not exist
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object is a stored file returned by ImageStore.Get, the caller must close Body
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// ImageStore abstracts where book cover images are kept
+type ImageStore interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -1,38 +1,64 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"io"
 	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/imaging"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
 	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/storage"
 	"github.com/fathirarya/online-bookstore-api/internal/utils"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type BookUseCase struct {
-	DB                 *gorm.DB
-	Log                *logrus.Logger
-	BookRepository     *repository.BookRepository
-	CategoryRepository *repository.CategoryRepository
+	DB                      *gorm.DB
+	ReadReplicas            *repository.ReadReplicas // daftar dan statistik buku boleh sedikit tertinggal
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	BookRepository          *repository.BookRepository
+	CategoryRepository      *repository.CategoryRepository
+	StockMovementRepository *repository.StockMovementRepository
+	OutboxRepository        *repository.OutboxRepository
+	ImageStore              storage.ImageStore
+	ImageOptions            imaging.Options
 }
 
-func NewBookUseCase(db *gorm.DB, logger *logrus.Logger, bookRepository *repository.BookRepository,
-	categoryRepository *repository.CategoryRepository) *BookUseCase {
+func NewBookUseCase(db *gorm.DB, readReplicas *repository.ReadReplicas, logger *logrus.Logger, validate *validator.Validate, bookRepository *repository.BookRepository,
+	categoryRepository *repository.CategoryRepository, stockMovementRepository *repository.StockMovementRepository,
+	outboxRepository *repository.OutboxRepository, imageStore storage.ImageStore, imageOptions imaging.Options) *BookUseCase {
 	return &BookUseCase{
-		DB:                 db,
-		Log:                logger,
-		BookRepository:     bookRepository,
-		CategoryRepository: categoryRepository,
+		DB:                      db,
+		ReadReplicas:            readReplicas,
+		Log:                     logger,
+		Validate:                validate,
+		BookRepository:          bookRepository,
+		CategoryRepository:      categoryRepository,
+		StockMovementRepository: stockMovementRepository,
+		OutboxRepository:        outboxRepository,
+		ImageStore:              imageStore,
+		ImageOptions:            imageOptions,
 	}
 }
 
-func (uc *BookUseCase) CreateBook(ctx context.Context, req *model.CreateBookRequest, imageBase64 string) (*model.BookResponse, error) {
+func (uc *BookUseCase) CreateBook(ctx context.Context, req *model.CreateBookRequest) (*model.BookResponse, error) {
 	// Start transaction
 	tx := uc.DB.WithContext(ctx).Begin()
 	defer func() {
@@ -51,25 +77,52 @@ func (uc *BookUseCase) CreateBook(ctx context.Context, req *model.CreateBookRequ
 		return nil, fiber.NewError(fiber.StatusConflict, "book already exists")
 	}
 
+	// Simpan cover ke image store sebelum insert, dihapus lagi jika insert gagal
+	imageKey, err := uc.storeCover(ctx, req.Image)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// 3️⃣ Build entity
 	book := &entity.Book{
-		Title:       req.Title,
-		Author:      req.Author,
-		Price:       req.Price,
-		Year:        req.Year,
-		CategoryID:  req.CategoryID,
-		ImageBase64: imageBase64,
+		Title:      req.Title,
+		Author:     req.Author,
+		Price:      req.Price,
+		Year:       req.Year,
+		CategoryID: req.CategoryID,
+		ImageKey:   imageKey,
+		Stock:      req.Stock,
 	}
 
 	// Persist book
 	if err := uc.BookRepository.Create(tx, book); err != nil {
 		tx.Rollback()
+		uc.deleteCover(ctx, imageKey)
 		uc.Log.Error("failed to create book: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create book")
 	}
 
+	// Stok awal dicatat di ledger supaya saldo bisa ditelusuri dari awal
+	if book.Stock > 0 {
+		if err := recordStockMovement(tx, uc.BookRepository, uc.StockMovementRepository, book.ID, book.Stock, "initial stock", nil, nil); err != nil {
+			tx.Rollback()
+			uc.deleteCover(ctx, imageKey)
+			uc.Log.Error("failed to record stock movement: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create book")
+		}
+	}
+
+	if err := uc.OutboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventBookCreated, converter.BookToEvent(book)); err != nil {
+		tx.Rollback()
+		uc.deleteCover(ctx, imageKey)
+		uc.Log.Error("failed to record book event: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create book")
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
+		uc.deleteCover(ctx, imageKey)
 		uc.Log.Error("failed to commit transaction: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create book")
 	}
@@ -85,8 +138,9 @@ func (uc *BookUseCase) CreateBook(ctx context.Context, req *model.CreateBookRequ
 	return converter.BookToResponse(fullBook), nil
 }
 
-func (uc *BookUseCase) ListBooks(ctx context.Context, page, size int) ([]*model.BookResponse, int, int, int64, int64, error) {
+func (uc *BookUseCase) ListBooks(ctx context.Context, req *model.ListBooksRequest) ([]*model.BookResponse, int, int, int64, int64, error) {
 	// Default pagination
+	page, size := req.Page, req.Size
 	if page < 1 {
 		page = 1
 	}
@@ -94,9 +148,22 @@ func (uc *BookUseCase) ListBooks(ctx context.Context, page, size int) ([]*model.
 		size = 10
 	}
 
+	if req.MinPrice > 0 && req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
+		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, "min_price must not be greater than max_price")
+	}
+	if req.YearFrom > 0 && req.YearTo > 0 && req.YearFrom > req.YearTo {
+		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, "year_from must not be greater than year_to")
+	}
+
+	// Filter & sort dipakai untuk count dan query halaman
+	spec, err := uc.BookRepository.SearchSpec(req)
+	if err != nil {
+		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid sort, allowed: price, title, year, created_at (prefix - for descending)")
+	}
+
 	// Query books
 	var books []entity.Book
-	total, err := uc.BookRepository.Paginate(ctx, uc.DB.Preload("Category"), page, size, &books)
+	total, err := uc.BookRepository.PaginateWithSpec(ctx, uc.ReadReplicas.Reader().Preload("Category"), spec, page, size, &books)
 	if err != nil {
 		uc.Log.Error("failed to list books: ", err)
 		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list books")
@@ -111,6 +178,36 @@ func (uc *BookUseCase) ListBooks(ctx context.Context, page, size int) ([]*model.
 	return response, page, size, total, totalPages, nil
 }
 
+// ListBooksByCursor is the keyset variant of ListBooks, stable while books are being inserted
+func (uc *BookUseCase) ListBooksByCursor(ctx context.Context, req *model.ListBooksRequest) ([]*model.BookResponse, string, string, error) {
+	if req.MinPrice > 0 && req.MaxPrice > 0 && req.MinPrice > req.MaxPrice {
+		return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "min_price must not be greater than max_price")
+	}
+	if req.YearFrom > 0 && req.YearTo > 0 && req.YearFrom > req.YearTo {
+		return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "year_from must not be greater than year_to")
+	}
+
+	spec, err := uc.BookRepository.SearchSpec(req)
+	if err != nil {
+		return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "invalid sort, allowed: price, title, year, created_at (prefix - for descending)")
+	}
+
+	var books []entity.Book
+	next, prev, err := uc.BookRepository.PaginateByCursor(ctx, uc.ReadReplicas.Reader().Preload("Category"), spec, req.Cursor, req.Limit, &books)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "cursor pagination supports a single sort key")
+		}
+		uc.Log.Error("failed to list books: ", err)
+		return nil, "", "", fiber.NewError(fiber.StatusInternalServerError, "failed to list books")
+	}
+
+	return converter.BooksToResponse(books), next, prev, nil
+}
+
 func (uc *BookUseCase) GetBookByID(ctx context.Context, id int) (*model.BookResponse, error) {
 	var book entity.Book
 	if err := uc.BookRepository.FindById(uc.DB.WithContext(ctx), &book, id); err != nil {
@@ -124,7 +221,7 @@ func (uc *BookUseCase) GetBookByID(ctx context.Context, id int) (*model.BookResp
 	return converter.BookToResponse(&book), nil
 }
 
-func (uc *BookUseCase) UpdateBook(ctx context.Context, id int, req *model.UpdateBookRequest, file multipart.File) (*model.BookResponse, error) {
+func (uc *BookUseCase) UpdateBook(ctx context.Context, id int, req *model.UpdateBookRequest) (*model.BookResponse, error) {
 	// Start transaction
 	tx := uc.DB.WithContext(ctx).Begin()
 	defer func() {
@@ -172,28 +269,50 @@ func (uc *BookUseCase) UpdateBook(ctx context.Context, id int, req *model.Update
 	}
 
 	// Update Image jika ada file baru
-	if file != nil {
-		imageBase64, err := utils.FileToBase64(file)
+	oldImageKey := book.ImageKey
+	if req.Image != nil {
+		imageKey, err := uc.storeCover(ctx, req.Image)
 		if err != nil {
 			tx.Rollback()
-			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to process image")
+			return nil, err
 		}
-		book.ImageBase64 = imageBase64
+		book.ImageKey = imageKey
 	}
 
 	// Simpan perubahan
-	if err := uc.BookRepository.Update(tx, &book); err != nil {
+	// Stok hanya berubah lewat order dan adjustment, jangan ditimpa nilai lama
+	if err := uc.BookRepository.Update(tx.Omit("stock"), &book); err != nil {
 		tx.Rollback()
+		if book.ImageKey != oldImageKey {
+			uc.deleteCover(ctx, book.ImageKey)
+		}
 		uc.Log.Error("failed to update book: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update book")
 	}
 
+	if err := uc.OutboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventBookUpdated, converter.BookToEvent(&book)); err != nil {
+		tx.Rollback()
+		if book.ImageKey != oldImageKey {
+			uc.deleteCover(ctx, book.ImageKey)
+		}
+		uc.Log.Error("failed to record book event: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update book")
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
+		if book.ImageKey != oldImageKey {
+			uc.deleteCover(ctx, book.ImageKey)
+		}
 		uc.Log.Error("failed to commit transaction: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update book")
 	}
 
+	// Cover lama baru dihapus setelah commit berhasil
+	if book.ImageKey != oldImageKey {
+		uc.deleteCover(ctx, oldImageKey)
+	}
+
 	// Ambil lagi dengan preload Category agar response lengkap
 	updatedBook, err := uc.BookRepository.FindByID(ctx, uc.DB, book.ID)
 	if err != nil {
@@ -231,12 +350,20 @@ func (uc *BookUseCase) DeleteBook(ctx context.Context, id int) error {
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete book")
 	}
 
+	if err := uc.OutboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventBookDeleted, converter.BookToEvent(book)); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to record book event: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete book")
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		uc.Log.Error("failed to commit transaction: ", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete book")
 	}
 
+	uc.deleteCover(ctx, book.ImageKey)
+
 	return nil
 }
 
@@ -271,12 +398,335 @@ func (uc *BookUseCase) GetTotalBooks(ctx context.Context) (*model.BookStatsRespo
 
 func (uc *BookUseCase) GetBookPriceStats(ctx context.Context) (*model.BookPriceStatsResponse, error) {
 	// 1️⃣ Ambil statistik harga dari repository
-	stats, err := uc.BookRepository.GetPriceStats(ctx, uc.DB)
+	stats, err := uc.BookRepository.GetPriceStats(ctx, uc.ReadReplicas.Reader())
 	if err != nil {
 		uc.Log.Error("failed to get book price stats: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to get book price stats")
 	}
 
 	// 2️⃣ Return response
+	stats.Currency = money.Currency()
 	return stats, nil
 }
+
+// GetBookCover opens the stored cover image of a book, the caller must close the body.
+// size is empty for the original upload or one of the thumbnail variant names. The book is looked
+// up with ctx, the object is opened with bodyCtx because its body may be read after ctx has ended.
+func (uc *BookUseCase) GetBookCover(ctx, bodyCtx context.Context, id int, size string) (*storage.Object, error) {
+	if size != "" && size != "original" && !imaging.IsVariant(size) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid cover size")
+	}
+
+	var book entity.Book
+	if err := uc.BookRepository.FindById(uc.DB.WithContext(ctx), &book, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "book not found")
+		}
+		uc.Log.Error("failed to get book: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to get book")
+	}
+	if book.ImageKey == "" {
+		return nil, fiber.NewError(fiber.StatusNotFound, "book has no cover")
+	}
+
+	key := book.ImageKey
+	if imaging.IsVariant(size) {
+		key = imaging.VariantKey(book.ImageKey, size)
+	}
+
+	object, err := uc.ImageStore.Get(bodyCtx, key)
+	// Cover hasil migrasi lama belum punya thumbnail, fallback ke original
+	if errors.Is(err, storage.ErrObjectNotFound) && key != book.ImageKey {
+		object, err = uc.ImageStore.Get(bodyCtx, book.ImageKey)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "book cover not found")
+		}
+		uc.Log.Error("failed to get book cover: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to get book cover")
+	}
+
+	return object, nil
+}
+
+// storeCover memvalidasi cover, membuat thumbnail, lalu menyimpan semuanya ke image store.
+// Yang dikembalikan adalah key file original; key thumbnail diturunkan dari key tersebut.
+func (uc *BookUseCase) storeCover(ctx context.Context, fileHeader *multipart.FileHeader) (string, error) {
+	if fileHeader == nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "image file is required")
+	}
+	if uc.ImageOptions.MaxBytes > 0 && fileHeader.Size > uc.ImageOptions.MaxBytes {
+		return "", uc.imageTooLargeError()
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "failed to open image file")
+	}
+	defer file.Close()
+
+	// Baca maksimal MaxBytes+1 supaya file yang melebihi batas tetap terdeteksi
+	reader := io.Reader(file)
+	if uc.ImageOptions.MaxBytes > 0 {
+		reader = io.LimitReader(file, uc.ImageOptions.MaxBytes+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusInternalServerError, "failed to process image")
+	}
+
+	cover, err := imaging.ProcessCover(data, uc.ImageOptions)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrTooLarge):
+			return "", uc.imageTooLargeError()
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return "", fiber.NewError(fiber.StatusUnsupportedMediaType, "image must be a JPEG, PNG or WebP file")
+		case errors.Is(err, imaging.ErrInvalidDimensions):
+			return "", fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("image width and height must be between %d and %d pixels",
+				uc.ImageOptions.MinDimension, uc.ImageOptions.MaxDimension))
+		}
+		uc.Log.Error("failed to process book cover: ", err)
+		return "", fiber.NewError(fiber.StatusInternalServerError, "failed to process image")
+	}
+
+	key := "covers/" + uuid.NewString() + "/original" + utils.ImageExtension(cover.Original.ContentType)
+	if err := uc.ImageStore.Put(ctx, key, bytes.NewReader(cover.Original.Data), int64(len(cover.Original.Data)), cover.Original.ContentType); err != nil {
+		uc.Log.Error("failed to store book cover: ", err)
+		return "", fiber.NewError(fiber.StatusInternalServerError, "failed to store image")
+	}
+
+	for name, thumbnail := range cover.Thumbnails {
+		if err := uc.ImageStore.Put(ctx, imaging.VariantKey(key, name), bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), thumbnail.ContentType); err != nil {
+			uc.Log.Error("failed to store book cover thumbnail: ", err)
+			uc.deleteCover(ctx, key)
+			return "", fiber.NewError(fiber.StatusInternalServerError, "failed to store image")
+		}
+	}
+
+	return key, nil
+}
+
+func (uc *BookUseCase) imageTooLargeError() error {
+	return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+		fmt.Sprintf("image must not exceed %d MB", uc.ImageOptions.MaxBytes/(1024*1024)))
+}
+
+// deleteCover menghapus cover beserta thumbnail dari image store, kegagalan hanya di-log
+func (uc *BookUseCase) deleteCover(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	keys := []string{key}
+	for _, variant := range imaging.Variants {
+		keys = append(keys, imaging.VariantKey(key, variant.Name))
+	}
+	for _, k := range keys {
+		if err := uc.ImageStore.Delete(ctx, k); err != nil {
+			uc.Log.Warnf("failed to delete book cover %s: %v", k, err)
+		}
+	}
+}
+
+var catalogCSVHeader = []string{"title", "author", "price", "year", "category", "stock"}
+
+// ExportCatalog writes every book as CSV in the format read by ImportCatalog
+func (uc *BookUseCase) ExportCatalog(ctx context.Context, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+
+	err := uc.BookRepository.FindInBatches(uc.DB.WithContext(ctx), 500, func(books []entity.Book) error {
+		for _, book := range books {
+			if err := writer.Write([]string{
+				csvSafe(book.Title),
+				csvSafe(book.Author),
+				book.Price.String(),
+				strconv.Itoa(book.Year),
+				csvSafe(book.Category.Name),
+				strconv.Itoa(book.Stock),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		uc.Log.Error("failed to export catalog: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to export catalog")
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ImportCatalog creates or updates books from a catalog CSV in a single transaction. Every row
+// is validated first and nothing is saved when one of them is invalid. Stock is only used for
+// new books, stock of existing books changes through orders and stock adjustments.
+func (uc *BookUseCase) ImportCatalog(ctx context.Context, r io.Reader, dryRun bool) (*model.CatalogImportResponse, error) {
+	rows, errs, err := uc.parseCatalog(r)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.CatalogImportResponse{DryRun: dryRun, Errors: errs}
+	if len(errs) > 0 {
+		return result, nil
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	categories := make(map[string]int)
+	for _, row := range rows {
+		categoryID, ok := categories[row.Category]
+		if !ok {
+			var category entity.Category
+			err := tx.Where("name = ?", row.Category).First(&category).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				category = entity.Category{Name: row.Category}
+				err = tx.Create(&category).Error
+				result.CategoriesCreated++
+			}
+			if err != nil {
+				tx.Rollback()
+				uc.Log.Error("failed to import category: ", err)
+				return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to import catalog")
+			}
+			categoryID = category.ID
+			categories[row.Category] = categoryID
+		}
+
+		var book entity.Book
+		err := tx.Where("title = ?", row.Title).First(&book).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			book = entity.Book{Title: row.Title, Author: row.Author, Price: row.Price, Year: row.Year, CategoryID: categoryID, Stock: row.Stock}
+			if err = uc.BookRepository.Create(tx, &book); err == nil && book.Stock > 0 {
+				err = recordStockMovement(tx, uc.BookRepository, uc.StockMovementRepository, book.ID, book.Stock, "initial stock", nil, nil)
+			}
+			if err == nil {
+				err = uc.OutboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventBookCreated, converter.BookToEvent(&book))
+			}
+			result.Created++
+		case err != nil:
+		case book.Author == row.Author && book.Price == row.Price && book.Year == row.Year && book.CategoryID == categoryID:
+			result.Unchanged++
+		default:
+			book.Author, book.Price, book.Year, book.CategoryID = row.Author, row.Price, row.Year, categoryID
+			if err = uc.BookRepository.Update(tx.Omit("stock"), &book); err == nil {
+				err = uc.OutboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventBookUpdated, converter.BookToEvent(&book))
+			}
+			result.Updated++
+		}
+		if err != nil {
+			tx.Rollback()
+			uc.Log.Error("failed to import book: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to import catalog")
+		}
+	}
+
+	// Dry run menjalankan semua perubahan lalu membatalkannya, jadi jumlahnya sama persis
+	if dryRun {
+		tx.Rollback()
+		return result, nil
+	}
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to import catalog")
+	}
+	return result, nil
+}
+
+// parseCatalog membaca dan memvalidasi semua baris, kolom dicocokkan lewat nama di header
+func (uc *BookUseCase) parseCatalog(r io.Reader) ([]model.CatalogBookRequest, []model.CatalogImportError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "catalog must start with a header row")
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"title", "author", "price", "category"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fiber.NewError(fiber.StatusBadRequest, "catalog header is missing column "+name)
+		}
+	}
+
+	var rows []model.CatalogBookRequest
+	var errs []model.CatalogImportError
+	titles := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			errs = append(errs, model.CatalogImportError{Line: parseErr.StartLine, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fiber.NewError(fiber.StatusBadRequest, "failed to read catalog")
+		}
+		line, _ := reader.FieldPos(0)
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return csvUnsafe(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+		row := model.CatalogBookRequest{
+			Title:    field("title"),
+			Author:   field("author"),
+			Category: field("category"),
+		}
+
+		var problems []string
+		if row.Price, err = money.Parse(field("price")); err != nil {
+			problems = append(problems, "invalid price")
+		}
+		for name, target := range map[string]*int{"year": &row.Year, "stock": &row.Stock} {
+			if value := field(name); value != "" {
+				if *target, err = strconv.Atoi(value); err != nil {
+					problems = append(problems, "invalid "+name)
+				}
+			}
+		}
+		if err := uc.Validate.Struct(&row); err != nil {
+			for _, message := range utils.TranslateValidationErrors(err) {
+				problems = append(problems, message)
+			}
+		}
+		if first, ok := titles[row.Title]; ok && row.Title != "" {
+			problems = append(problems, fmt.Sprintf("duplicate title, first seen on line %d", first))
+		}
+		titles[row.Title] = line
+
+		if len(problems) > 0 {
+			sort.Strings(problems)
+			errs = append(errs, model.CatalogImportError{Line: line, Message: strings.Join(problems, "; ")})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, errs, nil
+}
+
+// csvUnsafe membuang prefix ' yang ditambahkan csvSafe saat export
+func csvUnsafe(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CartUseCase struct {
+	DB             *gorm.DB
+	Log            *logrus.Logger
+	Validate       *validator.Validate
+	CartRepository *repository.CartRepository
+	BookRepository *repository.BookRepository
+	OrderUseCase   *OrderUseCase
+}
+
+func NewCartUseCase(db *gorm.DB, logger *logrus.Logger, validate *validator.Validate,
+	cartRepository *repository.CartRepository, bookRepository *repository.BookRepository, orderUseCase *OrderUseCase) *CartUseCase {
+	return &CartUseCase{
+		DB:             db,
+		Log:            logger,
+		Validate:       validate,
+		CartRepository: cartRepository,
+		BookRepository: bookRepository,
+		OrderUseCase:   orderUseCase,
+	}
+}
+
+func (uc *CartUseCase) GetCart(ctx context.Context, userID int) (*model.CartResponse, error) {
+	cart, err := uc.CartRepository.FindByUserIDWithItems(uc.DB.WithContext(ctx), userID)
+	if err != nil {
+		// User yang belum pernah menambah item belum punya cart
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return converter.CartToResponse(&entity.Cart{UserID: userID}), nil
+		}
+		uc.Log.Error("failed to fetch cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch cart")
+	}
+	return converter.CartToResponse(cart), nil
+}
+
+func (uc *CartUseCase) AddItem(ctx context.Context, userID int, req *model.AddCartItemRequest) (*model.CartResponse, error) {
+	if err := uc.Validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "validation failed, please check your input")
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var book entity.Book
+	if err := uc.BookRepository.FindById(tx, &book, req.BookID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("book not found: %d", req.BookID))
+		}
+		uc.Log.Error("failed to fetch book: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	if _, err := uc.CartRepository.FindOrCreateByUserID(tx, userID); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to fetch cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+	// Cart dikunci supaya dua request paralel tidak sama-sama lolos validasi total quantity
+	cart, err := uc.CartRepository.LockByUserID(tx, userID)
+	if err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to lock cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+
+	quantity := req.Quantity
+	for _, item := range cart.Items {
+		if item.BookID == book.ID {
+			quantity += item.Quantity
+		}
+	}
+	// Batas per judul dan stok dicek terhadap total di cart, bukan hanya quantity yang ditambahkan
+	if limit := uc.OrderUseCase.Rules.MaxQuantityFor(book.CategoryID); quantity > limit {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("maximum %d copies of book %d per transaction", limit, book.ID))
+	}
+	if quantity > book.Stock {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("insufficient stock, current stock is %d", book.Stock))
+	}
+
+	if err := uc.CartRepository.SaveItem(tx, cart.ID, book.ID, quantity); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to add cart item: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+
+	return uc.GetCart(ctx, userID)
+}
+
+func (uc *CartUseCase) UpdateItem(ctx context.Context, userID, bookID int, req *model.UpdateCartItemRequest) (*model.CartResponse, error) {
+	if err := uc.Validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "validation failed, please check your input")
+	}
+
+	db := uc.DB.WithContext(ctx)
+
+	var book entity.Book
+	if err := uc.BookRepository.FindById(db, &book, bookID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("book not found: %d", bookID))
+		}
+		uc.Log.Error("failed to fetch book: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+	if limit := uc.OrderUseCase.Rules.MaxQuantityFor(book.CategoryID); req.Quantity > limit {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("maximum %d copies of book %d per transaction", limit, book.ID))
+	}
+	if req.Quantity > book.Stock {
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("insufficient stock, current stock is %d", book.Stock))
+	}
+
+	cart, err := uc.findCart(db, userID, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.CartRepository.UpdateItemQuantity(db, cart.ID, bookID, req.Quantity)
+	if err != nil {
+		uc.Log.Error("failed to update cart item: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+	if !updated {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("book %d is not in the cart", bookID))
+	}
+
+	return uc.GetCart(ctx, userID)
+}
+
+func (uc *CartUseCase) RemoveItem(ctx context.Context, userID, bookID int) (*model.CartResponse, error) {
+	db := uc.DB.WithContext(ctx)
+	cart, err := uc.findCart(db, userID, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := uc.CartRepository.RemoveItem(db, cart.ID, bookID)
+	if err != nil {
+		uc.Log.Error("failed to remove cart item: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+	if !removed {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("book %d is not in the cart", bookID))
+	}
+
+	return uc.GetCart(ctx, userID)
+}
+
+// findCart tidak membuat cart baru, user tanpa cart berarti bukunya memang tidak ada di cart
+func (uc *CartUseCase) findCart(db *gorm.DB, userID, bookID int) (*entity.Cart, error) {
+	cart, err := uc.CartRepository.FindByUserID(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("book %d is not in the cart", bookID))
+		}
+		uc.Log.Error("failed to fetch cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update cart")
+	}
+	return cart, nil
+}
+
+// Checkout turns the cart into an order with the same rules as CreateOrder and empties
+// the cart in the same transaction
+func (uc *CartUseCase) Checkout(ctx context.Context, userID int, req *model.CheckoutCartRequest) (*model.OrderResponse, error) {
+	if err := uc.Validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "validation failed, please check your input")
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	cart, err := uc.CartRepository.LockByUserID(tx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		uc.Log.Error("failed to fetch cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to checkout")
+	}
+	if cart == nil || len(cart.Items) == 0 {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusBadRequest, "cart is empty")
+	}
+
+	items := make([]model.OrderItemInput, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, model.OrderItemInput{
+			BookID:   item.BookID,
+			Quantity: item.Quantity,
+		})
+	}
+
+	order, err := uc.OrderUseCase.placeOrder(tx, userID, items, req.CouponCode)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := uc.CartRepository.ClearItems(tx, cart.ID); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to clear cart: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to checkout")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to checkout")
+	}
+
+	fullOrder, err := uc.OrderUseCase.OrderRepository.FindByID(uc.DB, order.ID)
+	if err != nil {
+		uc.Log.Error("failed to fetch full order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch order")
+	}
+
+	return converter.OrderToResponse(fullOrder), nil
+}
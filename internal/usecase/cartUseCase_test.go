@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAddItemChecksTheAccumulatedQuantity(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	log := testutil.NewLogger()
+	uc := NewCartUseCase(db, log, validator.New(), repository.NewCartRepository(db, log),
+		repository.NewBookRepository(db, log), newTestOrderUseCase(db))
+	userID, bookID := createTestBook(t, db, money.FromMinor(1000), 6)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		quantity int
+		wantCode int
+		wantCart int
+	}{
+		{"first add", 3, 0, 3},
+		{"within the limit", 2, 0, 5},
+		{"over the per book limit", 1, fiber.StatusBadRequest, 5},
+	}
+	for _, tt := range tests {
+		cart, err := uc.AddItem(ctx, userID, &model.AddCartItemRequest{BookID: bookID, Quantity: tt.quantity})
+		if tt.wantCode != 0 {
+			if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != tt.wantCode {
+				t.Fatalf("%s: AddItem() error = %v, want %d", tt.name, err, tt.wantCode)
+			}
+			cart, err = uc.GetCart(ctx, userID)
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(cart.Items) != 1 || cart.Items[0].Quantity != tt.wantCart {
+			t.Fatalf("%s: cart items = %+v, want quantity %d", tt.name, cart.Items, tt.wantCart)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
@@ -14,13 +15,15 @@ import (
 
 type CategoryUseCase struct {
 	DB                 *gorm.DB
+	ReadReplicas       *repository.ReadReplicas
 	Log                *logrus.Logger
 	CategoryRepository *repository.CategoryRepository
 }
 
-func NewCategoryUseCase(db *gorm.DB, logger *logrus.Logger, categoryRepository *repository.CategoryRepository) *CategoryUseCase {
+func NewCategoryUseCase(db *gorm.DB, readReplicas *repository.ReadReplicas, logger *logrus.Logger, categoryRepository *repository.CategoryRepository) *CategoryUseCase {
 	return &CategoryUseCase{
 		DB:                 db,
+		ReadReplicas:       readReplicas,
 		Log:                logger,
 		CategoryRepository: categoryRepository,
 	}
@@ -66,18 +69,24 @@ func (uc *CategoryUseCase) CreateCategory(ctx context.Context, req *model.Create
 }
 
 // ListCategories returns a paginated list of categories
-func (uc *CategoryUseCase) ListCategories(ctx context.Context, page, size int) ([]*model.CategoryResponse, int64, int64, error) {
+func (uc *CategoryUseCase) ListCategories(ctx context.Context, req *model.ListCategoriesRequest) ([]*model.CategoryResponse, int64, int64, error) {
 	// Ensure default pagination values
-	if page < 1 {
-		page = 1
+	if req.Page < 1 {
+		req.Page = 1
 	}
-	if size < 1 {
-		size = 10
+	if req.Size < 1 {
+		req.Size = 10
+	}
+	size := req.Size
+
+	spec, err := uc.CategoryRepository.SearchSpec(req)
+	if err != nil {
+		return nil, 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid sort, allowed: name, created_at (prefix - for descending)")
 	}
 
 	// Fetch categories with pagination
 	var categories []entity.Category
-	total, err := uc.CategoryRepository.Paginate(ctx, uc.DB, page, size, &categories)
+	total, err := uc.CategoryRepository.PaginateWithSpec(ctx, uc.ReadReplicas.Reader(), spec, req.Page, req.Size, &categories)
 	if err != nil {
 		uc.Log.Error("failed to list categories: ", err)
 		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list categories")
@@ -93,6 +102,29 @@ func (uc *CategoryUseCase) ListCategories(ctx context.Context, page, size int) (
 	return response, total, totalPages, nil
 }
 
+// ListCategoriesByCursor is the keyset variant of ListCategories
+func (uc *CategoryUseCase) ListCategoriesByCursor(ctx context.Context, req *model.ListCategoriesRequest) ([]*model.CategoryResponse, string, string, error) {
+	spec, err := uc.CategoryRepository.SearchSpec(req)
+	if err != nil {
+		return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "invalid sort, allowed: name, created_at (prefix - for descending)")
+	}
+
+	var categories []entity.Category
+	next, prev, err := uc.CategoryRepository.PaginateByCursor(ctx, uc.ReadReplicas.Reader(), spec, req.Cursor, req.Limit, &categories)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "cursor pagination supports a single sort key")
+		}
+		uc.Log.Error("failed to list categories: ", err)
+		return nil, "", "", fiber.NewError(fiber.StatusInternalServerError, "failed to list categories")
+	}
+
+	return converter.CategoriesToResponse(categories), next, prev, nil
+}
+
 func (uc *CategoryUseCase) UpdateCategory(ctx context.Context, id int, req *model.UpdateCategoryRequest) (*model.CategoryResponse, error) {
 	//  Start transaction
 	tx := uc.DB.WithContext(ctx).Begin()
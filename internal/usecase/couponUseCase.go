@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type CouponUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	CouponRepository   *repository.CouponRepository
+	BookRepository     *repository.BookRepository
+	CategoryRepository *repository.CategoryRepository
+}
+
+func NewCouponUseCase(db *gorm.DB, logger *logrus.Logger, couponRepository *repository.CouponRepository,
+	bookRepository *repository.BookRepository, categoryRepository *repository.CategoryRepository) *CouponUseCase {
+	return &CouponUseCase{
+		DB:                 db,
+		Log:                logger,
+		CouponRepository:   couponRepository,
+		BookRepository:     bookRepository,
+		CategoryRepository: categoryRepository,
+	}
+}
+
+func (uc *CouponUseCase) CreateCoupon(ctx context.Context, req *model.CreateCouponRequest) (*model.CouponResponse, error) {
+	db := uc.DB.WithContext(ctx)
+
+	coupon := &entity.Coupon{}
+	if err := uc.fillCoupon(db, coupon, req); err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.CouponRepository.FindByCode(db, coupon.Code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		uc.Log.Error("failed to check existing coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create coupon")
+	}
+	if existing != nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "coupon code already exists")
+	}
+
+	if err := uc.CouponRepository.Create(db, coupon); err != nil {
+		uc.Log.Error("failed to create coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create coupon")
+	}
+
+	return converter.CouponToResponse(coupon), nil
+}
+
+func (uc *CouponUseCase) ListCoupons(ctx context.Context, req *model.ListCouponsRequest) ([]*model.CouponResponse, int64, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 {
+		req.Size = 10
+	}
+
+	spec, err := uc.CouponRepository.SearchSpec(req)
+	if err != nil {
+		return nil, 0, 0, fiber.NewError(fiber.StatusBadRequest, "invalid sort, allowed: code, created_at, ends_at (prefix - for descending)")
+	}
+
+	var coupons []entity.Coupon
+	total, err := uc.CouponRepository.PaginateWithSpec(ctx, uc.DB, spec, req.Page, req.Size, &coupons)
+	if err != nil {
+		uc.Log.Error("failed to list coupons: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list coupons")
+	}
+
+	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
+	return converter.CouponsToResponse(coupons), total, totalPages, nil
+}
+
+func (uc *CouponUseCase) GetCoupon(ctx context.Context, id int) (*model.CouponResponse, error) {
+	var coupon entity.Coupon
+	if err := uc.CouponRepository.FindById(uc.DB.WithContext(ctx), &coupon, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "coupon not found")
+		}
+		uc.Log.Error("failed to fetch coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch coupon")
+	}
+	return converter.CouponToResponse(&coupon), nil
+}
+
+func (uc *CouponUseCase) UpdateCoupon(ctx context.Context, id int, req *model.UpdateCouponRequest) (*model.CouponResponse, error) {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var coupon entity.Coupon
+	if err := uc.CouponRepository.FindById(tx, &coupon, id); err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "coupon not found")
+		}
+		uc.Log.Error("failed to fetch coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch coupon")
+	}
+
+	if err := uc.fillCoupon(tx, &coupon, req); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	existing, err := uc.CouponRepository.FindByCode(tx, coupon.Code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		uc.Log.Error("failed to check existing coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update coupon")
+	}
+	if existing != nil && existing.ID != coupon.ID {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusConflict, "coupon code already exists")
+	}
+
+	// used_count hanya diubah oleh checkout
+	if err := uc.CouponRepository.Update(tx.Omit("used_count"), &coupon); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to update coupon: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update coupon")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update coupon")
+	}
+
+	return converter.CouponToResponse(&coupon), nil
+}
+
+func (uc *CouponUseCase) DeleteCoupon(ctx context.Context, id int) error {
+	db := uc.DB.WithContext(ctx)
+
+	var coupon entity.Coupon
+	if err := uc.CouponRepository.FindById(db, &coupon, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "coupon not found")
+		}
+		uc.Log.Error("failed to fetch coupon: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to fetch coupon")
+	}
+
+	// Kupon yang sudah dipakai tetap disimpan untuk histori order
+	if coupon.UsedCount > 0 {
+		return fiber.NewError(fiber.StatusConflict, "coupon has been used, deactivate it instead")
+	}
+
+	if err := uc.CouponRepository.Delete(db, &coupon); err != nil {
+		uc.Log.Error("failed to delete coupon: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete coupon")
+	}
+	return nil
+}
+
+// fillCoupon validates the rules that the struct tags can't express and copies the request to coupon
+func (uc *CouponUseCase) fillCoupon(db *gorm.DB, coupon *entity.Coupon, req *model.CreateCouponRequest) error {
+	if req.Type == enum.CouponPercentage && req.Value > money.FromMinor(100_00) {
+		return fiber.NewError(fiber.StatusBadRequest, "percentage value must not be greater than 100")
+	}
+	if req.CategoryID != nil && req.BookID != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "coupon can be scoped to a category or a book, not both")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return fiber.NewError(fiber.StatusBadRequest, "ends_at must be after starts_at")
+	}
+
+	if req.CategoryID != nil {
+		var category entity.Category
+		if err := uc.CategoryRepository.FindById(db, &category, *req.CategoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("category not found: %d", *req.CategoryID))
+			}
+			uc.Log.Error("failed to fetch category: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+	}
+	if req.BookID != nil {
+		var book entity.Book
+		if err := uc.BookRepository.FindById(db, &book, *req.BookID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("book not found: %d", *req.BookID))
+			}
+			uc.Log.Error("failed to fetch book: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	coupon.Code = normalizeCouponCode(req.Code)
+	coupon.Type = req.Type
+	coupon.Value = req.Value
+	coupon.CategoryID = req.CategoryID
+	coupon.BookID = req.BookID
+	coupon.MinOrderValue = req.MinOrderValue
+	coupon.MaxDiscount = req.MaxDiscount
+	coupon.StartsAt = req.StartsAt
+	coupon.EndsAt = req.EndsAt
+	coupon.UsageLimit = req.UsageLimit
+	coupon.PerUserLimit = req.PerUserLimit
+	coupon.Active = req.Active == nil || *req.Active
+	return nil
+}
+
+// Kode kupon tidak case sensitive
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// redeemCoupon validates the coupon against the order lines, counts its usage atomically and
+// returns the coupon with the discount. Only one coupon is allowed per order (no stacking).
+// lines must have Book loaded. Runs inside the checkout transaction.
+func redeemCoupon(tx *gorm.DB, couponRepository *repository.CouponRepository, code string, userID int,
+	lines []entity.BookOrder, subtotal money.Amount) (*entity.Coupon, money.Amount, error) {
+	coupon, err := couponRepository.FindByCodeForUpdate(tx, normalizeCouponCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, fiber.NewError(fiber.StatusBadRequest, "coupon is not valid")
+		}
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	if !coupon.Active || (coupon.StartsAt != nil && now.Before(*coupon.StartsAt)) || (coupon.EndsAt != nil && !now.Before(*coupon.EndsAt)) {
+		return nil, 0, fiber.NewError(fiber.StatusBadRequest, "coupon is not valid")
+	}
+	if subtotal < coupon.MinOrderValue {
+		return nil, 0, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("minimum order value for this coupon is %s", coupon.MinOrderValue))
+	}
+
+	// Diskon hanya dihitung dari item yang masuk scope kupon
+	var eligible money.Amount
+	for _, line := range lines {
+		if coupon.BookID != nil && line.BookID != *coupon.BookID {
+			continue
+		}
+		if coupon.CategoryID != nil && line.Book.CategoryID != *coupon.CategoryID {
+			continue
+		}
+		eligible += line.Book.Price.Mul(line.Quantity)
+	}
+	if eligible == 0 {
+		return nil, 0, fiber.NewError(fiber.StatusBadRequest, "coupon does not apply to any item in this order")
+	}
+
+	// Persen dibulatkan half away from zero ke 2 desimal
+	var discount money.Amount
+	switch coupon.Type {
+	case enum.CouponPercentage:
+		discount = eligible.Percent(coupon.Value)
+		if coupon.MaxDiscount != nil && discount > *coupon.MaxDiscount {
+			discount = *coupon.MaxDiscount
+		}
+	case enum.CouponFixed:
+		discount = money.Min(coupon.Value, eligible)
+	}
+
+	if coupon.PerUserLimit != nil {
+		used, err := couponRepository.CountUserRedemptions(tx, coupon.ID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= int64(*coupon.PerUserLimit) {
+			return nil, 0, fiber.NewError(fiber.StatusConflict, "you have reached the usage limit of this coupon")
+		}
+	}
+
+	incremented, err := couponRepository.IncrementUsage(tx, coupon.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !incremented {
+		return nil, 0, fiber.NewError(fiber.StatusConflict, "coupon usage limit has been reached")
+	}
+
+	return coupon, discount, nil
+}
+
+// releaseOrderCoupon mengembalikan kuota kupon dari order yang dibatalkan
+func releaseOrderCoupon(tx *gorm.DB, couponRepository *repository.CouponRepository, orderID int) error {
+	redemption, err := couponRepository.DeleteRedemptionByOrderID(tx, orderID)
+	if err != nil || redemption == nil {
+		return err
+	}
+	return couponRepository.DecrementUsage(tx, redemption.CouponID)
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type EventUseCase struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	OutboxRepository *repository.OutboxRepository
+}
+
+func NewEventUseCase(db *gorm.DB, logger *logrus.Logger, outboxRepository *repository.OutboxRepository) *EventUseCase {
+	return &EventUseCase{
+		DB:               db,
+		Log:              logger,
+		OutboxRepository: outboxRepository,
+	}
+}
+
+func (uc *EventUseCase) ListEvents(ctx context.Context, req *model.ListEventsRequest) ([]*model.OutboxEventResponse, int64, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 {
+		req.Size = 20
+	}
+
+	var events []entity.OutboxEvent
+	total, err := uc.OutboxRepository.PaginateWithSpec(ctx, uc.DB, uc.OutboxRepository.SearchSpec(req), req.Page, req.Size, &events)
+	if err != nil {
+		uc.Log.Error("failed to list outbox events: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list events")
+	}
+
+	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
+	return converter.OutboxEventsToResponse(events), total, totalPages, nil
+}
+
+// ReplayEvents requeues the events of a time range, the dispatcher delivers them again in order
+func (uc *EventUseCase) ReplayEvents(ctx context.Context, req *model.ReplayEventsRequest) (*model.ReplayEventsResponse, error) {
+	requeued, err := uc.OutboxRepository.Requeue(uc.DB.WithContext(ctx), req.From, req.To, req.Type)
+	if err != nil {
+		uc.Log.Error("failed to requeue outbox events: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to replay events")
+	}
+
+	uc.Log.Infof("%d outbox events requeued for replay (%s - %s)", requeued, req.From, req.To)
+	return &model.ReplayEventsResponse{Requeued: requeued}, nil
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/jobs"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobUseCase struct {
+	DB            *gorm.DB
+	Log           *logrus.Logger
+	Scheduler     *jobs.Scheduler
+	JobRepository *repository.JobRepository
+}
+
+func NewJobUseCase(db *gorm.DB, logger *logrus.Logger, scheduler *jobs.Scheduler, jobRepository *repository.JobRepository) *JobUseCase {
+	return &JobUseCase{
+		DB:            db,
+		Log:           logger,
+		Scheduler:     scheduler,
+		JobRepository: jobRepository,
+	}
+}
+
+// ListJobs returns every registered job with its most recent run
+func (uc *JobUseCase) ListJobs(ctx context.Context) ([]*model.JobResponse, error) {
+	db := uc.DB.WithContext(ctx)
+
+	infos := uc.Scheduler.Jobs()
+	responses := make([]*model.JobResponse, 0, len(infos))
+	for _, info := range infos {
+		lastRun, err := uc.JobRepository.FindLastRun(db, info.Name)
+		if err != nil {
+			uc.Log.Error("failed to fetch last job run: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to list jobs")
+		}
+		responses = append(responses, jobToResponse(info, lastRun))
+	}
+	return responses, nil
+}
+
+func (uc *JobUseCase) ListRuns(ctx context.Context, name string, req *model.ListJobRunsRequest) ([]*model.JobRunResponse, int64, int64, error) {
+	if _, ok := uc.Scheduler.Lookup(name); !ok {
+		return nil, 0, 0, fiber.NewError(fiber.StatusNotFound, "job not found")
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 {
+		req.Size = 20
+	}
+
+	var runs []entity.JobRun
+	total, err := uc.JobRepository.PaginateWithSpec(ctx, uc.DB, uc.JobRepository.RunsSpec(name, req.Status), req.Page, req.Size, &runs)
+	if err != nil {
+		uc.Log.Error("failed to list job runs: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list job runs")
+	}
+
+	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
+	return converter.JobRunsToResponse(runs), total, totalPages, nil
+}
+
+// TriggerJob starts a run immediately, the job keeps running after the request returns
+func (uc *JobUseCase) TriggerJob(ctx context.Context, name string, adminID int) (*model.JobRunResponse, error) {
+	run, err := uc.Scheduler.Trigger(name, &adminID)
+	if err != nil {
+		switch {
+		case errors.Is(err, jobs.ErrUnknownJob):
+			return nil, fiber.NewError(fiber.StatusNotFound, "job not found")
+		case errors.Is(err, jobs.ErrJobRunning):
+			return nil, fiber.NewError(fiber.StatusConflict, "job is already running")
+		case errors.Is(err, jobs.ErrStopped):
+			return nil, fiber.NewError(fiber.StatusServiceUnavailable, "server is shutting down")
+		}
+		uc.Log.Error("failed to trigger job: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to trigger job")
+	}
+
+	uc.Log.Infof("job %s triggered by admin %d", name, adminID)
+	return converter.JobRunToResponse(run), nil
+}
+
+func jobToResponse(info jobs.Info, lastRun *entity.JobRun) *model.JobResponse {
+	response := &model.JobResponse{
+		Name:           info.Name,
+		Schedule:       info.Schedule,
+		TimeoutSeconds: int(info.Timeout.Seconds()),
+		MaxRetries:     info.MaxRetries,
+	}
+	if !info.Next.IsZero() {
+		next := info.Next
+		response.NextRunAt = &next
+	}
+	if lastRun != nil {
+		response.LastRun = converter.JobRunToResponse(lastRun)
+	}
+	return response
+}
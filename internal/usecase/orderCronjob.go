@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
 	"github.com/fathirarya/online-bookstore-api/internal/repository"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -10,28 +11,55 @@ import (
 )
 
 type OrderCronJob struct {
-	DB              *gorm.DB
-	Log             *logrus.Logger
-	OrderRepository *repository.OrderRepository
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	OrderRepository   *repository.OrderRepository
+	OrderStateMachine *OrderStateMachine
+	Rules             OrderRules
 }
 
 func NewOrderCronJob(db *gorm.DB, logger *logrus.Logger,
-	orderRepository *repository.OrderRepository) *OrderCronJob {
+	orderRepository *repository.OrderRepository, orderStateMachine *OrderStateMachine, rules OrderRules) *OrderCronJob {
 	return &OrderCronJob{
-		DB:              db,
-		Log:             logger,
-		OrderRepository: orderRepository,
+		DB:                db,
+		Log:               logger,
+		OrderRepository:   orderRepository,
+		OrderStateMachine: orderStateMachine,
+		Rules:             rules,
 	}
 }
 
 func (w OrderCronJob) CheckingOrderPaymentStatus(ctx context.Context) error {
 	w.Log.Info("cron job started")
 
-	if err := w.OrderRepository.CancelExpiredOrders(w.DB.WithContext(ctx)); err != nil {
+	tx := w.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	expired, err := w.OrderRepository.FindExpiredOrders(tx, w.Rules.PaymentWindow)
+	if err != nil {
+		tx.Rollback()
 		w.Log.Error("failed to cancel expired orders: ", err)
 		return fiber.NewError(fiber.StatusInternalServerError, "failed to cancel expired orders")
 	}
 
-	w.Log.Info("cron job done")
+	// Pembatalan lewat state machine supaya stok dikembalikan dan history tercatat
+	for i := range expired {
+		if err := w.OrderStateMachine.Transition(ctx, tx, &expired[i], enum.Cancelled, nil, "payment window expired"); err != nil {
+			tx.Rollback()
+			w.Log.Error("failed to cancel expired order: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to cancel expired orders")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		w.Log.Error("failed to commit transaction: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to cancel expired orders")
+	}
+
+	w.Log.Infof("cron job done, %d expired orders cancelled", len(expired))
 	return nil
 }
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+)
+
+// OrderRules adalah aturan bisnis order yang diatur lewat konfigurasi (lihat config.NewOrderRules)
+type OrderRules struct {
+	// PaymentWindow adalah batas waktu order PENDING dibayar sebelum dibatalkan otomatis
+	PaymentWindow time.Duration
+	// ExpirySchedule adalah jadwal cron job pembatalan order kadaluarsa
+	ExpirySchedule string
+	// MaxQuantityPerItem adalah jumlah maksimal satu judul buku dalam satu order
+	MaxQuantityPerItem int
+	// MaxItemsPerOrder adalah total jumlah buku maksimal dalam satu order
+	MaxItemsPerOrder int
+	// MinOrderValue adalah subtotal minimal sebelum diskon, 0 berarti tidak dibatasi
+	MinOrderValue money.Amount
+	// CategoryMaxQuantityPerItem menimpa MaxQuantityPerItem untuk kategori tertentu
+	CategoryMaxQuantityPerItem map[int]int
+}
+
+// Validate checks the rules are consistent, it is called once at startup
+func (r OrderRules) Validate() error {
+	if r.PaymentWindow <= 0 {
+		return fmt.Errorf("payment window must be positive")
+	}
+	if r.MaxQuantityPerItem < 1 || r.MaxItemsPerOrder < 1 {
+		return fmt.Errorf("max quantity per item and max items per order must be at least 1")
+	}
+	if r.MaxQuantityPerItem > r.MaxItemsPerOrder {
+		return fmt.Errorf("max quantity per item (%d) must not exceed max items per order (%d)", r.MaxQuantityPerItem, r.MaxItemsPerOrder)
+	}
+	if r.MinOrderValue < 0 {
+		return fmt.Errorf("min order value must not be negative")
+	}
+	for categoryID, limit := range r.CategoryMaxQuantityPerItem {
+		if categoryID < 1 || limit < 1 || limit > r.MaxItemsPerOrder {
+			return fmt.Errorf("invalid max quantity %d for category %d", limit, categoryID)
+		}
+	}
+	return nil
+}
+
+// MaxQuantityFor returns the per item limit of a book in the given category
+func (r OrderRules) MaxQuantityFor(categoryID int) int {
+	if limit, ok := r.CategoryMaxQuantityPerItem[categoryID]; ok {
+		return limit
+	}
+	return r.MaxQuantityPerItem
+}
+
+// Public returns the rules clients need to validate a cart before checkout
+func (r OrderRules) Public() *model.PublicConfigResponse {
+	return &model.PublicConfigResponse{
+		Currency:                   money.Currency(),
+		PaymentWindowMinutes:       int(r.PaymentWindow / time.Minute),
+		MaxQuantityPerItem:         r.MaxQuantityPerItem,
+		MaxItemsPerOrder:           r.MaxItemsPerOrder,
+		MinOrderValue:              r.MinOrderValue,
+		CategoryMaxQuantityPerItem: r.CategoryMaxQuantityPerItem,
+	}
+}
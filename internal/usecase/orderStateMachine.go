@@ -0,0 +1,236 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// OrderStateMachine is the single place where an order changes status. It enforces
+// enum.OrderTransitions, records the history and runs the side effects of a transition.
+type OrderStateMachine struct {
+	Log                          *logrus.Logger
+	OrderRepository              *repository.OrderRepository
+	OrderStatusHistoryRepository *repository.OrderStatusHistoryRepository
+	BookRepository               *repository.BookRepository
+	StockMovementRepository      *repository.StockMovementRepository
+	PaymentRepository            *repository.PaymentRepository
+	PaymentProvider              payment.Provider
+	CouponRepository             *repository.CouponRepository
+	OutboxRepository             *repository.OutboxRepository
+}
+
+func NewOrderStateMachine(logger *logrus.Logger, orderRepository *repository.OrderRepository,
+	orderStatusHistoryRepository *repository.OrderStatusHistoryRepository, bookRepository *repository.BookRepository,
+	stockMovementRepository *repository.StockMovementRepository, paymentRepository *repository.PaymentRepository,
+	paymentProvider payment.Provider, couponRepository *repository.CouponRepository, outboxRepository *repository.OutboxRepository) *OrderStateMachine {
+	return &OrderStateMachine{
+		Log:                          logger,
+		OrderRepository:              orderRepository,
+		OrderStatusHistoryRepository: orderStatusHistoryRepository,
+		BookRepository:               bookRepository,
+		StockMovementRepository:      stockMovementRepository,
+		PaymentRepository:            paymentRepository,
+		PaymentProvider:              paymentProvider,
+		CouponRepository:             couponRepository,
+		OutboxRepository:             outboxRepository,
+	}
+}
+
+// Transition moves the order to status `to` inside tx. order.BookOrders must be loaded
+// when cancelling so the reserved stock can be released. changedBy is nil for system changes.
+func (sm *OrderStateMachine) Transition(ctx context.Context, tx *gorm.DB, order *entity.Order, to string, changedBy *int, reason string) error {
+	from := order.Status
+	if !enum.CanTransition(from, to) {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cannot change order status from %s to %s", from, to))
+	}
+
+	// Conditional update, gagal jika status sudah diubah request lain sejak order dibaca
+	changed, err := sm.OrderRepository.TransitionStatus(tx, order.ID, from, to)
+	if err != nil {
+		sm.Log.Error("failed to update order status: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	if !changed {
+		return fiber.NewError(fiber.StatusConflict, "order status has been changed, please retry")
+	}
+
+	if err := sm.OrderStatusHistoryRepository.Create(tx, &entity.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}); err != nil {
+		sm.Log.Error("failed to record order status history: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+
+	// Side effect: order yang batal sebelum dibayar mengembalikan stok dan kuota kupon
+	if from == enum.Pending && to == enum.Cancelled {
+		if err := releaseOrderStock(tx, sm.BookRepository, sm.StockMovementRepository, order, "order cancelled"); err != nil {
+			sm.Log.Error("failed to release order stock: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+		}
+		if err := releaseOrderCoupon(tx, sm.CouponRepository, order.ID); err != nil {
+			sm.Log.Error("failed to release order coupon: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+		}
+	}
+
+	// Side effect: uang sudah dikembalikan oleh Refund, payment-nya ditandai REFUNDED
+	if to == enum.Refunded {
+		if err := sm.completeRefund(tx, order); err != nil {
+			return err
+		}
+	}
+
+	// Event ikut di-rollback bersama transisinya, mis. order.paid atau order.cancelled
+	if err := sm.OutboxRepository.Append(tx, enum.AggregateOrder, order.ID, enum.EventOrderStatusPrefix+strings.ToLower(to), &model.OrderStatusEvent{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		PreviousStatus: from,
+		Status:         to,
+		TotalPrice:     order.TotalPrice,
+		Currency:       order.Currency,
+		Reason:         reason,
+		ChangedBy:      changedBy,
+	}); err != nil {
+		sm.Log.Error("failed to record order event: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+
+	order.Status = to
+	return nil
+}
+
+// Refund moves a REFUND_REQUESTED order to REFUNDED and returns the money through the provider.
+// The provider call cannot be rolled back, so the payment is first marked REFUNDING and committed,
+// then refunded with an idempotency key of the payment, and only then the order changes status.
+// A failed attempt leaves the order REFUND_REQUESTED and can be retried without refunding twice.
+func (sm *OrderStateMachine) Refund(ctx context.Context, db *gorm.DB, orderID int, changedBy *int, reason string) (*entity.Order, error) {
+	record, err := sm.startRefund(ctx, db, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		if err := sm.PaymentProvider.Refund(ctx, record.ProviderRef, record.Amount, RefundIdempotencyKey(record.ID)); err != nil {
+			sm.Log.Error("failed to refund payment: ", err)
+			return nil, fiber.NewError(fiber.StatusBadGateway, "failed to refund payment, please retry")
+		}
+	}
+
+	tx := db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := sm.OrderRepository.FindByID(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		sm.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	if err := sm.Transition(ctx, tx, order, enum.Refunded, changedBy, reason); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		sm.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	return order, nil
+}
+
+// RefundIdempotencyKey identifies the refund of a payment at the provider, every retry uses the same key
+func RefundIdempotencyKey(paymentID int) string {
+	return fmt.Sprintf("refund_payment_%d", paymentID)
+}
+
+// startRefund commits the refund intent. It returns nil when the order has no payment to refund.
+func (sm *OrderStateMachine) startRefund(ctx context.Context, db *gorm.DB, orderID int) (*entity.Payment, error) {
+	tx := db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := sm.OrderRepository.FindByID(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		sm.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+	if !enum.CanTransition(order.Status, enum.Refunded) {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("cannot change order status from %s to %s", order.Status, enum.Refunded))
+	}
+
+	record, err := sm.PaymentRepository.FindRefundableByOrderID(tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		// Order lama yang ditandai PAID manual tidak punya payment di provider
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			sm.Log.Warnf("order %d has no succeeded payment to refund", orderID)
+			return nil, nil
+		}
+		sm.Log.Error("failed to fetch payment: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	if record.Provider != sm.PaymentProvider.Name() {
+		tx.Rollback()
+		sm.Log.Errorf("payment %s was made with provider %s", record.ProviderRef, record.Provider)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to refund payment")
+	}
+
+	if record.Status == enum.PaymentSucceeded {
+		if err := sm.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentRefunding); err != nil {
+			tx.Rollback()
+			sm.Log.Error("failed to update payment status: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		sm.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	return record, nil
+}
+
+// completeRefund marks the payment refunded by Refund. A payment that is still SUCCEEDED means
+// the money was never returned, so the order must not become REFUNDED.
+func (sm *OrderStateMachine) completeRefund(tx *gorm.DB, order *entity.Order) error {
+	record, err := sm.PaymentRepository.FindRefundableByOrderID(tx, order.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		sm.Log.Error("failed to fetch payment: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	if record.Status != enum.PaymentRefunding {
+		sm.Log.Errorf("order %d moved to REFUNDED without OrderStateMachine.Refund", order.ID)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to refund payment")
+	}
+	if err := sm.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentRefunded); err != nil {
+		sm.Log.Error("failed to update payment status: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+	}
+	return nil
+}
@@ -2,12 +2,19 @@ package usecase
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
 	"github.com/fathirarya/online-bookstore-api/internal/enum"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
 	"github.com/fathirarya/online-bookstore-api/internal/repository"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -16,21 +23,40 @@ import (
 )
 
 type OrderUseCase struct {
-	DB              *gorm.DB
-	Log             *logrus.Logger
-	Validate        *validator.Validate
-	OrderRepository *repository.OrderRepository
-	BookRepository  *repository.BookRepository
+	DB                           *gorm.DB
+	Log                          *logrus.Logger
+	Validate                     *validator.Validate
+	OrderRepository              *repository.OrderRepository
+	BookRepository               *repository.BookRepository
+	StockMovementRepository      *repository.StockMovementRepository
+	OrderStatusHistoryRepository *repository.OrderStatusHistoryRepository
+	OrderStateMachine            *OrderStateMachine
+	CouponRepository             *repository.CouponRepository
+	OrderNoteRepository          *repository.OrderNoteRepository
+	OutboxRepository             *repository.OutboxRepository
+	Rules                        OrderRules
+	LowStockThreshold            int
 }
 
 func NewOrderUseCase(db *gorm.DB, logger *logrus.Logger, validate *validator.Validate,
-	orderRepository *repository.OrderRepository, bookRepository *repository.BookRepository) *OrderUseCase {
+	orderRepository *repository.OrderRepository, bookRepository *repository.BookRepository,
+	stockMovementRepository *repository.StockMovementRepository, orderStatusHistoryRepository *repository.OrderStatusHistoryRepository,
+	orderStateMachine *OrderStateMachine, couponRepository *repository.CouponRepository,
+	orderNoteRepository *repository.OrderNoteRepository, outboxRepository *repository.OutboxRepository, rules OrderRules, lowStockThreshold int) *OrderUseCase {
 	return &OrderUseCase{
-		DB:              db,
-		Log:             logger,
-		Validate:        validate,
-		OrderRepository: orderRepository,
-		BookRepository:  bookRepository,
+		DB:                           db,
+		Log:                          logger,
+		Validate:                     validate,
+		OrderRepository:              orderRepository,
+		BookRepository:               bookRepository,
+		StockMovementRepository:      stockMovementRepository,
+		OrderStatusHistoryRepository: orderStatusHistoryRepository,
+		OrderStateMachine:            orderStateMachine,
+		CouponRepository:             couponRepository,
+		OrderNoteRepository:          orderNoteRepository,
+		OutboxRepository:             outboxRepository,
+		Rules:                        rules,
+		LowStockThreshold:            lowStockThreshold,
 	}
 }
 
@@ -47,18 +73,46 @@ func (uc *OrderUseCase) CreateOrder(ctx context.Context, req *model.CreateOrderR
 		}
 	}()
 
+	order, err := uc.placeOrder(tx, userID, req.Items, req.CouponCode)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
+	}
+
+	fullOrder, err := uc.OrderRepository.FindByID(uc.DB, order.ID)
+	if err != nil {
+		uc.Log.Error("failed to fetch full order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch order")
+	}
+
+	return converter.OrderToResponse(fullOrder), nil
+}
+
+// placeOrder applies the order rules (quantity limits, minimum value, stock reservation, coupon) and
+// creates the order inside tx. It is shared by CreateOrder and cart checkout, the caller owns commit and rollback.
+func (uc *OrderUseCase) placeOrder(tx *gorm.DB, userID int, items []model.OrderItemInput, couponCode string) (*entity.Order, error) {
+	// Buku yang sama di beberapa baris digabung, batas per judul berlaku untuk totalnya
+	items = mergeOrderItems(items)
+
 	totalQuantity := 0
-	for _, item := range req.Items {
+	for _, item := range items {
 		totalQuantity += item.Quantity
 	}
-	if totalQuantity > 5 {
-		return nil, fiber.NewError(fiber.StatusBadRequest, "maximum 5 books per transaction")
+	if totalQuantity > uc.Rules.MaxItemsPerOrder {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("maximum %d books per transaction", uc.Rules.MaxItemsPerOrder))
 	}
 
 	var bookOrders []entity.BookOrder
-	totalPrice := 0.0
+	var balances []int // saldo stok setelah reservasi tiap baris, untuk ledger
+	var insufficientBookIDs []string
+	var totalPrice money.Amount
 
-	for _, item := range req.Items {
+	for _, item := range items {
 		var book entity.Book
 		if err := uc.BookRepository.FindById(tx, &book, item.BookID); err != nil {
 			if err == gorm.ErrRecordNotFound {
@@ -67,43 +121,235 @@ func (uc *OrderUseCase) CreateOrder(ctx context.Context, req *model.CreateOrderR
 			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
 		}
 
+		if limit := uc.Rules.MaxQuantityFor(book.CategoryID); item.Quantity > limit {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("maximum %d copies of book %d per transaction", limit, book.ID))
+		}
+
+		// Reservasi stok dengan conditional update, gagal jika stok tidak cukup
+		reserved, err := uc.BookRepository.DecrementStock(tx, book.ID, item.Quantity)
+		if err != nil {
+			uc.Log.Error("failed to reserve stock: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+		if !reserved {
+			insufficientBookIDs = append(insufficientBookIDs, strconv.Itoa(book.ID))
+			continue
+		}
+
+		// Dibaca sebelum baris berikutnya mengubah stok, jadi balance_after milik baris ini
+		balance, err := uc.BookRepository.GetStock(tx, book.ID)
+		if err != nil {
+			uc.Log.Error("failed to fetch stock: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+		balances = append(balances, balance)
+
 		bookOrders = append(bookOrders, entity.BookOrder{
-			BookID:   book.ID,
-			Quantity: item.Quantity,
-			Book:     book,
+			BookID:    book.ID,
+			Quantity:  item.Quantity,
+			UnitPrice: book.Price,
+			Title:     book.Title,
+			Author:    book.Author,
+			Book:      book,
 		})
 
-		totalPrice += float64(item.Quantity) * book.Price
+		totalPrice += book.Price.Mul(item.Quantity)
+	}
+
+	// Semua buku dicek dulu supaya client tahu buku mana saja yang stoknya kurang
+	if len(insufficientBookIDs) > 0 {
+		return nil, fiber.NewError(fiber.StatusConflict, "insufficient stock for books: "+strings.Join(insufficientBookIDs, ", "))
+	}
+
+	if totalPrice < uc.Rules.MinOrderValue {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("minimum order value is %s", uc.Rules.MinOrderValue))
 	}
 
 	order := &entity.Order{
 		UserID:     userID,
+		Subtotal:   totalPrice,
 		TotalPrice: totalPrice,
+		Currency:   money.Currency(),
 		Status:     enum.Pending,
 		BookOrders: bookOrders,
 	}
 
+	var coupon *entity.Coupon
+	if couponCode != "" {
+		var discount money.Amount
+		var err error
+		coupon, discount, err = redeemCoupon(tx, uc.CouponRepository, couponCode, userID, bookOrders, totalPrice)
+		if err != nil {
+			if _, ok := err.(*fiber.Error); ok {
+				return nil, err
+			}
+			uc.Log.Error("failed to apply coupon: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
+		}
+		order.CouponID = &coupon.ID
+		order.CouponCode = coupon.Code
+		order.DiscountAmount = discount
+		order.TotalPrice = totalPrice - discount
+	}
+
 	if err := uc.OrderRepository.Create(tx, order); err != nil {
-		tx.Rollback()
 		uc.Log.Error("failed to create order: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		uc.Log.Error("failed to commit transaction: ", err)
+	if coupon != nil {
+		if err := uc.CouponRepository.CreateRedemption(tx, &entity.CouponRedemption{
+			CouponID:       coupon.ID,
+			UserID:         userID,
+			OrderID:        order.ID,
+			DiscountAmount: order.DiscountAmount,
+		}); err != nil {
+			uc.Log.Error("failed to record coupon redemption: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
+		}
+	}
+
+	for i, line := range bookOrders {
+		if err := uc.StockMovementRepository.Create(tx, &entity.StockMovement{
+			BookID:       line.BookID,
+			Change:       -line.Quantity,
+			BalanceAfter: balances[i],
+			Reason:       "order reserved",
+			OrderID:      &order.ID,
+			CreatedBy:    &userID,
+		}); err != nil {
+			uc.Log.Error("failed to record stock movement: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
+		}
+	}
+
+	// Satu baris per buku dan row buku masih terkunci oleh DecrementStock,
+	// jadi saldo setelah reservasi adalah saldo akhir buku tersebut
+	for i := range bookOrders {
+		line := &bookOrders[i]
+		if err := publishLowStock(tx, uc.OutboxRepository, uc.LowStockThreshold, &line.Book, balances[i], -line.Quantity); err != nil {
+			uc.Log.Error("failed to record stock event: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
+		}
+	}
+
+	if err := uc.OutboxRepository.Append(tx, enum.AggregateOrder, order.ID, enum.EventOrderCreated, converter.OrderToResponse(order)); err != nil {
+		uc.Log.Error("failed to record order event: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create order")
 	}
 
-	fullOrder, err := uc.OrderRepository.FindByID(uc.DB, order.ID)
+	return order, nil
+}
+
+// mergeOrderItems sums the quantities of repeated book IDs, keeping the order of first appearance.
+// book_orders has one row per (book_id, order_id), so a book can only be one line of an order.
+func mergeOrderItems(items []model.OrderItemInput) []model.OrderItemInput {
+	merged := make([]model.OrderItemInput, 0, len(items))
+	index := make(map[int]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item.BookID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.BookID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// GetOrdersByUser lists the user's orders page by page, newest first
+func (uc *OrderUseCase) GetOrdersByUser(ctx context.Context, userID int, req *model.ListOrdersRequest) (*model.OrderListResponse, int, int, int64, int64, error) {
+	if err := validateListOrdersRequest(uc.Validate, req, req.DateFrom, req.DateTo); err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	page, size := req.Page, req.Size
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	var orders []entity.Order
+	total, err := uc.OrderRepository.PaginateWithSpec(ctx, uc.DB.Preload("BookOrders"),
+		uc.OrderRepository.UserOrdersSpec(userID, req), page, size, &orders)
 	if err != nil {
-		uc.Log.Error("failed to fetch full order: ", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch order")
+		uc.Log.Error("failed to fetch orders: ", err)
+		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch orders")
 	}
 
-	return converter.OrderToResponse(fullOrder), nil
+	orderResponses := make([]model.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		orderResponses = append(orderResponses, *converter.OrderToResponse(&order))
+	}
+
+	totalPages := (total + int64(size) - 1) / int64(size)
+
+	return &model.OrderListResponse{
+		Orders: orderResponses,
+	}, page, size, total, totalPages, nil
 }
 
-func (uc *OrderUseCase) PayOrder(ctx context.Context, orderID int, userID int) (*model.OrderResponse, error) {
+// GetOrdersByUserCursor is the keyset paginated variant of GetOrdersByUser
+func (uc *OrderUseCase) GetOrdersByUserCursor(ctx context.Context, userID int, req *model.ListOrdersRequest) (*model.OrderListResponse, string, string, error) {
+	if err := validateListOrdersRequest(uc.Validate, req, req.DateFrom, req.DateTo); err != nil {
+		return nil, "", "", err
+	}
+
+	var orders []entity.Order
+	next, prev, err := uc.OrderRepository.PaginateByCursor(ctx, uc.DB.Preload("BookOrders"),
+		uc.OrderRepository.UserOrdersSpec(userID, req), req.Cursor, req.Limit, &orders)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", "", fiber.NewError(fiber.StatusBadRequest, "invalid cursor")
+		}
+		uc.Log.Error("failed to fetch orders: ", err)
+		return nil, "", "", fiber.NewError(fiber.StatusInternalServerError, "failed to fetch orders")
+	}
+
+	orderResponses := make([]model.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		orderResponses = append(orderResponses, *converter.OrderToResponse(&order))
+	}
+
+	return &model.OrderListResponse{
+		Orders: orderResponses,
+	}, next, prev, nil
+}
+
+// validateListOrdersRequest memeriksa query param list order, termasuk urutan rentang tanggal
+func validateListOrdersRequest(validate *validator.Validate, req any, dateFrom, dateTo string) error {
+	if err := validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters: page/size/limit must be between 1 and 100, status must be a valid order status, dates must be YYYY-MM-DD")
+	}
+	// Format YYYY-MM-DD bisa dibandingkan sebagai string
+	if dateFrom != "" && dateTo != "" && dateFrom > dateTo {
+		return fiber.NewError(fiber.StatusBadRequest, "date_from must not be after date_to")
+	}
+	return nil
+}
+
+// GetOrder returns one of the user's own orders
+func (uc *OrderUseCase) GetOrder(ctx context.Context, userID, orderID int) (*model.OrderResponse, error) {
+	order, err := uc.OrderRepository.FindByID(uc.DB.WithContext(ctx), orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	if order.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "you are not allowed to access this order")
+	}
+
+	return converter.OrderToResponse(order), nil
+}
+
+// CancelOrder cancels the user's own PENDING order, the state machine releases the reserved stock and coupon
+func (uc *OrderUseCase) CancelOrder(ctx context.Context, userID, orderID int) (*model.OrderResponse, error) {
 	tx := uc.DB.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -113,58 +359,275 @@ func (uc *OrderUseCase) PayOrder(ctx context.Context, orderID int, userID int) (
 
 	order, err := uc.OrderRepository.FindByID(tx, orderID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
 		}
+		uc.Log.Error("failed to fetch order: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
 	}
 
 	if order.UserID != userID {
-		return nil, fiber.NewError(fiber.StatusForbidden, "you are not allowed to pay this order")
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusForbidden, "you are not allowed to cancel this order")
+	}
+	// Customer hanya boleh membatalkan order yang belum dibayar, selebihnya lewat refund
+	if order.Status != enum.Pending {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusConflict, "only pending orders can be cancelled")
+	}
+
+	if err := uc.OrderStateMachine.Transition(ctx, tx, order, enum.Cancelled, &userID, "cancelled by customer"); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to cancel order")
+	}
+
+	return converter.OrderToResponse(order), nil
+}
+
+// UpdateOrderStatus advances an order by an admin, the transition is validated by OrderStateMachine
+func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, orderID int, req *model.UpdateOrderStatusRequest, adminID int) (*model.OrderResponse, error) {
+	if err := uc.Validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "validation failed, please check your input")
 	}
 
-	switch order.Status {
-	case enum.Cancelled:
-		return nil, fiber.NewError(fiber.StatusBadRequest, "order has been cancelled")
+	switch req.Status {
 	case enum.Paid:
-		return nil, fiber.NewError(fiber.StatusBadRequest, "order has been paid")
+		// PAID hanya dari webhook provider, supaya setiap order PAID punya payment yang bisa di-refund
+		return nil, fiber.NewError(fiber.StatusConflict, "orders are marked PAID by the payment provider webhook")
+	case enum.Refunded:
+		order, err := uc.OrderStateMachine.Refund(ctx, uc.DB, orderID, &adminID, req.Reason)
+		if err != nil {
+			return nil, err
+		}
+		return converter.OrderToResponse(order), nil
 	}
 
-	if err := uc.OrderRepository.UpdateStatus(tx, orderID, enum.Paid); err != nil {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := uc.OrderRepository.FindByID(tx, orderID)
+	if err != nil {
 		tx.Rollback()
-		uc.Log.Error("failed to update order status: ", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	if err := uc.OrderStateMachine.Transition(ctx, tx, order, req.Status, &adminID, req.Reason); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		uc.Log.Error("failed to commit transaction: ", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to commit transaction")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update order status")
 	}
 
-	fullOrder, err := uc.OrderRepository.FindByID(uc.DB, orderID)
+	return converter.OrderToResponse(order), nil
+}
+
+// GetOrderStatusHistory returns every status change of an order oldest first
+func (uc *OrderUseCase) GetOrderStatusHistory(ctx context.Context, orderID int) ([]*model.OrderStatusHistoryResponse, error) {
+	db := uc.DB.WithContext(ctx)
+
+	var order entity.Order
+	if err := uc.OrderRepository.FindById(db, &order, orderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	histories, err := uc.OrderStatusHistoryRepository.FindByOrderID(db, orderID)
 	if err != nil {
-		uc.Log.Error("failed to fetch full order: ", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch order")
+		uc.Log.Error("failed to fetch order status history: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch order status history")
 	}
 
-	return converter.OrderToResponse(fullOrder), nil
+	return converter.OrderStatusHistoriesToResponse(histories), nil
 }
 
-func (uc *OrderUseCase) GetOrdersByUser(ctx context.Context, userID int) (*model.OrderListResponse, error) {
-	tx := uc.DB.WithContext(ctx)
+// ListAllOrders lists every customer's orders for the back office
+func (uc *OrderUseCase) ListAllOrders(ctx context.Context, req *model.AdminListOrdersRequest) (*model.OrderListResponse, int, int, int64, int64, error) {
+	if err := validateListOrdersRequest(uc.Validate, req, req.DateFrom, req.DateTo); err != nil {
+		return nil, 0, 0, 0, 0, err
+	}
+
+	page, size := req.Page, req.Size
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
 
-	orders, err := uc.OrderRepository.FindByUserID(tx, userID)
+	var orders []entity.Order
+	total, err := uc.OrderRepository.PaginateWithSpec(ctx, uc.DB.Preload("BookOrders"),
+		uc.OrderRepository.AdminOrdersSpec(req), page, size, &orders)
 	if err != nil {
 		uc.Log.Error("failed to fetch orders: ", err)
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch orders")
+		return nil, 0, 0, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch orders")
 	}
 
-	var orderResponses []model.OrderResponse
+	orderResponses := make([]model.OrderResponse, 0, len(orders))
 	for _, order := range orders {
 		orderResponses = append(orderResponses, *converter.OrderToResponse(&order))
 	}
 
+	totalPages := (total + int64(size) - 1) / int64(size)
+
 	return &model.OrderListResponse{
 		Orders: orderResponses,
-	}, nil
+	}, page, size, total, totalPages, nil
+}
+
+// GetOrderForAdmin returns any order with the customer and the internal notes
+func (uc *OrderUseCase) GetOrderForAdmin(ctx context.Context, orderID int) (*model.AdminOrderResponse, error) {
+	db := uc.DB.WithContext(ctx)
+
+	order, err := uc.OrderRepository.FindByID(db.Preload("User"), orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	notes, err := uc.OrderNoteRepository.FindByOrderID(db, orderID)
+	if err != nil {
+		uc.Log.Error("failed to fetch order notes: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	return converter.AdminOrderToResponse(order, notes), nil
+}
+
+// AddOrderNote menambahkan catatan internal staf ke order
+func (uc *OrderUseCase) AddOrderNote(ctx context.Context, orderID, authorID int, req *model.CreateOrderNoteRequest) (*model.OrderNoteResponse, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	if err := uc.Validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "validation failed, please check your input")
+	}
+
+	db := uc.DB.WithContext(ctx)
+
+	var order entity.Order
+	if err := uc.OrderRepository.FindById(db, &order, orderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	note := &entity.OrderNote{
+		OrderID:  orderID,
+		AuthorID: authorID,
+		Note:     req.Note,
+	}
+	if err := uc.OrderNoteRepository.Create(db, note); err != nil {
+		uc.Log.Error("failed to create order note: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to add order note")
+	}
+	if err := db.Take(&note.Author, authorID).Error; err != nil {
+		uc.Log.Warn("failed to fetch note author: ", err)
+	}
+
+	return converter.OrderNoteToResponse(note), nil
+}
+
+// orderCSVHeader adalah kolom export CSV untuk tim finance
+var orderCSVHeader = []string{
+	"order_id", "created_at", "status", "customer_id", "customer_name", "customer_email",
+	"items", "quantity", "subtotal", "discount_amount", "coupon_code", "total_price", "currency",
+}
+
+// ExportOrdersCSV writes every order matching the admin filters to w as CSV, ordered by id.
+// Paging params are ignored.
+func (uc *OrderUseCase) ExportOrdersCSV(ctx context.Context, req *model.AdminListOrdersRequest, w io.Writer) error {
+	if err := validateListOrdersRequest(uc.Validate, req, req.DateFrom, req.DateTo); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(orderCSVHeader); err != nil {
+		return err
+	}
+
+	spec := uc.OrderRepository.AdminOrdersSpec(req)
+	err := uc.OrderRepository.FindInBatchesWithSpec(uc.DB.WithContext(ctx).Preload("User").Preload("BookOrders"), spec, 500,
+		func(orders []entity.Order) error {
+			for _, order := range orders {
+				quantity := 0
+				for _, line := range order.BookOrders {
+					quantity += line.Quantity
+				}
+				if err := writer.Write([]string{
+					strconv.Itoa(order.ID),
+					order.CreatedAt.Format(time.RFC3339),
+					order.Status,
+					strconv.Itoa(order.UserID),
+					csvSafe(order.User.Name),
+					csvSafe(order.User.Email),
+					strconv.Itoa(len(order.BookOrders)),
+					strconv.Itoa(quantity),
+					order.Subtotal.String(),
+					order.DiscountAmount.String(),
+					csvSafe(order.CouponCode),
+					order.TotalPrice.String(),
+					order.Currency,
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		uc.Log.Error("failed to export orders: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to export orders")
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe mencegah formula injection saat file dibuka di spreadsheet
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// GetOrderStats counts orders per status for the admin CLI
+func (uc *OrderUseCase) GetOrderStats(ctx context.Context) (*model.OrderStatsResponse, error) {
+	counts, err := uc.OrderRepository.CountByStatus(uc.DB.WithContext(ctx))
+	if err != nil {
+		uc.Log.Error("failed to count orders: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to get order stats")
+	}
+
+	stats := &model.OrderStatsResponse{Statuses: counts, Currency: money.Currency()}
+	for _, count := range counts {
+		stats.TotalOrders += count.Count
+		switch count.Status {
+		case enum.Paid, enum.Processing, enum.Shipped, enum.Delivered, enum.RefundRequested:
+			stats.Revenue += count.TotalPrice
+		}
+	}
+	return stats, nil
 }
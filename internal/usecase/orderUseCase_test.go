@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+var testOrderRules = OrderRules{PaymentWindow: 15 * time.Minute, MaxQuantityPerItem: 5, MaxItemsPerOrder: 5}
+
+// newTestOrderUseCase wires the order use case the same way config.Bootstrap does, on top of db
+func newTestOrderUseCase(db *gorm.DB) *OrderUseCase {
+	log := testutil.NewLogger()
+	orderRepository := repository.NewOrderRepository(db, log)
+	bookRepository := repository.NewBookRepository(db, log)
+	stockMovementRepository := repository.NewStockMovementRepository(db, log)
+	orderStatusHistoryRepository := repository.NewOrderStatusHistoryRepository(db, log)
+	couponRepository := repository.NewCouponRepository(db, log)
+	outboxRepository := repository.NewOutboxRepository(db, log)
+	orderStateMachine := NewOrderStateMachine(log, orderRepository, orderStatusHistoryRepository, bookRepository,
+		stockMovementRepository, repository.NewPaymentRepository(db, log), payment.NewFakeProvider("secret", log),
+		couponRepository, outboxRepository)
+	return NewOrderUseCase(db, log, validator.New(), orderRepository, bookRepository, stockMovementRepository,
+		orderStatusHistoryRepository, orderStateMachine, couponRepository, repository.NewOrderNoteRepository(db, log),
+		outboxRepository, testOrderRules, 5)
+}
+
+// createTestBook membuat customer, kategori dan satu buku, lalu mengembalikan id user dan buku
+func createTestBook(t *testing.T, db *gorm.DB, price money.Amount, stock int) (int, int) {
+	t.Helper()
+	user := &entity.User{Name: "Customer", Email: "customer@example.com", Password: "x", Role: enum.RoleCustomer}
+	category := &entity.Category{Name: "Fiction"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+	book := &entity.Book{Title: "Odd Cents", Author: "Author", Price: price, Year: 2020, CategoryID: category.ID, Stock: stock}
+	if err := db.Create(book).Error; err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+	return user.ID, book.ID
+}
+
+func TestCreateOrderTotalsAreExactToTheCent(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	uc := newTestOrderUseCase(db)
+	userID, bookID := createTestBook(t, db, money.FromMinor(499), 10)
+
+	coupon := &entity.Coupon{Code: "FIFTEEN", Type: enum.CouponPercentage, Value: money.FromMinor(1500), Active: true}
+	if err := db.Create(coupon).Error; err != nil {
+		t.Fatalf("failed to create coupon: %v", err)
+	}
+
+	order, err := uc.CreateOrder(context.Background(), &model.CreateOrderRequest{
+		Items:      []model.OrderItemInput{{BookID: bookID, Quantity: 3}},
+		CouponCode: "fifteen",
+	}, userID)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+
+	// 4.99 × 3 = 14.97, 15% = 2.2455 dibulatkan ke 2.25, total 12.72
+	if order.Subtotal != money.FromMinor(1497) {
+		t.Errorf("subtotal = %s, want 14.97", order.Subtotal)
+	}
+	if order.DiscountAmount != money.FromMinor(225) {
+		t.Errorf("discount = %s, want 2.25", order.DiscountAmount)
+	}
+	if order.TotalPrice != money.FromMinor(1272) {
+		t.Errorf("total = %s, want 12.72", order.TotalPrice)
+	}
+	if len(order.Items) != 1 || order.Items[0].Price != money.FromMinor(499) || order.Items[0].SubTotal != money.FromMinor(1497) {
+		t.Errorf("items = %+v, want one line of 3 × 4.99 = 14.97", order.Items)
+	}
+
+	// Nilai yang tersimpan di database harus sama persis dengan response
+	var stored entity.Order
+	if err := db.First(&stored, order.ID).Error; err != nil {
+		t.Fatalf("failed to load order: %v", err)
+	}
+	if stored.Subtotal != order.Subtotal || stored.DiscountAmount != order.DiscountAmount || stored.TotalPrice != order.TotalPrice {
+		t.Errorf("stored order = %s - %s = %s, want %s - %s = %s", stored.Subtotal, stored.DiscountAmount, stored.TotalPrice,
+			order.Subtotal, order.DiscountAmount, order.TotalPrice)
+	}
+}
+
+func TestCreateOrderMergesRepeatedBooks(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	uc := newTestOrderUseCase(db)
+	uc.Rules.MaxItemsPerOrder = 10
+	userID, bookID := createTestBook(t, db, money.FromMinor(1000), 10)
+	ctx := context.Background()
+
+	// 3 + 3 melebihi batas 5 per judul walaupun tiap baris di bawah batas
+	_, err := uc.CreateOrder(ctx, &model.CreateOrderRequest{
+		Items: []model.OrderItemInput{{BookID: bookID, Quantity: 3}, {BookID: bookID, Quantity: 3}},
+	}, userID)
+	if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusBadRequest || !strings.Contains(fiberErr.Message, "copies") {
+		t.Fatalf("CreateOrder() error = %v, want 400 for the per book limit", err)
+	}
+
+	order, err := uc.CreateOrder(ctx, &model.CreateOrderRequest{
+		Items: []model.OrderItemInput{{BookID: bookID, Quantity: 2}, {BookID: bookID, Quantity: 1}},
+	}, userID)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	if len(order.Items) != 1 || order.Items[0].Quantity != 3 || order.TotalPrice != money.FromMinor(3000) {
+		t.Fatalf("order = %+v, want one line of 3 × 10.00", order)
+	}
+
+	var book entity.Book
+	if err := db.First(&book, bookID).Error; err != nil {
+		t.Fatalf("failed to load book: %v", err)
+	}
+	if book.Stock != 7 {
+		t.Errorf("stock = %d, want 7", book.Stock)
+	}
+	var movements []entity.StockMovement
+	if err := db.Where("order_id = ?", order.ID).Find(&movements).Error; err != nil {
+		t.Fatalf("failed to load stock movements: %v", err)
+	}
+	if len(movements) != 1 || movements[0].Change != -3 || movements[0].BalanceAfter != 7 {
+		t.Errorf("movements = %+v, want one movement of -3 with balance 7", movements)
+	}
+}
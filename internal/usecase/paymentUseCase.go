@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	Provider          payment.Provider
+	PaymentRepository *repository.PaymentRepository
+	OrderRepository   *repository.OrderRepository
+	OrderStateMachine *OrderStateMachine
+}
+
+func NewPaymentUseCase(db *gorm.DB, logger *logrus.Logger, provider payment.Provider,
+	paymentRepository *repository.PaymentRepository, orderRepository *repository.OrderRepository,
+	orderStateMachine *OrderStateMachine) *PaymentUseCase {
+	return &PaymentUseCase{
+		DB:                db,
+		Log:               logger,
+		Provider:          provider,
+		PaymentRepository: paymentRepository,
+		OrderRepository:   orderRepository,
+		OrderStateMachine: orderStateMachine,
+	}
+}
+
+// CreatePayment starts a payment attempt for a pending order, the order becomes PAID
+// only after the provider confirms it through the webhook
+func (uc *PaymentUseCase) CreatePayment(ctx context.Context, orderID int, userID int) (*model.PaymentResponse, error) {
+	var order entity.Order
+	if err := uc.OrderRepository.FindById(uc.DB.WithContext(ctx), &order, orderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("order not found: %d", orderID))
+		}
+		uc.Log.Error("failed to fetch order: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	if order.UserID != userID {
+		return nil, fiber.NewError(fiber.StatusForbidden, "you are not allowed to pay this order")
+	}
+	if order.Status != enum.Pending {
+		return nil, fiber.NewError(fiber.StatusConflict, "order is not pending")
+	}
+
+	intent, err := uc.Provider.CreatePaymentIntent(ctx, order.ID, order.TotalPrice, order.Currency)
+	if err != nil {
+		uc.Log.Error("failed to create payment intent: ", err)
+		return nil, fiber.NewError(fiber.StatusBadGateway, "failed to create payment")
+	}
+
+	record := &entity.Payment{
+		OrderID:     order.ID,
+		Provider:    uc.Provider.Name(),
+		ProviderRef: intent.ProviderRef,
+		Amount:      order.TotalPrice,
+		Currency:    order.Currency,
+		Status:      enum.PaymentPending,
+	}
+	if err := uc.PaymentRepository.Create(uc.DB.WithContext(ctx), record); err != nil {
+		uc.Log.Error("failed to create payment: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create payment")
+	}
+
+	return converter.PaymentToResponse(record, intent.ClientSecret), nil
+}
+
+// HandleWebhook applies a signed provider callback. Duplicate deliveries of the same event are ignored.
+func (uc *PaymentUseCase) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := uc.Provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid webhook signature")
+		}
+		return fiber.NewError(fiber.StatusBadRequest, "invalid webhook payload")
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	record, err := uc.PaymentRepository.FindByProviderRefForUpdate(tx, event.ProviderRef)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "payment not found")
+		}
+		uc.Log.Error("failed to fetch payment: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+
+	created, err := uc.PaymentRepository.CreateEvent(tx, &entity.PaymentEvent{
+		EventID:   event.ID,
+		PaymentID: record.ID,
+		Type:      event.Type,
+	})
+	if err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to record payment event: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+	if !created {
+		// Delivery ulang dari provider, sudah diproses sebelumnya
+		tx.Rollback()
+		return uc.retryLateRefund(ctx, record, event.ID)
+	}
+
+	refund := false
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		if refund, err = uc.applySucceeded(ctx, tx, record, event); err != nil {
+			tx.Rollback()
+			return err
+		}
+	case payment.EventPaymentFailed:
+		if record.Status == enum.PaymentPending {
+			if err := uc.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentFailed); err != nil {
+				tx.Rollback()
+				uc.Log.Error("failed to update payment status: ", err)
+				return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+			}
+		}
+	default:
+		uc.Log.Infof("unhandled payment event type %s", event.Type)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+
+	// Refund baru dikirim setelah niatnya tersimpan, lihat OrderStateMachine.Refund
+	if refund {
+		return uc.refundLatePayment(ctx, record)
+	}
+	return nil
+}
+
+// applySucceeded marks the payment as succeeded and the order as PAID. A payment for an order that
+// is no longer pending (e.g. cancelled by the expiry job) is marked REFUNDING and true is returned,
+// the caller refunds it after commit.
+func (uc *PaymentUseCase) applySucceeded(ctx context.Context, tx *gorm.DB, record *entity.Payment, event *payment.Event) (bool, error) {
+	if record.Status != enum.PaymentPending {
+		return false, nil
+	}
+	if event.Amount != record.Amount || (event.Currency != "" && event.Currency != record.Currency) {
+		// Dicatat untuk dicek manual, bukan error, supaya provider tidak mengirim ulang tanpa henti
+		uc.Log.Errorf("payment %s: provider reported %s %s, expected %s %s", record.ProviderRef,
+			event.Amount, event.Currency, record.Amount, record.Currency)
+		if err := uc.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentMismatched); err != nil {
+			uc.Log.Error("failed to update payment status: ", err)
+			return false, fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+		}
+		return false, nil
+	}
+
+	order, err := uc.OrderRepository.FindByID(tx, record.OrderID)
+	if err != nil {
+		uc.Log.Error("failed to fetch order: ", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+
+	if order.Status != enum.Pending {
+		if err := uc.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentRefunding); err != nil {
+			uc.Log.Error("failed to update payment status: ", err)
+			return false, fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+		}
+		uc.Log.Warnf("payment %s will be refunded, order %d is %s", record.ProviderRef, order.ID, order.Status)
+		return true, nil
+	}
+
+	if err := uc.PaymentRepository.UpdateStatus(tx, record.ID, enum.PaymentSucceeded); err != nil {
+		uc.Log.Error("failed to update payment status: ", err)
+		return false, fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+	return false, uc.OrderStateMachine.Transition(ctx, tx, order, enum.Paid, nil, "payment "+record.ProviderRef+" confirmed")
+}
+
+// refundLatePayment returns a payment that arrived after its order was cancelled. On failure the
+// webhook answers 502, the provider delivers the event again and retryLateRefund tries once more.
+func (uc *PaymentUseCase) refundLatePayment(ctx context.Context, record *entity.Payment) error {
+	if err := uc.Provider.Refund(ctx, record.ProviderRef, record.Amount, RefundIdempotencyKey(record.ID)); err != nil {
+		uc.Log.Error("failed to refund payment: ", err)
+		return fiber.NewError(fiber.StatusBadGateway, "failed to refund payment")
+	}
+	if err := uc.PaymentRepository.UpdateStatus(uc.DB.WithContext(ctx), record.ID, enum.PaymentRefunded); err != nil {
+		uc.Log.Error("failed to update payment status: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+	}
+	uc.Log.Warnf("payment %s refunded, its order %d was no longer pending", record.ProviderRef, record.OrderID)
+	return nil
+}
+
+// retryLateRefund handles a repeated event. Only an unfinished refund of a cancelled order is
+// retried, a REFUNDING payment of a REFUND_REQUESTED order belongs to OrderStateMachine.Refund.
+func (uc *PaymentUseCase) retryLateRefund(ctx context.Context, record *entity.Payment, eventID string) error {
+	if record.Status == enum.PaymentRefunding {
+		var order entity.Order
+		if err := uc.OrderRepository.FindById(uc.DB.WithContext(ctx), &order, record.OrderID); err != nil {
+			uc.Log.Error("failed to fetch order: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to process webhook")
+		}
+		if order.Status == enum.Cancelled {
+			return uc.refundLatePayment(ctx, record)
+		}
+	}
+	uc.Log.Infof("duplicate payment event %s ignored", eventID)
+	return nil
+}
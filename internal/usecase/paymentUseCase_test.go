@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/money"
+	"github.com/fathirarya/online-bookstore-api/internal/payment"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestHandleWebhookIgnoresDuplicateDeliveries(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	log := testutil.NewLogger()
+	orderUseCase := newTestOrderUseCase(db)
+	provider := payment.NewFakeProvider("secret", log)
+	uc := NewPaymentUseCase(db, log, provider, repository.NewPaymentRepository(db, log),
+		orderUseCase.OrderRepository, orderUseCase.OrderStateMachine)
+	userID, bookID := createTestBook(t, db, money.FromMinor(1000), 5)
+	ctx := context.Background()
+
+	order, err := orderUseCase.CreateOrder(ctx, &model.CreateOrderRequest{
+		Items: []model.OrderItemInput{{BookID: bookID, Quantity: 2}},
+	}, userID)
+	if err != nil {
+		t.Fatalf("CreateOrder() error = %v", err)
+	}
+	created, err := uc.CreatePayment(ctx, order.ID, userID)
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	payload, _ := json.Marshal(payment.Event{
+		ID:          "evt_1",
+		Type:        payment.EventPaymentSucceeded,
+		ProviderRef: created.ProviderRef,
+		Amount:      order.TotalPrice,
+		Currency:    order.Currency,
+	})
+	signature := provider.Sign(payload)
+
+	// Provider mengirim event yang sama dua kali
+	for i := 0; i < 2; i++ {
+		if err := uc.HandleWebhook(ctx, payload, signature); err != nil {
+			t.Fatalf("delivery %d: HandleWebhook() error = %v", i+1, err)
+		}
+	}
+
+	var record entity.Payment
+	if err := db.First(&record, created.ID).Error; err != nil {
+		t.Fatalf("failed to load payment: %v", err)
+	}
+	if record.Status != enum.PaymentSucceeded {
+		t.Errorf("payment status = %s, want %s", record.Status, enum.PaymentSucceeded)
+	}
+
+	var events, transitions int64
+	if err := db.Model(&entity.PaymentEvent{}).Where("payment_id = ?", record.ID).Count(&events).Error; err != nil {
+		t.Fatalf("failed to count payment events: %v", err)
+	}
+	if err := db.Model(&entity.OrderStatusHistory{}).
+		Where("order_id = ? AND to_status = ?", order.ID, enum.Paid).
+		Count(&transitions).Error; err != nil {
+		t.Fatalf("failed to count order status changes: %v", err)
+	}
+	if events != 1 || transitions != 1 {
+		t.Errorf("%d payment events and %d PAID transitions recorded, want 1 each", events, transitions)
+	}
+
+	var stored entity.Order
+	if err := db.First(&stored, order.ID).Error; err != nil {
+		t.Fatalf("failed to load order: %v", err)
+	}
+	if stored.Status != enum.Paid {
+		t.Errorf("order status = %s, want %s", stored.Status, enum.Paid)
+	}
+
+	// Event dengan signature yang salah ditolak sebelum menyentuh database
+	err = uc.HandleWebhook(ctx, payload, payment.NewFakeProvider("forged", log).Sign(payload))
+	if fiberErr, ok := err.(*fiber.Error); !ok || fiberErr.Code != fiber.StatusUnauthorized {
+		t.Errorf("HandleWebhook() with a forged signature error = %v, want 401", err)
+	}
+}
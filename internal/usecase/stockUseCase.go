@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type StockUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	BookRepository          *repository.BookRepository
+	StockMovementRepository *repository.StockMovementRepository
+	OutboxRepository        *repository.OutboxRepository
+	LowStockThreshold       int
+}
+
+func NewStockUseCase(db *gorm.DB, logger *logrus.Logger, bookRepository *repository.BookRepository,
+	stockMovementRepository *repository.StockMovementRepository, outboxRepository *repository.OutboxRepository, lowStockThreshold int) *StockUseCase {
+	return &StockUseCase{
+		DB:                      db,
+		Log:                     logger,
+		BookRepository:          bookRepository,
+		StockMovementRepository: stockMovementRepository,
+		OutboxRepository:        outboxRepository,
+		LowStockThreshold:       lowStockThreshold,
+	}
+}
+
+// AdjustStock applies a manual stock correction by an admin and records it in the ledger
+func (uc *StockUseCase) AdjustStock(ctx context.Context, bookID int, req *model.AdjustStockRequest, adminID int) (*model.StockResponse, error) {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Kunci row buku supaya balance_after di ledger konsisten
+	book, err := uc.BookRepository.FindByIDForUpdate(tx, bookID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "book not found")
+		}
+		uc.Log.Error("failed to fetch book: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch book")
+	}
+
+	if book.Stock+req.Change < 0 {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusConflict, fmt.Sprintf("insufficient stock, current stock is %d", book.Stock))
+	}
+
+	if err := uc.BookRepository.IncrementStock(tx, bookID, req.Change); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to adjust stock: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to adjust stock")
+	}
+
+	balance := book.Stock + req.Change
+	if err := uc.StockMovementRepository.Create(tx, &entity.StockMovement{
+		BookID:       bookID,
+		Change:       req.Change,
+		BalanceAfter: balance,
+		Reason:       req.Reason,
+		CreatedBy:    &adminID,
+	}); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to record stock movement: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to adjust stock")
+	}
+
+	if err := publishLowStock(tx, uc.OutboxRepository, uc.LowStockThreshold, book, balance, req.Change); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to record stock event: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to adjust stock")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to adjust stock")
+	}
+
+	return &model.StockResponse{
+		BookID: bookID,
+		Stock:  balance,
+	}, nil
+}
+
+// ListMovements returns the paginated stock ledger of a book
+func (uc *StockUseCase) ListMovements(ctx context.Context, bookID, page, size int) ([]*model.StockMovementResponse, int64, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = 10
+	}
+
+	var book entity.Book
+	if err := uc.BookRepository.FindById(uc.DB.WithContext(ctx), &book, bookID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, 0, fiber.NewError(fiber.StatusNotFound, "book not found")
+		}
+		uc.Log.Error("failed to fetch book: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch book")
+	}
+
+	var movements []entity.StockMovement
+	total, err := uc.StockMovementRepository.PaginateWithSpec(ctx, uc.DB, uc.StockMovementRepository.BookMovementsSpec(bookID), page, size, &movements)
+	if err != nil {
+		uc.Log.Error("failed to list stock movements: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list stock movements")
+	}
+
+	totalPages := (total + int64(size) - 1) / int64(size)
+	return converter.StockMovementsToResponse(movements), total, totalPages, nil
+}
+
+// recordStockMovement mencatat perubahan stok yang sudah diterapkan ke tabel books. Dipanggil
+// langsung setelah perubahan itu supaya balance_after tidak ikut perubahan berikutnya.
+func recordStockMovement(tx *gorm.DB, bookRepository *repository.BookRepository, stockMovementRepository *repository.StockMovementRepository,
+	bookID, change int, reason string, orderID *int, createdBy *int) error {
+	balance, err := bookRepository.GetStock(tx, bookID)
+	if err != nil {
+		return err
+	}
+	return stockMovementRepository.Create(tx, &entity.StockMovement{
+		BookID:       bookID,
+		Change:       change,
+		BalanceAfter: balance,
+		Reason:       reason,
+		OrderID:      orderID,
+		CreatedBy:    createdBy,
+	})
+}
+
+// releaseOrderStock mengembalikan stok yang direservasi oleh sebuah order
+func releaseOrderStock(tx *gorm.DB, bookRepository *repository.BookRepository, stockMovementRepository *repository.StockMovementRepository,
+	order *entity.Order, reason string) error {
+	for _, line := range order.BookOrders {
+		if err := bookRepository.IncrementStock(tx, line.BookID, line.Quantity); err != nil {
+			// Buku yang sudah dihapus tidak perlu dikembalikan stoknya
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+		orderID := order.ID
+		if err := recordStockMovement(tx, bookRepository, stockMovementRepository, line.BookID, line.Quantity, reason, &orderID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishLowStock writes stock.low when a change took the stock from above threshold to at or
+// below it, so an alert is only sent once per crossing. balance is the stock after the change.
+func publishLowStock(tx *gorm.DB, outboxRepository *repository.OutboxRepository, threshold int, book *entity.Book, balance, change int) error {
+	if change >= 0 || balance > threshold || balance-change <= threshold {
+		return nil
+	}
+	return outboxRepository.Append(tx, enum.AggregateBook, book.ID, enum.EventStockLow, &model.StockLowEvent{
+		BookID:    book.ID,
+		Title:     book.Title,
+		Stock:     balance,
+		Threshold: threshold,
+	})
+}
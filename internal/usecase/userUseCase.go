@@ -2,36 +2,54 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"time"
 
 	"github.com/fathirarya/online-bookstore-api/internal/auth"
 	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
 	"github.com/fathirarya/online-bookstore-api/internal/model"
 	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
 	"github.com/fathirarya/online-bookstore-api/internal/repository"
 	"github.com/fathirarya/online-bookstore-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	UserRepository *repository.UserRepository
+	DB                     *gorm.DB
+	Log                    *logrus.Logger
+	UserRepository         *repository.UserRepository
+	RefreshTokenRepository *repository.RefreshTokenRepository
+	RevokedTokenRepository *repository.RevokedTokenRepository
 }
 
-func NewUserUseCase(db *gorm.DB, logger *logrus.Logger, userRepository *repository.UserRepository) *UserUseCase {
+func NewUserUseCase(db *gorm.DB, logger *logrus.Logger, userRepository *repository.UserRepository,
+	refreshTokenRepository *repository.RefreshTokenRepository, revokedTokenRepository *repository.RevokedTokenRepository) *UserUseCase {
 	return &UserUseCase{
-		DB:             db,
-		Log:            logger,
-		UserRepository: userRepository,
+		DB:                     db,
+		Log:                    logger,
+		UserRepository:         userRepository,
+		RefreshTokenRepository: refreshTokenRepository,
+		RevokedTokenRepository: revokedTokenRepository,
 	}
 }
 
-// Register registers a new user with transaction
+// Register registers a new customer with transaction
 func (uc *UserUseCase) Register(ctx context.Context, req *model.RegisterUserRequest) (*model.UserResponse, error) {
+	return uc.CreateUser(ctx, req, enum.RoleCustomer)
+}
+
+// CreateUser creates a user with the given role, used by Register and the admin CLI
+func (uc *UserUseCase) CreateUser(ctx context.Context, req *model.RegisterUserRequest, role string) (*model.UserResponse, error) {
+	if !isRole(role) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid role")
+	}
+
 	tx := uc.DB.WithContext(ctx).Begin()
 	defer func() {
 		if r := recover(); r != nil {
@@ -65,6 +83,7 @@ func (uc *UserUseCase) Register(ctx context.Context, req *model.RegisterUserRequ
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     role,
 	}
 
 	// Save user to database
@@ -101,16 +120,401 @@ func (uc *UserUseCase) Login(ctx context.Context, req *model.LoginUserRequest, j
 		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid email or password")
 	}
 
+	// Dicek setelah password supaya status akun tidak bocor ke orang yang tidak tahu password-nya
+	if user.DisabledAt != nil {
+		return nil, fiber.NewError(fiber.StatusForbidden, "account is disabled")
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Login baru selalu membuka refresh token family baru
+	response, err := uc.issueTokens(tx, user, uuid.NewString(), jwtService)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to generate token")
+	}
+
+	// Return AuthResponse DTO
+	return response, nil
+}
+
+// RefreshToken rotates a refresh token and returns a new access/refresh token pair.
+// Presenting a refresh token that was already rotated revokes the whole family.
+func (uc *UserUseCase) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest, jwtService *auth.JWTService) (*model.AuthResponse, error) {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	current, err := uc.RefreshTokenRepository.FindByHash(tx, auth.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid refresh token")
+		}
+		uc.Log.Error("failed to find refresh token: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	// Reuse detection: token yang sudah dirotasi dipakai lagi, cabut seluruh family
+	if current.RevokedAt != nil {
+		if err := uc.RefreshTokenRepository.RevokeFamily(tx, current.FamilyID); err != nil {
+			tx.Rollback()
+			uc.Log.Error("failed to revoke refresh token family: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+		if err := tx.Commit().Error; err != nil {
+			uc.Log.Error("failed to commit transaction: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+		}
+		uc.Log.Warnf("refresh token reuse detected for user %d, family %s revoked", current.UserID, current.FamilyID)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token has been revoked")
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "refresh token expired")
+	}
+
+	var user entity.User
+	if err := uc.UserRepository.FindById(tx, &user, current.UserID); err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid refresh token")
+		}
+		uc.Log.Error("failed to find user: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+	if user.DisabledAt != nil {
+		tx.Rollback()
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "account is disabled")
+	}
+
+	// Rotasi: token lama dicabut, token baru tetap di family yang sama
+	if err := uc.RefreshTokenRepository.Revoke(tx, current.ID); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to revoke refresh token: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	response, err := uc.issueTokens(tx, &user, current.FamilyID, jwtService)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to refresh token")
+	}
+
+	return response, nil
+}
+
+// Logout revokes the refresh token family and denylists the current access token
+func (uc *UserUseCase) Logout(ctx context.Context, req *model.LogoutUserRequest, userID int, jti string, accessExpiresAt time.Time) error {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	current, err := uc.RefreshTokenRepository.FindByHash(tx, auth.HashRefreshToken(req.RefreshToken))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		uc.Log.Error("failed to find refresh token: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	// Refresh token milik user lain tidak boleh ikut dicabut
+	if current != nil && current.UserID == userID {
+		if err := uc.RefreshTokenRepository.RevokeFamily(tx, current.FamilyID); err != nil {
+			tx.Rollback()
+			uc.Log.Error("failed to revoke refresh token family: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to logout")
+		}
+	}
+
+	if jti != "" {
+		if err := uc.RevokedTokenRepository.Create(tx, &entity.RevokedToken{
+			JTI:       jti,
+			UserID:    userID,
+			ExpiresAt: accessExpiresAt,
+		}); err != nil {
+			tx.Rollback()
+			uc.Log.Error("failed to revoke access token: ", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to logout")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to logout")
+	}
+
+	return nil
+}
+
+// PurgeExpiredTokens removes expired refresh tokens and denylist entries
+func (uc *UserUseCase) PurgeExpiredTokens(ctx context.Context) error {
+	tx := uc.DB.WithContext(ctx)
+
+	if _, err := uc.RefreshTokenRepository.DeleteExpired(tx); err != nil {
+		uc.Log.Error("failed to purge expired refresh tokens: ", err)
+		return err
+	}
+	if _, err := uc.RevokedTokenRepository.DeleteExpired(tx); err != nil {
+		uc.Log.Error("failed to purge expired revoked tokens: ", err)
+		return err
+	}
+	return nil
+}
+
+// issueTokens membuat access token dan refresh token baru dalam family yang diberikan
+func (uc *UserUseCase) issueTokens(tx *gorm.DB, user *entity.User, familyID string, jwtService *auth.JWTService) (*model.AuthResponse, error) {
 	// Generate JWT token
-	token, err := jwtService.GenerateToken(user.ID)
+	token, err := jwtService.GenerateToken(user.ID, user.Role)
 	if err != nil {
 		uc.Log.Error("failed to generate token: ", err)
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to generate token")
 	}
 
+	refreshToken, err := auth.GenerateRefreshToken()
+	if err != nil {
+		uc.Log.Error("failed to generate refresh token: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to generate token")
+	}
+
 	// Token expiry
-	expiresAt := time.Now().Add(jwtService.ExpireDuration())
+	now := time.Now()
+	expiresAt := now.Add(jwtService.ExpireDuration())
+	refreshExpiresAt := now.Add(jwtService.RefreshExpireDuration())
 
-	// Return AuthResponse DTO
-	return converter.AuthToResponse(user, token, expiresAt), nil
+	if err := uc.RefreshTokenRepository.Create(tx, &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashRefreshToken(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+	}); err != nil {
+		uc.Log.Error("failed to store refresh token: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to generate token")
+	}
+
+	return converter.AuthToResponse(user, token, expiresAt, refreshToken, refreshExpiresAt), nil
+}
+
+// PromoteFirstAdmin promotes the user with the given email to admin, but only
+// while no admin exists yet. It is used to bootstrap a fresh installation.
+func (uc *UserUseCase) PromoteFirstAdmin(ctx context.Context, email string) error {
+	totalAdmins, err := uc.UserRepository.CountByRole(ctx, enum.RoleAdmin)
+	if err != nil {
+		uc.Log.Error("failed to count admins: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to count admins")
+	}
+	if totalAdmins > 0 {
+		return nil
+	}
+
+	user, err := uc.UserRepository.FindByEmail(ctx, email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		uc.Log.Error("failed to find user: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	if err := uc.UserRepository.UpdateRole(uc.DB.WithContext(ctx), user.ID, enum.RoleAdmin); err != nil {
+		uc.Log.Error("failed to promote user: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to promote user")
+	}
+
+	uc.Log.Infof("user %s promoted to admin", email)
+	return nil
+}
+
+// ChangeRole promotes or demotes a user. The last active admin cannot be demoted.
+func (uc *UserUseCase) ChangeRole(ctx context.Context, email, role string) (*model.UserResponse, error) {
+	if !isRole(role) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid role")
+	}
+
+	user, err := uc.findUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return converter.UserToResponse(user), nil
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if user.Role == enum.RoleAdmin {
+		if err := uc.ensureNotLastAdmin(tx, user.ID, "cannot demote the last admin"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := uc.UserRepository.UpdateRole(tx, user.ID, role); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to update role: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update role")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update role")
+	}
+
+	user.Role = role
+	uc.Log.Infof("user %s role changed to %s", email, role)
+	return converter.UserToResponse(user), nil
+}
+
+// SetDisabled disables or re-enables a user. Disabling revokes every refresh token, access
+// tokens that were already issued are rejected by JWTProtected. The last active admin cannot
+// be disabled.
+func (uc *UserUseCase) SetDisabled(ctx context.Context, email string, disabled bool) (*model.UserResponse, error) {
+	user, err := uc.findUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	var disabledAt *time.Time
+	if disabled {
+		if user.DisabledAt != nil {
+			return converter.UserToResponse(user), nil
+		}
+		now := time.Now()
+		disabledAt = &now
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if disabled && user.Role == enum.RoleAdmin {
+		if err := uc.ensureNotLastAdmin(tx, user.ID, "cannot disable the last admin"); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := uc.UserRepository.UpdateDisabledAt(tx, user.ID, disabledAt); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to update user: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update user")
+	}
+	if disabled {
+		if err := uc.RefreshTokenRepository.RevokeAllByUser(tx, user.ID); err != nil {
+			tx.Rollback()
+			uc.Log.Error("failed to revoke refresh tokens: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update user")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update user")
+	}
+
+	user.DisabledAt = disabledAt
+	uc.Log.Infof("user %s disabled: %t", email, disabled)
+	return converter.UserToResponse(user), nil
+}
+
+// ResetPassword sets a new password and logs the user out of every session
+func (uc *UserUseCase) ResetPassword(ctx context.Context, email, password string) error {
+	if err := utils.ValidatePassword(password); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	user, err := uc.findUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		uc.Log.Error("failed to hash password: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := uc.UserRepository.UpdatePassword(tx, user.ID, string(hashedPassword)); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to update password: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to reset password")
+	}
+	if err := uc.RefreshTokenRepository.RevokeAllByUser(tx, user.ID); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to revoke refresh tokens: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to reset password")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to reset password")
+	}
+
+	uc.Log.Infof("password of user %s reset", email)
+	return nil
+}
+
+func (uc *UserUseCase) findUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	user, err := uc.UserRepository.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		uc.Log.Error("failed to find user: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "internal server error")
+	}
+	return user, nil
+}
+
+// ensureNotLastAdmin locks the active admins so concurrent demotions and disables wait for each
+// other, then refuses when userID is the only one left
+func (uc *UserUseCase) ensureNotLastAdmin(tx *gorm.DB, userID int, message string) error {
+	adminIDs, err := uc.UserRepository.LockActiveByRole(tx, enum.RoleAdmin)
+	if err != nil {
+		uc.Log.Error("failed to count admins: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to count admins")
+	}
+	if len(adminIDs) <= 1 && slices.Contains(adminIDs, userID) {
+		return fiber.NewError(fiber.StatusConflict, message)
+	}
+	return nil
+}
+
+func isRole(role string) bool {
+	return role == enum.RoleAdmin || role == enum.RoleCustomer
 }
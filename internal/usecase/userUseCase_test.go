@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestLastActiveAdminCannotBeDemotedOrDisabled(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	log := testutil.NewLogger()
+	userRepository := repository.NewUserRepository(db, log)
+	uc := NewUserUseCase(db, log, userRepository, repository.NewRefreshTokenRepository(db, log),
+		repository.NewRevokedTokenRepository(db, log))
+	ctx := context.Background()
+
+	// Admin yang sudah dinonaktifkan tidak dihitung sebagai admin aktif
+	disabledAt := time.Now()
+	for _, user := range []*entity.User{
+		{Name: "Admin", Email: "admin@example.com", Password: "x", Role: enum.RoleAdmin},
+		{Name: "Old Admin", Email: "old-admin@example.com", Password: "x", Role: enum.RoleAdmin, DisabledAt: &disabledAt},
+		{Name: "Customer", Email: "customer@example.com", Password: "x", Role: enum.RoleCustomer},
+	} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+	}
+
+	if _, err := uc.ChangeRole(ctx, "admin@example.com", enum.RoleCustomer); !isConflict(err) {
+		t.Fatalf("ChangeRole() on the last active admin error = %v, want 409", err)
+	}
+	if _, err := uc.SetDisabled(ctx, "admin@example.com", true); !isConflict(err) {
+		t.Fatalf("SetDisabled() on the last active admin error = %v, want 409", err)
+	}
+	if _, err := uc.ChangeRole(ctx, "old-admin@example.com", enum.RoleCustomer); err != nil {
+		t.Fatalf("ChangeRole() on a disabled admin error = %v", err)
+	}
+
+	// Dengan admin aktif kedua, admin pertama boleh diturunkan
+	if _, err := uc.ChangeRole(ctx, "customer@example.com", enum.RoleAdmin); err != nil {
+		t.Fatalf("ChangeRole() promote error = %v", err)
+	}
+	if _, err := uc.ChangeRole(ctx, "admin@example.com", enum.RoleCustomer); err != nil {
+		t.Fatalf("ChangeRole() with another active admin error = %v", err)
+	}
+
+	user, err := uc.SetDisabled(ctx, "admin@example.com", true)
+	if err != nil {
+		t.Fatalf("SetDisabled() error = %v", err)
+	}
+	// JWTProtected menolak access token user yang dinonaktifkan
+	if disabled, err := userRepository.IsDisabled(ctx, user.ID); err != nil || !disabled {
+		t.Errorf("IsDisabled() = %v, %v, want true", disabled, err)
+	}
+	if _, err := uc.SetDisabled(ctx, "admin@example.com", false); err != nil {
+		t.Fatalf("SetDisabled() enable error = %v", err)
+	}
+	if disabled, err := userRepository.IsDisabled(ctx, user.ID); err != nil || disabled {
+		t.Errorf("IsDisabled() after enabling = %v, %v, want false", disabled, err)
+	}
+}
+
+func isConflict(err error) bool {
+	fiberErr, ok := err.(*fiber.Error)
+	return ok && fiberErr.Code == fiber.StatusConflict
+}
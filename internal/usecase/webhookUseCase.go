@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/model"
+	"github.com/fathirarya/online-bookstore-api/internal/model/converter"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/webhook"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type WebhookUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	WebhookRepository *repository.WebhookRepository
+	Deliverer         *webhook.Deliverer
+}
+
+func NewWebhookUseCase(db *gorm.DB, logger *logrus.Logger, webhookRepository *repository.WebhookRepository, deliverer *webhook.Deliverer) *WebhookUseCase {
+	return &WebhookUseCase{
+		DB:                db,
+		Log:               logger,
+		WebhookRepository: webhookRepository,
+		Deliverer:         deliverer,
+	}
+}
+
+// CreateWebhook registers a partner endpoint, the generated secret is only returned here
+// and when it is rotated
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		uc.Log.Error("failed to generate webhook secret: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create webhook")
+	}
+
+	subscription := &entity.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  joinEventTypes(req.EventTypes),
+		Description: req.Description,
+		Active:      true,
+	}
+	if err := uc.WebhookRepository.Create(uc.DB.WithContext(ctx), subscription); err != nil {
+		uc.Log.Error("failed to create webhook: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to create webhook")
+	}
+
+	response := converter.WebhookToResponse(subscription)
+	response.Secret = secret
+	return response, nil
+}
+
+func (uc *WebhookUseCase) ListWebhooks(ctx context.Context) ([]*model.WebhookResponse, error) {
+	subscriptions, err := uc.WebhookRepository.FindAll(uc.DB.WithContext(ctx))
+	if err != nil {
+		uc.Log.Error("failed to list webhooks: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to list webhooks")
+	}
+	return converter.WebhooksToResponse(subscriptions), nil
+}
+
+func (uc *WebhookUseCase) GetWebhook(ctx context.Context, id int) (*model.WebhookResponse, error) {
+	subscription, err := uc.findWebhook(uc.DB.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return converter.WebhookToResponse(subscription), nil
+}
+
+func (uc *WebhookUseCase) UpdateWebhook(ctx context.Context, id int, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	db := uc.DB.WithContext(ctx)
+	subscription, err := uc.findWebhook(db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.URL = req.URL
+	subscription.EventTypes = joinEventTypes(req.EventTypes)
+	subscription.Description = req.Description
+	if req.Active != nil {
+		// Diaktifkan lagi oleh admin, hitungan kegagalan dimulai dari nol
+		if *req.Active && !subscription.Active {
+			subscription.ConsecutiveFailures = 0
+			subscription.DisabledAt = nil
+			subscription.DisabledReason = ""
+		}
+		subscription.Active = *req.Active
+	}
+
+	var secret string
+	if req.RotateSecret {
+		if secret, err = newWebhookSecret(); err != nil {
+			uc.Log.Error("failed to generate webhook secret: ", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update webhook")
+		}
+		subscription.Secret = secret
+	}
+
+	if err := uc.WebhookRepository.Update(db, subscription); err != nil {
+		uc.Log.Error("failed to update webhook: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to update webhook")
+	}
+
+	response := converter.WebhookToResponse(subscription)
+	response.Secret = secret
+	return response, nil
+}
+
+// DeleteWebhook removes the subscription with its delivery log
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, id int) error {
+	tx := uc.DB.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	subscription, err := uc.findWebhook(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := uc.WebhookRepository.DeleteWithDeliveries(tx, subscription); err != nil {
+		tx.Rollback()
+		uc.Log.Error("failed to delete webhook: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete webhook")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		uc.Log.Error("failed to commit transaction: ", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "failed to delete webhook")
+	}
+	return nil
+}
+
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, id int, req *model.ListWebhookDeliveriesRequest) ([]*model.WebhookDeliveryResponse, int64, int64, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 {
+		req.Size = 20
+	}
+
+	db := uc.DB.WithContext(ctx)
+	if _, err := uc.findWebhook(db, id); err != nil {
+		return nil, 0, 0, err
+	}
+
+	deliveries, total, err := uc.WebhookRepository.PaginateDeliveries(db, id, req.Status, req.Page, req.Size)
+	if err != nil {
+		uc.Log.Error("failed to list webhook deliveries: ", err)
+		return nil, 0, 0, fiber.NewError(fiber.StatusInternalServerError, "failed to list webhook deliveries")
+	}
+
+	totalPages := (total + int64(req.Size) - 1) / int64(req.Size)
+	return converter.WebhookDeliveriesToResponse(deliveries), total, totalPages, nil
+}
+
+// GetDelivery returns a delivery with its payload and every attempt
+func (uc *WebhookUseCase) GetDelivery(ctx context.Context, id, deliveryID int) (*model.WebhookDeliveryResponse, error) {
+	db := uc.DB.WithContext(ctx)
+	delivery, err := uc.findDelivery(db, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := uc.WebhookRepository.FindAttempts(db, delivery.ID)
+	if err != nil {
+		uc.Log.Error("failed to fetch webhook attempts: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch webhook delivery")
+	}
+	return converter.WebhookDeliveryDetailToResponse(delivery, attempts), nil
+}
+
+// Redeliver sends a delivery again right away, whatever its status, and returns the result.
+// A failed request is not an error of this endpoint, the attempt shows the response.
+func (uc *WebhookUseCase) Redeliver(ctx context.Context, id, deliveryID int) (*model.WebhookDeliveryResponse, error) {
+	delivery, err := uc.findDelivery(uc.DB.WithContext(ctx), id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.Deliverer.Send(ctx, delivery, true); err != nil {
+		uc.Log.Error("failed to redeliver webhook: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to redeliver webhook")
+	}
+
+	return uc.GetDelivery(ctx, id, deliveryID)
+}
+
+func (uc *WebhookUseCase) findWebhook(db *gorm.DB, id int) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	if err := uc.WebhookRepository.FindById(db, &subscription, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "webhook not found")
+		}
+		uc.Log.Error("failed to fetch webhook: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch webhook")
+	}
+	return &subscription, nil
+}
+
+func (uc *WebhookUseCase) findDelivery(db *gorm.DB, id, deliveryID int) (*entity.WebhookDelivery, error) {
+	delivery, err := uc.WebhookRepository.FindDelivery(db, id, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "webhook delivery not found")
+		}
+		uc.Log.Error("failed to fetch webhook delivery: ", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "failed to fetch webhook delivery")
+	}
+	return delivery, nil
+}
+
+// validateWebhookURL hanya menerima http dan https, http dibolehkan untuk stub lokal
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "url must be an http or https URL")
+	}
+	return nil
+}
+
+// joinEventTypes menyimpan event type tanpa duplikat, dipisah koma
+func joinEventTypes(eventTypes []string) string {
+	seen := make(map[string]bool, len(eventTypes))
+	unique := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			unique = append(unique, eventType)
+		}
+	}
+	return strings.Join(unique, ",")
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
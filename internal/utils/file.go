@@ -1,18 +1,16 @@
 package utils
 
-import (
-	"encoding/base64"
-	"io"
-	"mime/multipart"
-)
-
-func FileToBase64(file multipart.File) (string, error) {
-	defer file.Close()
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
 
-	fileBytes, err := io.ReadAll(file)
-	if err != nil {
-		return "", err
+// ImageExtension mengembalikan ekstensi file untuk content type gambar
+func ImageExtension(contentType string) string {
+	if ext, ok := imageExtensions[contentType]; ok {
+		return ext
 	}
-
-	return base64.StdEncoding.EncodeToString(fileBytes), nil
+	return ".bin"
 }
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/outbox"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Header yang dikirim ke endpoint partner
+const (
+	HeaderDeliveryID = "X-Webhook-ID"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+// maxResponseBody adalah batas body response yang disimpan di delivery log
+const maxResponseBody = 1 << 10
+
+// Options mengatur pengiriman webhook, dibaca dari env di config.NewWebhookOptions
+type Options struct {
+	Timeout      time.Duration
+	MaxAttempts  int // setelah ini delivery ditandai FAILED
+	DisableAfter int // subscription dinonaktifkan setelah sekian kegagalan berturut-turut
+	Concurrency  int
+	BatchSize    int
+	PollInterval time.Duration
+	Backoff      time.Duration // jeda retry pertama, dikali dua setiap gagal
+	MaxBackoff   time.Duration
+}
+
+// Deliverer sends queued deliveries to partner endpoints with retries
+type Deliverer struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	WebhookRepository *repository.WebhookRepository
+	Client            *http.Client
+	Options           Options
+}
+
+func NewDeliverer(db *gorm.DB, log *logrus.Logger, webhookRepository *repository.WebhookRepository, options Options) *Deliverer {
+	return &Deliverer{
+		DB:                db,
+		Log:               log,
+		WebhookRepository: webhookRepository,
+		Client:            &http.Client{Timeout: options.Timeout},
+		Options:           options,
+	}
+}
+
+// Run polls for due deliveries every PollInterval until duration has passed or ctx is done
+func (d *Deliverer) Run(ctx context.Context, duration time.Duration) error {
+	deadline := time.Now().Add(duration)
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			return err
+		}
+		if time.Now().Add(d.Options.PollInterval).After(deadline) {
+			return nil
+		}
+		select {
+		case <-time.After(d.Options.PollInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Dispatch sends every due delivery, up to Concurrency requests at a time. It stops when the
+// result of a delivery cannot be saved, the delivery is still due and fetching the next batch
+// would post it to the partner again.
+func (d *Deliverer) Dispatch(ctx context.Context) error {
+	for ctx.Err() == nil {
+		deliveries, err := d.WebhookRepository.FindDueDeliveries(d.DB.WithContext(ctx), d.Options.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var recordErr error
+		slots := make(chan struct{}, d.Options.Concurrency)
+		for i := range deliveries {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(delivery *entity.WebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				if _, err := d.Send(ctx, delivery, false); err != nil && ctx.Err() == nil {
+					d.Log.Errorf("failed to record webhook delivery %d: %v", delivery.ID, err)
+					mu.Lock()
+					if recordErr == nil {
+						recordErr = fmt.Errorf("failed to record webhook delivery %d: %w", delivery.ID, err)
+					}
+					mu.Unlock()
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if recordErr != nil {
+			return recordErr
+		}
+		if len(deliveries) < d.Options.BatchSize {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Send makes one attempt for a delivery and records the result. delivery.Subscription must be
+// loaded. When ctx ends during the request nothing is recorded and the delivery stays due.
+func (d *Deliverer) Send(ctx context.Context, delivery *entity.WebhookDelivery, manual bool) (*entity.WebhookDeliveryAttempt, error) {
+	attempt, sendErr := d.post(ctx, delivery)
+	if sendErr != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	attempt.Manual = manual
+
+	delivery.Attempts++
+	delivery.ResponseCode = attempt.ResponseCode
+	if sendErr == nil {
+		now := time.Now()
+		delivery.Status = enum.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		attempt.Error = sendErr.Error()
+		delivery.LastError = sendErr.Error()
+		if delivery.Attempts < d.Options.MaxAttempts {
+			delivery.Status = enum.WebhookDeliveryPending
+			delivery.NextAttemptAt = time.Now().Add(d.backoff(delivery.Attempts))
+		} else {
+			delivery.Status = enum.WebhookDeliveryFailed
+		}
+	}
+
+	// Tanpa ctx request supaya hasil tetap tercatat
+	err := d.DB.Transaction(func(tx *gorm.DB) error {
+		if err := d.WebhookRepository.CreateAttempt(tx, attempt); err != nil {
+			return err
+		}
+		if err := d.WebhookRepository.UpdateDelivery(tx, delivery); err != nil {
+			return err
+		}
+		if sendErr == nil {
+			return d.WebhookRepository.RecordSuccess(tx, delivery.SubscriptionID)
+		}
+		disabled, err := d.WebhookRepository.RecordFailure(tx, delivery.SubscriptionID, d.Options.DisableAfter)
+		if err != nil {
+			return err
+		}
+		if disabled {
+			d.Log.Warnf("webhook subscription %d disabled after %d consecutive failures", delivery.SubscriptionID, d.Options.DisableAfter)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// post sends the payload signed with the subscription secret. The attempt is always returned,
+// the error is set when the request failed or the response is not 2xx.
+func (d *Deliverer) post(ctx context.Context, delivery *entity.WebhookDelivery) (*entity.WebhookDeliveryAttempt, error) {
+	attempt := &entity.WebhookDeliveryAttempt{DeliveryID: delivery.ID}
+	started := time.Now()
+	defer func() { attempt.DurationMs = time.Since(started).Milliseconds() }()
+
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(started.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return attempt, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "online-bookstore-webhooks/1.0")
+	req.Header.Set(HeaderDeliveryID, strconv.Itoa(delivery.ID))
+	req.Header.Set(outbox.HeaderEventID, delivery.EventID)
+	req.Header.Set(outbox.HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Signature(delivery.Subscription.Secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return attempt, err
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	attempt.ResponseCode = &code
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.ResponseBody = string(responseBody)
+
+	if code < 200 || code > 299 {
+		return attempt, errors.New("unexpected status " + strconv.Itoa(code))
+	}
+	return attempt, nil
+}
+
+func (d *Deliverer) backoff(attempts int) time.Duration {
+	backoff := d.Options.Backoff << (attempts - 1)
+	if backoff > d.Options.MaxBackoff || backoff <= 0 {
+		return d.Options.MaxBackoff
+	}
+	return backoff
+}
+
+// Signature is "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Partners recompute it with their secret and reject old timestamps to block replays.
+func Signature(secret, timestamp string, body []byte) string {
+	return "sha256=" + outbox.Sign(secret, append([]byte(timestamp+"."), body...))
+}
@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"github.com/fathirarya/online-bookstore-api/internal/testutil"
+	"gorm.io/gorm"
+)
+
+// partner is a webhook endpoint that checks the signature the way partners are told to
+type partner struct {
+	secret string
+	status int
+
+	mu       sync.Mutex
+	requests int
+	invalid  int
+}
+
+func (p *partner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write([]byte(r.Header.Get(HeaderTimestamp) + "." + string(body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests++
+	if !hmac.Equal([]byte(expected), []byte(r.Header.Get(HeaderSignature))) || r.Header.Get(HeaderDeliveryID) == "" {
+		p.invalid++
+	}
+	w.WriteHeader(p.status)
+}
+
+func (p *partner) counts() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests, p.invalid
+}
+
+func newTestDeliverer(db *gorm.DB) *Deliverer {
+	log := testutil.NewLogger()
+	return NewDeliverer(db, log, repository.NewWebhookRepository(db, log), Options{
+		Timeout:      5 * time.Second,
+		MaxAttempts:  3,
+		DisableAfter: 2,
+		Concurrency:  1, // SQLite in-memory tidak menerima tulis paralel
+		BatchSize:    10,
+		PollInterval: time.Second,
+		Backoff:      time.Minute,
+		MaxBackoff:   time.Hour,
+	})
+}
+
+func createSubscription(t *testing.T, db *gorm.DB, url, secret string, deliveries int) *entity.WebhookSubscription {
+	t.Helper()
+	subscription := &entity.WebhookSubscription{URL: url, Secret: secret, EventTypes: "order.created", Active: true}
+	if err := db.Create(subscription).Error; err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+	for i := 0; i < deliveries; i++ {
+		if err := db.Create(&entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        fmt.Sprintf("event-%d-%d", subscription.ID, i),
+			EventType:      "order.created",
+			Payload:        `{"id":1,"status":"PENDING"}`,
+			Status:         enum.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now().Add(-time.Second),
+		}).Error; err != nil {
+			t.Fatalf("failed to create delivery: %v", err)
+		}
+	}
+	return subscription
+}
+
+func TestDeliverySignsTimestampAndBody(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	endpoint := &partner{secret: "partner-secret", status: http.StatusNoContent}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	subscription := createSubscription(t, db, server.URL, endpoint.secret, 1)
+
+	if err := newTestDeliverer(db).Dispatch(context.Background()); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if requests, invalid := endpoint.counts(); requests != 1 || invalid != 0 {
+		t.Fatalf("partner got %d requests, %d with an invalid signature, want 1 valid", requests, invalid)
+	}
+	var delivery entity.WebhookDelivery
+	if err := db.Where("subscription_id = ?", subscription.ID).First(&delivery).Error; err != nil {
+		t.Fatalf("failed to load delivery: %v", err)
+	}
+	if delivery.Status != enum.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+		t.Errorf("delivery = %s after %d attempts, want SUCCEEDED after 1", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestFailedDeliveriesBackOffAndDisableTheSubscription(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	endpoint := &partner{secret: "partner-secret", status: http.StatusInternalServerError}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	subscription := createSubscription(t, db, server.URL, endpoint.secret, 2)
+	deliverer := newTestDeliverer(db)
+	ctx := context.Background()
+
+	started := time.Now()
+	if err := deliverer.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	var deliveries []entity.WebhookDelivery
+	if err := db.Where("subscription_id = ?", subscription.ID).Order("id").Find(&deliveries).Error; err != nil {
+		t.Fatalf("failed to load deliveries: %v", err)
+	}
+	for _, delivery := range deliveries {
+		if delivery.Status != enum.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseCode == nil || *delivery.ResponseCode != 500 {
+			t.Fatalf("delivery %d = %s after %d attempts, want PENDING after 1 with response 500", delivery.ID, delivery.Status, delivery.Attempts)
+		}
+		// Retry pertama dijadwalkan setelah Backoff
+		if wait := delivery.NextAttemptAt.Sub(started); wait < time.Minute || wait > time.Minute+10*time.Second {
+			t.Errorf("delivery %d retries after %s, want about 1m", delivery.ID, wait)
+		}
+	}
+
+	// Dua kegagalan berturut-turut mencapai DisableAfter
+	if err := db.First(subscription, subscription.ID).Error; err != nil {
+		t.Fatalf("failed to load subscription: %v", err)
+	}
+	if subscription.Active || subscription.DisabledAt == nil || subscription.ConsecutiveFailures != 2 {
+		t.Fatalf("subscription active = %v after %d failures, want disabled after 2", subscription.Active, subscription.ConsecutiveFailures)
+	}
+
+	// Subscription nonaktif tidak lagi dikirimi walaupun retry sudah jatuh tempo
+	if err := db.Model(&entity.WebhookDelivery{}).Where("subscription_id = ?", subscription.ID).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatalf("failed to reschedule deliveries: %v", err)
+	}
+	if err := deliverer.Dispatch(ctx); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+	if requests, invalid := endpoint.counts(); requests != 2 || invalid != 0 {
+		t.Errorf("partner got %d requests, %d with an invalid signature, want 2 valid", requests, invalid)
+	}
+}
+
+func TestDispatchStopsWhenAResultCannotBeSaved(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	endpoint := &partner{secret: "partner-secret", status: http.StatusNoContent}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	createSubscription(t, db, server.URL, endpoint.secret, 2)
+	deliverer := newTestDeliverer(db)
+	deliverer.Options.BatchSize = 1
+
+	// Attempt tidak bisa disimpan, delivery tetap PENDING dan jatuh tempo
+	if err := db.Migrator().DropTable(&entity.WebhookDeliveryAttempt{}); err != nil {
+		t.Fatalf("failed to drop attempts table: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- deliverer.Dispatch(context.Background()) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("Dispatch() error = nil, want the record error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Dispatch() kept posting the same delivery")
+	}
+
+	// Batch berikutnya tidak diambil, partner hanya menerima satu request
+	if requests, _ := endpoint.counts(); requests != 1 {
+		t.Errorf("partner got %d requests, want 1", requests)
+	}
+}
+
+func TestSendMarksTheLastAttemptFailed(t *testing.T) {
+	db := testutil.NewDatabase(t)
+	endpoint := &partner{secret: "partner-secret", status: http.StatusBadGateway}
+	server := httptest.NewServer(endpoint)
+	defer server.Close()
+	subscription := createSubscription(t, db, server.URL, endpoint.secret, 1)
+	deliverer := newTestDeliverer(db)
+
+	var delivery entity.WebhookDelivery
+	if err := db.Joins("Subscription").Where("webhook_deliveries.subscription_id = ?", subscription.ID).Take(&delivery).Error; err != nil {
+		t.Fatalf("failed to load delivery: %v", err)
+	}
+
+	started := time.Now()
+	for attempt := 1; attempt <= deliverer.Options.MaxAttempts; attempt++ {
+		if _, err := deliverer.Send(context.Background(), &delivery, false); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if attempt < deliverer.Options.MaxAttempts {
+			// Jeda dikali dua setiap gagal: 1m, 2m, ...
+			want := deliverer.Options.Backoff << (attempt - 1)
+			if wait := delivery.NextAttemptAt.Sub(started); delivery.Status != enum.WebhookDeliveryPending || wait < want || wait > want+10*time.Second {
+				t.Fatalf("attempt %d: %s, retry after %s, want PENDING after %s", attempt, delivery.Status, wait, want)
+			}
+		}
+	}
+	if delivery.Status != enum.WebhookDeliveryFailed || delivery.Attempts != deliverer.Options.MaxAttempts {
+		t.Errorf("delivery = %s after %d attempts, want FAILED after %d", delivery.Status, delivery.Attempts, deliverer.Options.MaxAttempts)
+	}
+
+	var attempts int64
+	if err := db.Model(&entity.WebhookDeliveryAttempt{}).Where("delivery_id = ?", delivery.ID).Count(&attempts).Error; err != nil {
+		t.Fatalf("failed to count attempts: %v", err)
+	}
+	if attempts != int64(deliverer.Options.MaxAttempts) {
+		t.Errorf("%d attempts recorded, want %d", attempts, deliverer.Options.MaxAttempts)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	deliverer := &Deliverer{Options: Options{Backoff: 30 * time.Second, MaxBackoff: 6 * time.Hour}}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 30 * time.Second << 9},
+		{11, 6 * time.Hour},
+		{80, 6 * time.Hour}, // shift overflow
+	}
+	for _, tt := range tests {
+		if got := deliverer.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fathirarya/online-bookstore-api/internal/entity"
+	"github.com/fathirarya/online-bookstore-api/internal/enum"
+	"github.com/fathirarya/online-bookstore-api/internal/outbox"
+	"github.com/fathirarya/online-bookstore-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Sink is the outbox sink that queues a delivery for every active subscription of an event.
+// Sending happens in the Deliverer so a slow partner never holds back the outbox.
+type Sink struct {
+	DB                *gorm.DB
+	WebhookRepository *repository.WebhookRepository
+}
+
+func NewSink(db *gorm.DB, webhookRepository *repository.WebhookRepository) *Sink {
+	return &Sink{
+		DB:                db,
+		WebhookRepository: webhookRepository,
+	}
+}
+
+func (s *Sink) Name() string {
+	return "webhooks"
+}
+
+func (s *Sink) Deliver(ctx context.Context, event outbox.Event) error {
+	if !slices.Contains(enum.WebhookEventTypes, event.Type) {
+		return nil
+	}
+
+	db := s.DB.WithContext(ctx)
+	subscriptions, err := s.WebhookRepository.FindActive(db)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	var deliveries []entity.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !Subscribed(&subscription, event.Type) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(body),
+			Status:         enum.WebhookDeliveryPending,
+			NextAttemptAt:  time.Now(),
+		})
+	}
+	return s.WebhookRepository.CreateDeliveries(db, deliveries)
+}
+
+// Subscribed reports whether the subscription listens to eventType
+func Subscribed(subscription *entity.WebhookSubscription, eventType string) bool {
+	return slices.Contains(strings.Split(subscription.EventTypes, ","), eventType)
+}